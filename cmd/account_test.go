@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// writingProfileCommands take --profile as a writing profile
+var writingProfileCommands = map[string]bool{
+	"toneclone write":                 true,
+	"toneclone compare":               true,
+	"toneclone lint":                  true,
+	"toneclone profiles associate":    true,
+	"toneclone profiles disassociate": true,
+}
+
+// deprecatedProfileCommands keep a local --profile naming an account
+var deprecatedProfileCommands = map[string]bool{
+	"toneclone auth switch": true,
+}
+
+func allCommands(root *cobra.Command) []*cobra.Command {
+	commands := []*cobra.Command{root}
+	for _, child := range root.Commands() {
+		commands = append(commands, allCommands(child)...)
+	}
+	return commands
+}
+
+// resetFlags restores flags changed by a test to their defaults
+func resetFlags(t *testing.T, flagSets ...*pflag.FlagSet) {
+	t.Cleanup(func() {
+		for _, flags := range flagSets {
+			flags.VisitAll(func(f *pflag.Flag) {
+				if f.Changed {
+					f.Value.Set(f.DefValue)
+					f.Changed = false
+				}
+			})
+		}
+	})
+}
+
+func TestProfileFlagMeaningPerCommand(t *testing.T) {
+	for _, c := range allCommands(rootCmd) {
+		path := c.CommandPath()
+		local := c.LocalNonPersistentFlags().Lookup("profile")
+
+		switch {
+		case writingProfileCommands[path]:
+			if local == nil {
+				t.Errorf("%s: expected a local --profile for writing profiles", path)
+			} else if strings.Contains(local.Usage, "account") {
+				t.Errorf("%s: --profile usage should describe writing profiles, got %q", path, local.Usage)
+			}
+		case deprecatedProfileCommands[path]:
+			if local == nil || local.Deprecated == "" {
+				t.Errorf("%s: expected a deprecated local --profile", path)
+			}
+		default:
+			if local != nil {
+				t.Errorf("%s: unexpected local --profile; it would shadow the account flag", path)
+			}
+		}
+
+		if c != rootCmd {
+			if c.LocalNonPersistentFlags().Lookup("account") != nil {
+				t.Errorf("%s: must not define its own --account", path)
+			}
+			if c.InheritedFlags().Lookup("account") == nil {
+				t.Errorf("%s: expected the global --account flag", path)
+			}
+		}
+	}
+
+	if rootCmd.PersistentFlags().Lookup("profile").Deprecated == "" {
+		t.Error("Expected the global --profile flag to be deprecated")
+	}
+}
+
+func TestWriteProfileDoesNotSelectAccount(t *testing.T) {
+	resetFlags(t, writeCmd.Flags(), rootCmd.PersistentFlags())
+
+	if err := writeCmd.ParseFlags([]string{"--profile", "Email", "--account", "work"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if writeProfile != "Email" {
+		t.Errorf("Expected writing profile Email, got %q", writeProfile)
+	}
+	if account != "work" {
+		t.Errorf("Expected account work, got %q", account)
+	}
+	if profile != "" {
+		t.Errorf("Expected the deprecated account flag to stay unset, got %q", profile)
+	}
+}
+
+func TestDeprecatedProfileStillSelectsAccount(t *testing.T) {
+	resetFlags(t, listPersonasCmd.Flags(), rootCmd.PersistentFlags())
+
+	if err := listPersonasCmd.ParseFlags([]string{"--profile", "work"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if profile != "work" || account != "" {
+		t.Errorf("Expected --profile to select the account, got profile=%q account=%q", profile, account)
+	}
+}
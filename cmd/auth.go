@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -13,7 +14,6 @@ import (
 	"golang.org/x/term"
 
 	"github.com/toneclone/cli/internal/config"
-	"github.com/toneclone/cli/pkg/client"
 )
 
 var (
@@ -23,6 +23,9 @@ var (
 	fromStdin      bool
 	force          bool
 	skipValidation bool
+	keyStore       string
+	keyCommand     string
+	deviceLogin    bool
 )
 
 // authCmd represents the auth command
@@ -37,6 +40,7 @@ This command group allows you to:
 - List configured API keys
 - Switch between different API key profiles
 - Check authentication status
+- Move stored keys into the OS keyring or an encrypted file
 
 Examples:
   toneclone auth login                     # Interactive login
@@ -45,13 +49,14 @@ Examples:
   toneclone auth logout                    # Remove default profile
   toneclone auth list                      # List all configured profiles
   toneclone auth status                    # Check current authentication
-  toneclone auth switch --profile prod     # Switch to 'prod' profile`,
+  toneclone auth switch prod               # Switch to 'prod' account
+  toneclone auth migrate --store keyring   # Move plaintext keys to the keyring`,
 }
 
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
 	Use:   "login",
-	Short: "Login with an API key",
+	Short: "Login with an API key or in the browser",
 	Long: `Login with a ToneClone API key.
 
 You can provide the API key in several ways:
@@ -62,10 +67,22 @@ You can provide the API key in several ways:
 
 The API key will be validated before being saved to your configuration.
 
+With --device, no key is needed: the CLI shows a one-time code to enter in
+a browser on any device, then saves the access and refresh tokens it is
+granted. Access tokens are refreshed automatically when they expire.
+
+By default the key is saved in the config file. Use --store (or the
+credential_store config setting) to keep it in the OS keyring or an
+encrypted file instead, or --key-command to fetch it from a password
+manager each time it is needed.
+
 Examples:
   toneclone auth login
   toneclone auth login --key tc_live_abc123 --name production
   toneclone auth login --from-stdin --name ci-cd
+  toneclone auth login --name prod --store keyring
+  toneclone auth login --device --name work
+  toneclone auth login --name prod --key-command "op read op://Private/ToneClone/key"
   echo "tc_live_abc123" | toneclone auth login --from-stdin`,
 	RunE: runLogin,
 }
@@ -95,8 +112,9 @@ var listCmd = &cobra.Command{
 
 Shows profile names, base URLs, and which profile is currently default.
 
-Example:
-  toneclone auth list`,
+Examples:
+  toneclone auth list
+  toneclone auth list -q`,
 	RunE: runList,
 }
 
@@ -112,22 +130,45 @@ Shows:
 - Base URL
 - Connection status
 - User information (if available)
+- Scopes granted to the API key
 
 Example:
   toneclone auth status`,
 	RunE: runStatus,
 }
 
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [profile-name]...",
+	Short: "Move plaintext API keys into a credential store",
+	Long: `Move API keys stored in plaintext in the config file into a credential store.
+The config file keeps only a reference to each key.
+
+Stores:
+  keyring         OS keyring (Secret Service on Linux, Keychain on macOS)
+  encrypted-file  ~/.toneclone/credentials.enc, encrypted with a passphrase
+                  (prompted, or read from TONECLONE_PASSPHRASE)
+
+If no profile names are given, all plaintext profiles are migrated.
+
+Examples:
+  toneclone auth migrate --store keyring
+  toneclone auth migrate production --store encrypted-file`,
+	RunE: runMigrate,
+}
+
 // switchCmd represents the switch command
 var switchCmd = &cobra.Command{
-	Use:   "switch",
+	Use:   "switch <account>",
 	Short: "Switch to a different API key profile",
-	Long: `Switch the default API key profile.
+	Long: `Switch the default API key profile (account).
 
-Changes which profile is used by default when no --profile flag is specified.
+Changes which account is used when neither --account nor TONECLONE_ACCOUNT
+is given.
 
 Example:
-  toneclone auth switch --profile production`,
+  toneclone auth switch production`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runSwitch,
 }
 
@@ -140,6 +181,7 @@ func init() {
 	authCmd.AddCommand(listCmd)
 	authCmd.AddCommand(statusCmd)
 	authCmd.AddCommand(switchCmd)
+	authCmd.AddCommand(migrateCmd)
 
 	// Login flags
 	loginCmd.Flags().StringVar(&keyName, "name", "", "name for this API key profile")
@@ -148,13 +190,28 @@ func init() {
 	loginCmd.Flags().BoolVar(&fromStdin, "from-stdin", false, "read API key from stdin (useful for CI/CD)")
 	loginCmd.Flags().BoolVar(&force, "force", false, "overwrite existing profile without confirmation")
 	loginCmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "skip API key validation (useful for development)")
+	loginCmd.Flags().StringVar(&keyStore, "store", "", "credential store for the key: keyring, encrypted-file (default: credential_store setting, else config file)")
+	loginCmd.Flags().StringVar(&keyCommand, "key-command", "", "shell command that prints the API key (e.g. a password manager CLI)")
+	loginCmd.MarkFlagsMutuallyExclusive("store", "key-command")
+	loginCmd.Flags().BoolVar(&deviceLogin, "device", false, "log in through the browser with a one-time code instead of an API key")
+	loginCmd.MarkFlagsMutuallyExclusive("from-stdin", "key-command")
+	loginCmd.MarkFlagsMutuallyExclusive("device", "from-stdin", "key-command")
+
+	// List flags
+	listCmd.Flags().BoolVarP(&outputQuiet, "quiet", "q", false, "print only profile names, one per line")
+
+	// Migrate flags
+	migrateCmd.Flags().StringVar(&keyStore, "store", "", "credential store to move keys into: keyring, encrypted-file (default: credential_store setting, else keyring)")
+
+	// Prompt for the encrypted file passphrase when it is not in the environment
+	config.PassphraseFunc = promptForPassphrase
 
 	// Logout flags
 	logoutCmd.Flags().Bool("all", false, "remove all profiles")
 
 	// Switch flags
 	switchCmd.Flags().String("profile", "", "profile name to switch to")
-	switchCmd.MarkFlagRequired("profile")
+	switchCmd.Flags().MarkDeprecated("profile", "pass the account name as an argument instead")
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
@@ -166,9 +223,18 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		cfg = config.NewConfig()
 	}
 
+	if deviceLogin {
+		return runDeviceLogin(ctx, cfg)
+	}
+
 	// Get API key
 	var apiKey string
-	if fromStdin {
+	if keyCommand != "" {
+		apiKey, err = config.APIKeyConfig{Store: config.StoreCommand, KeyCommand: keyCommand}.ResolveKey("")
+		if err != nil {
+			return err
+		}
+	} else if fromStdin {
 		apiKey, err = readAPIKeyFromStdin()
 		if err != nil {
 			return fmt.Errorf("failed to read API key from stdin: %w", err)
@@ -183,57 +249,32 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Determine profile name
-	profileName := keyName
-	if profileName == "" {
-		if fromStdin {
-			profileName = "default"
-		} else {
-			profileName, err = promptForProfileName()
-			if err != nil {
-				return fmt.Errorf("failed to get profile name: %w", err)
-			}
-		}
-	}
-
-	// Check if profile already exists
-	if _, exists := cfg.Keys[profileName]; exists && !force {
-		if !fromStdin {
-			fmt.Printf("Profile '%s' already exists. Overwrite? (y/N): ", profileName)
-			scanner := bufio.NewScanner(os.Stdin)
-			scanner.Scan()
-			if strings.ToLower(strings.TrimSpace(scanner.Text())) != "y" {
-				return fmt.Errorf("aborted")
-			}
-		} else {
-			return fmt.Errorf("profile '%s' already exists, use --force to overwrite", profileName)
-		}
+	profileName, err := loginProfileName(cfg)
+	if err != nil {
+		return err
 	}
 
-	// Determine base URL
-	apiBaseURL := baseURL
-	if apiBaseURL == "" {
-		// Check environment variable first
-		if envURL := os.Getenv("TONECLONE_BASE_URL"); envURL != "" {
-			apiBaseURL = envURL
-		} else {
-			apiBaseURL = cfg.DefaultBaseURL
-		}
+	loginKey, envSelection, err := loginTarget(cfg, apiKey)
+	if err != nil {
+		return err
 	}
 
 	// Validate API key (unless skipped)
 	if !skipValidation {
 		fmt.Print("Validating API key...")
-		testClient := client.NewToneCloneClient(apiKey, client.WithBaseURL(apiBaseURL))
+		testClient, err := newAPIClient(loginKey, 10*time.Second)
+		if err != nil {
+			return err
+		}
 
 		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
 		if err := testClient.ValidateConnection(ctx); err != nil {
-			fmt.Println(" ✗")
+			fmt.Println(emoji(" ✗", " failed"))
 			return fmt.Errorf("API key validation failed: %w", err)
 		}
-		fmt.Println(" ✓")
+		fmt.Println(emoji(" ✓", " OK"))
 
 		// Get user info for confirmation
 		user, err := testClient.WhoAmI(ctx)
@@ -241,32 +282,124 @@ func runLogin(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Successfully authenticated as: %s\n", user.Email)
 		}
 	} else {
-		fmt.Println("⚠️  Skipping API key validation")
+		logger.Warn("Skipping API key validation")
 	}
 
 	// Add to config
-	cfg.AddKey(profileName, apiKey, apiBaseURL)
+	cfg.AddKey(profileName, apiKey, loginBaseURL(cfg))
+	if err := saveLoginProfile(cfg, profileName, envSelection); err != nil {
+		return err
+	}
 
+	fmt.Printf("%sAPI key saved as profile '%s'\n", emoji("✓ ", ""), profileName)
 	if setDefault {
-		cfg.DefaultKey = profileName
+		fmt.Printf("%sSet '%s' as default profile\n", emoji("✓ ", ""), profileName)
 	}
 
-	// Save config
-	configPath, err := config.GetConfigPath()
-	if err != nil {
-		return fmt.Errorf("failed to get config path: %w", err)
+	return nil
+}
+
+// loginProfileName returns the profile name for a login, asking before
+// overwriting an existing profile
+func loginProfileName(cfg *config.Config) (string, error) {
+	profileName := keyName
+	if profileName == "" {
+		if fromStdin {
+			profileName = "default"
+		} else {
+			var err error
+			profileName, err = promptForProfileName()
+			if err != nil {
+				return "", fmt.Errorf("failed to get profile name: %w", err)
+			}
+		}
 	}
 
-	if err := cfg.SaveConfig(configPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	// Check if profile already exists
+	if _, exists := cfg.Keys[profileName]; exists && !force {
+		if !fromStdin {
+			fmt.Printf("Profile '%s' already exists. Overwrite? (y/N): ", profileName)
+			scanner := bufio.NewScanner(os.Stdin)
+			scanner.Scan()
+			if strings.ToLower(strings.TrimSpace(scanner.Text())) != "y" {
+				return "", fmt.Errorf("aborted")
+			}
+		} else {
+			return "", fmt.Errorf("profile '%s' already exists, use --force to overwrite", profileName)
+		}
+	}
+
+	return profileName, nil
+}
+
+// loginBaseURL returns the base URL saved with a new profile: --base-url,
+// else TONECLONE_BASE_URL, else the default base URL
+func loginBaseURL(cfg *config.Config) string {
+	if baseURL != "" {
+		return baseURL
+	}
+	if envURL := os.Getenv("TONECLONE_BASE_URL"); envURL != "" {
+		return envURL
+	}
+	return cfg.DefaultBaseURL
+}
+
+// loginTarget returns the key profile a login talks to, and the environment
+// selected for it. Without --base-url, an environment chosen with --env or
+// TONECLONE_ENV decides where the key is used, and without TONECLONE_BASE_URL
+// either, so does default_env.
+func loginTarget(cfg *config.Config, key string) (config.APIKeyConfig, config.EnvironmentSelection, error) {
+	loginKey := config.APIKeyConfig{Key: key}
+	if baseURL != "" || os.Getenv("TONECLONE_BASE_URL") != "" {
+		loginKey.BaseURL = loginBaseURL(cfg)
+	}
+	envSelection := cfg.ResolveEnvironment(loginKey)
+	if envSelection.Name == "" {
+		loginKey.BaseURL = loginBaseURL(cfg)
+	}
+	loginKey.Network = cfg.NetworkFor(envSelection.Name)
+
+	if baseURL == "" && envSelection.Name != "" {
+		var err error
+		if loginKey, err = cfg.ApplyEnvironment(loginKey); err != nil {
+			return loginKey, envSelection, err
+		}
+	}
+	return loginKey, envSelection, nil
+}
+
+// saveLoginProfile binds a newly added profile to the selected environment,
+// moves its secrets to the configured store and saves the config
+func saveLoginProfile(cfg *config.Config, profileName string, envSelection config.EnvironmentSelection) error {
+	if baseURL == "" && envSelection.Name != "" {
+		if err := cfg.BindEnvironment(profileName, envSelection.Name); err != nil {
+			return err
+		}
+	}
+
+	// Keep the key out of the config file if a store is configured
+	store := keyStore
+	if store == "" {
+		store = cfg.CredentialStore
+	}
+	switch {
+	case keyCommand != "":
+		keyConfig := cfg.Keys[profileName]
+		keyConfig.Key = ""
+		keyConfig.Store = config.StoreCommand
+		keyConfig.KeyCommand = keyCommand
+		cfg.Keys[profileName] = keyConfig
+	case store != "":
+		if err := cfg.MigrateKey(profileName, store); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("✓ API key saved as profile '%s'\n", profileName)
 	if setDefault {
-		fmt.Printf("✓ Set '%s' as default profile\n", profileName)
+		cfg.DefaultKey = profileName
 	}
 
-	return nil
+	return saveConfig(cfg)
 }
 
 func runLogout(cmd *cobra.Command, args []string) error {
@@ -315,6 +448,19 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	names := make([]string, 0, len(cfg.Keys))
+	for name := range cfg.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if outputQuiet {
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
 	if len(cfg.Keys) == 0 {
 		fmt.Println("No API key profiles configured.")
 		fmt.Println("Run 'toneclone auth login' to add one.")
@@ -324,7 +470,8 @@ func runList(cmd *cobra.Command, args []string) error {
 	fmt.Println("Configured API key profiles:")
 	fmt.Println()
 
-	for name, keyConfig := range cfg.Keys {
+	for _, name := range names {
+		keyConfig := cfg.Keys[name]
 		isDefault := name == cfg.DefaultKey
 		defaultMarker := ""
 		if isDefault {
@@ -333,10 +480,22 @@ func runList(cmd *cobra.Command, args []string) error {
 
 		// Redact API key for display
 		redactedKey := redactAPIKey(keyConfig.Key)
+		if keyConfig.IsOAuth() {
+			redactedKey = "(device login)"
+		} else if keyConfig.Store == config.StoreCommand {
+			redactedKey = "(from key_command)"
+		} else if keyConfig.IsStored() {
+			redactedKey = fmt.Sprintf("(stored in %s)", keyConfig.Store)
+		}
 
 		fmt.Printf("  %s%s\n", name, defaultMarker)
 		fmt.Printf("    Key: %s\n", redactedKey)
-		fmt.Printf("    URL: %s\n", keyConfig.BaseURL)
+		if env, exists := cfg.Environments[keyConfig.Env]; exists {
+			fmt.Printf("    URL: %s\n", env.BaseURL)
+			fmt.Printf("    Env: %s\n", keyConfig.Env)
+		} else {
+			fmt.Printf("    URL: %s\n", keyConfig.BaseURL)
+		}
 		fmt.Println()
 	}
 
@@ -349,7 +508,8 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	currentKeyName := cfg.GetCurrentKeyName()
+	selection := cfg.ResolveAccount()
+	currentKeyName := selection.Name
 	if currentKeyName == "" {
 		fmt.Println("Not authenticated")
 		fmt.Println("Run 'toneclone auth login' to authenticate")
@@ -361,23 +521,33 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Current profile: %s\n", currentKeyName)
-	fmt.Printf("API key: %s\n", redactAPIKey(keyConfig.Key))
+	fmt.Printf("Current profile: %s (from %s)\n", currentKeyName, selection.Source)
+	if keyConfig.IsOAuth() {
+		fmt.Printf("Auth: device login (%s)\n", describeTokenExpiry(keyConfig.TokenExpiry))
+	} else {
+		fmt.Printf("API key: %s\n", redactAPIKey(keyConfig.Key))
+	}
 	fmt.Printf("Base URL: %s\n", keyConfig.BaseURL)
+	if envSelection := cfg.ResolveEnvironment(cfg.Keys[currentKeyName]); envSelection.Name != "" {
+		fmt.Printf("Environment: %s (from %s)\n", envSelection.Name, envSelection.Source)
+	}
 
 	// Test connection
 	fmt.Print("Testing connection...")
-	testClient := client.NewToneCloneClient(keyConfig.Key, client.WithBaseURL(keyConfig.BaseURL))
+	testClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := testClient.ValidateConnection(ctx); err != nil {
-		fmt.Println(" ✗")
+		fmt.Println(emoji(" ✗", " failed"))
 		fmt.Printf("Connection failed: %v\n", err)
 		return nil
 	}
-	fmt.Println(" ✓")
+	fmt.Println(emoji(" ✓", " OK"))
 
 	// Get user info
 	user, err := testClient.WhoAmI(ctx)
@@ -391,11 +561,30 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Plan: %s\n", user.Plan)
 	}
 
+	// Device logins have no API key record to read scopes from
+	if keyConfig.IsOAuth() {
+		return nil
+	}
+
+	// Refresh the cached scopes used by command preflight checks
+	scopes, err := keyScopes(ctx, keyConfig, true)
+	if err != nil {
+		fmt.Printf("Scopes: unknown (%v)\n", err)
+		return nil
+	}
+	fmt.Printf("Scopes: %s\n", formatScopes(scopes))
+
 	return nil
 }
 
 func runSwitch(cmd *cobra.Command, args []string) error {
 	profileName, _ := cmd.Flags().GetString("profile")
+	if len(args) > 0 {
+		profileName = args[0]
+	}
+	if profileName == "" {
+		return fmt.Errorf("account name required: toneclone auth switch <account>")
+	}
 
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -420,8 +609,76 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runMigrate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := keyStore
+	if store == "" {
+		store = cfg.CredentialStore
+	}
+	if store == "" {
+		store = config.StoreKeyring
+	}
+
+	profiles := args
+	if len(profiles) == 0 {
+		profiles = cfg.PlaintextKeys()
+	}
+
+	if len(profiles) == 0 {
+		fmt.Println("No plaintext API keys to migrate.")
+		return nil
+	}
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	for _, profileName := range profiles {
+		if profileName == "environment" {
+			return fmt.Errorf("the 'environment' profile comes from TONECLONE_API_KEY and cannot be migrated")
+		}
+
+		if err := cfg.MigrateKey(profileName, store); err != nil {
+			return err
+		}
+
+		// Save after each key so the config never points at a missing secret
+		if err := cfg.SaveConfig(configPath); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		fmt.Printf("%sMoved API key for profile '%s' to %s\n", emoji("✓ ", ""), profileName, store)
+	}
+
+	if store == config.StoreFake {
+		fmt.Fprintln(os.Stderr, emoji("⚠️  ", "Warning: ")+"The fake store keeps keys unencrypted and is meant for testing only")
+	}
+
+	return nil
+}
+
 // Helper functions
 
+func promptForPassphrase() (string, error) {
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return "", fmt.Errorf("passphrase required: set TONECLONE_PASSPHRASE")
+	}
+
+	fmt.Fprint(os.Stderr, "Credentials passphrase: ")
+	passphrase, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	return string(passphrase), nil
+}
+
 func readAPIKeyFromStdin() (string, error) {
 	scanner := bufio.NewScanner(os.Stdin)
 	if !scanner.Scan() {
@@ -468,6 +725,18 @@ func promptForProfileName() (string, error) {
 	return name, nil
 }
 
+// describeTokenExpiry describes when an OAuth access token expires
+func describeTokenExpiry(expiry time.Time) string {
+	switch {
+	case expiry.IsZero():
+		return "token does not expire"
+	case time.Now().After(expiry):
+		return "token expired, refreshed on next use"
+	default:
+		return fmt.Sprintf("token expires %s", expiry.Local().Format(time.RFC3339))
+	}
+}
+
 func redactAPIKey(key string) string {
 	if len(key) <= 12 {
 		return "****"
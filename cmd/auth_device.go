@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// runDeviceLogin logs in with the OAuth device authorization grant: the
+// user approves a one-time code in a browser while the CLI polls for tokens
+func runDeviceLogin(ctx context.Context, cfg *config.Config) error {
+	profileName, err := loginProfileName(cfg)
+	if err != nil {
+		return err
+	}
+
+	loginKey, envSelection, err := loginTarget(cfg, "")
+	if err != nil {
+		return err
+	}
+
+	deviceAuth, err := newDeviceAuth(cfg, loginKey)
+	if err != nil {
+		return err
+	}
+
+	// Stop polling on Ctrl-C
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	code, err := deviceAuth.RequestCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("To log in, open %s and enter the code: %s\n", code.VerificationURI, code.UserCode)
+	if code.VerificationURIComplete != "" {
+		fmt.Printf("Or open %s\n", code.VerificationURIComplete)
+	}
+	fmt.Println()
+	fmt.Print("Waiting for approval...")
+
+	token, err := deviceAuth.PollToken(ctx, code)
+	if err != nil {
+		fmt.Println(emoji(" ✗", " failed"))
+		return fmt.Errorf("device login failed: %w", err)
+	}
+	fmt.Println(emoji(" ✓", " OK"))
+
+	// Confirm the token works before saving it
+	if !skipValidation {
+		loginKey.Key = token.AccessToken
+		loginKey.Auth = config.AuthOAuth
+		testClient, err := newAPIClient(loginKey, 10*time.Second)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		user, err := testClient.WhoAmI(ctx)
+		if err != nil {
+			return fmt.Errorf("access token validation failed: %w", err)
+		}
+		fmt.Printf("Successfully authenticated as: %s\n", user.Email)
+	}
+
+	cfg.AddKey(profileName, token.AccessToken, loginBaseURL(cfg))
+	if err := cfg.SetTokens(profileName, token.AccessToken, token.RefreshToken, token.Expiry); err != nil {
+		return err
+	}
+	if err := saveLoginProfile(cfg, profileName, envSelection); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sLogged in as profile '%s'\n", emoji("✓ ", ""), profileName)
+	if setDefault {
+		fmt.Printf("%sSet '%s' as default profile\n", emoji("✓ ", ""), profileName)
+	}
+
+	return nil
+}
+
+// newDeviceAuth returns the device grant client for a key profile, using
+// its client options and any oauth overrides in the config
+func newDeviceAuth(cfg *config.Config, keyConfig config.APIKeyConfig) (*client.DeviceAuth, error) {
+	options, err := clientOptions(keyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceAuth := client.NewDeviceAuth(keyConfig.BaseURL, cfg.OAuth.ClientID, options...)
+	if cfg.OAuth.DeviceAuthURL != "" {
+		deviceAuth.DeviceAuthURL = cfg.OAuth.DeviceAuthURL
+	}
+	if cfg.OAuth.TokenURL != "" {
+		deviceAuth.TokenURL = cfg.OAuth.TokenURL
+	}
+	deviceAuth.Scopes = cfg.OAuth.Scopes
+
+	return deviceAuth, nil
+}
+
+// profileTokenSource returns the access token of an OAuth profile and, when
+// the API rejects it, refreshes it and saves the new tokens
+func profileTokenSource(keyConfig config.APIKeyConfig) client.TokenSource {
+	// A profile that is not saved yet has nothing to refresh
+	if keyConfig.Profile == "" {
+		return client.RefreshingTokenSource(keyConfig.Key, nil)
+	}
+
+	return client.RefreshingTokenSource(keyConfig.Key, func(ctx context.Context) (string, error) {
+		return refreshProfileToken(ctx, keyConfig)
+	})
+}
+
+// refreshProfileToken exchanges a profile's refresh token for new tokens and
+// saves them. The config is reloaded so a refresh by another process is
+// not lost.
+func refreshProfileToken(ctx context.Context, keyConfig config.APIKeyConfig) (string, error) {
+	profileName := keyConfig.Profile
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	stored, exists := cfg.Keys[profileName]
+	if !exists {
+		return "", fmt.Errorf("API key profile '%s' not found", profileName)
+	}
+
+	refreshToken, err := stored.ResolveRefreshToken(profileName)
+	if err != nil {
+		return "", err
+	}
+	if refreshToken == "" {
+		return "", fmt.Errorf("access token for profile '%s' expired; run 'toneclone auth login --device --name %s'", profileName, profileName)
+	}
+
+	deviceAuth, err := newDeviceAuth(cfg, keyConfig)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := deviceAuth.Refresh(ctx, refreshToken)
+	if err != nil {
+		var oauthErr *client.OAuthError
+		if errors.As(err, &oauthErr) && oauthErr.Code == client.OAuthInvalidGrant {
+			return "", fmt.Errorf("session for profile '%s' expired; run 'toneclone auth login --device --name %s'", profileName, profileName)
+		}
+		return "", err
+	}
+
+	if err := cfg.SetTokens(profileName, token.AccessToken, token.RefreshToken, token.Expiry); err != nil {
+		return "", err
+	}
+	if err := saveConfig(cfg); err != nil {
+		return "", err
+	}
+
+	logger.Debug("Refreshed access token", "profile", profileName)
+	return token.AccessToken, nil
+}
@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+var (
+	// Key management flags
+	keysName    string
+	keysScopes  []string
+	keysExpires string
+	keysSaveAs  string
+	keysConfirm bool
+)
+
+// keysCmd represents the auth keys command
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage API keys on your account",
+	Long: `List, create, revoke and rotate the API keys on your ToneClone account.
+
+Scopes:
+  personas:read  personas:write  profiles:read  profiles:write
+  training:read  training:write  files:read     files:write
+  writing:read   writing:write   user:read      user:write
+  text:generate  admin:all       *
+
+Examples:
+  toneclone auth keys list
+  toneclone auth keys create --name ci --scope personas:read,text:generate --expires 90d
+  toneclone auth keys revoke key_123
+  toneclone auth keys rotate`,
+}
+
+// listKeysCmd represents the auth keys list command
+var listKeysCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Long: `List the API keys on your account with their scopes, status and usage.
+
+Examples:
+  toneclone auth keys list
+  toneclone auth keys list -o json`,
+	RunE: runListKeys,
+}
+
+// createKeyCmd represents the auth keys create command
+var createKeyCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key",
+	Long: `Create a new API key. The key is shown once; use --save-as to store it
+directly as a local profile instead.
+
+--expires accepts a duration (90d, 12h) or a date (2026-12-31).
+
+Examples:
+  toneclone auth keys create --name ci --scope personas:read,text:generate
+  toneclone auth keys create --name deploy --expires 30d --save-as deploy`,
+	RunE: runCreateKey,
+}
+
+// revokeKeyCmd represents the auth keys revoke command
+var revokeKeyCmd = &cobra.Command{
+	Use:   "revoke <key-id>",
+	Short: "Revoke an API key",
+	Long: `Revoke an API key. Requests using the key fail immediately.
+
+Examples:
+  toneclone auth keys revoke key_123
+  toneclone auth keys revoke key_123 --confirm`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRevokeKey,
+}
+
+// rotateKeyCmd represents the auth keys rotate command
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the API key of the current profile",
+	Long: `Replace the API key of the current profile with a new one.
+
+Rotation creates a new key with the same name and scopes, saves it to the
+local profile (in the profile's credential store, if it has one), validates
+it, and only then revokes the old key. If validation fails, the profile keeps
+the old key and the new key is revoked.
+
+Examples:
+  toneclone auth keys rotate
+  toneclone auth keys rotate --profile production --expires 90d`,
+	RunE: runRotateKey,
+}
+
+func init() {
+	authCmd.AddCommand(keysCmd)
+
+	keysCmd.AddCommand(listKeysCmd)
+	keysCmd.AddCommand(createKeyCmd)
+	keysCmd.AddCommand(revokeKeyCmd)
+	keysCmd.AddCommand(rotateKeyCmd)
+
+	// List flags
+	addOutputFlags(listKeysCmd)
+	addQuietFlag(listKeysCmd)
+
+	// Create flags
+	createKeyCmd.Flags().StringVar(&keysName, "name", "", "name for the API key")
+	createKeyCmd.Flags().StringSliceVar(&keysScopes, "scope", nil, "scopes to grant (comma-separated, default: all)")
+	createKeyCmd.Flags().StringVar(&keysExpires, "expires", "", "expiry as a duration (90d, 12h) or date (2026-12-31)")
+	createKeyCmd.Flags().StringVar(&keysSaveAs, "save-as", "", "save the new key as this local profile instead of printing it")
+	addOutputFlags(createKeyCmd)
+	createKeyCmd.Flags().BoolVarP(&outputQuiet, "quiet", "q", false, "print only the new key, or its ID with --save-as")
+	createKeyCmd.MarkFlagRequired("name")
+
+	// Revoke flags
+	revokeKeyCmd.Flags().BoolVar(&keysConfirm, "confirm", false, "skip confirmation prompt")
+
+	// Rotate flags
+	rotateKeyCmd.Flags().StringVar(&keysExpires, "expires", "", "expiry for the new key (default: same lifetime as the old key)")
+}
+
+// newKeysClient loads the config and creates a client for the current profile
+func newKeysClient() (*config.Config, string, *client.ToneCloneClient, error) {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Get current API key
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("authentication required: %w", err)
+	}
+
+	// Create API client
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return cfg, keyConfig.Key, apiClient, nil
+}
+
+func runListKeys(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	_, currentKey, apiClient, err := newKeysClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	keys, err := apiClient.APIKeys.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	return outputAPIKeys(printer, keys, currentKey)
+}
+
+// apiKeyRow is an API key in tables, with the secret of a new key
+type apiKeyRow struct {
+	Key     client.APIKey
+	Current bool
+	Secret  string
+}
+
+// apiKeyColumns are the columns of API key tables and CSV output
+var apiKeyColumns = []output.Column[apiKeyRow]{
+	{Name: "id", Value: func(r apiKeyRow) string { return r.Key.KeyID }},
+	{Name: "name", Value: func(r apiKeyRow) string {
+		if r.Current {
+			return r.Key.Name + " (current)"
+		}
+		return r.Key.Name
+	}},
+	{Name: "prefix", Value: func(r apiKeyRow) string { return r.Key.Prefix }},
+	{Name: "scopes", Value: func(r apiKeyRow) string { return formatScopes(r.Key.Scopes) }},
+	{Name: "status", Value: func(r apiKeyRow) string { return r.Key.Status }},
+	{Name: "expires", Value: func(r apiKeyRow) string {
+		if r.Key.ExpiresAt != nil {
+			return r.Key.ExpiresAt.Format("2006-01-02")
+		}
+		return "Never"
+	}},
+	{Name: "last_used", Value: func(r apiKeyRow) string {
+		if r.Key.LastUsedAt != nil {
+			return formatTime(*r.Key.LastUsedAt)
+		}
+		return "Never"
+	}},
+	{Name: "uses", Value: func(r apiKeyRow) string { return strconv.FormatInt(r.Key.UsageCount, 10) }},
+}
+
+// createdKeyColumns add the new key itself, shown once
+var createdKeyColumns = append(apiKeyColumns[:len(apiKeyColumns):len(apiKeyColumns)],
+	output.Column[apiKeyRow]{Name: "key", Wide: true, Value: func(r apiKeyRow) string { return r.Secret }},
+)
+
+func outputAPIKeys(printer *output.Printer, keys []client.APIKey, currentKey string) error {
+	if len(keys) == 0 && printer.Tabular() {
+		fmt.Println("No API keys found.")
+		return nil
+	}
+	if keys == nil {
+		keys = []client.APIKey{}
+	}
+
+	rows := make([]apiKeyRow, len(keys))
+	for i, key := range keys {
+		rows[i] = apiKeyRow{Key: key, Current: key.Prefix != "" && strings.HasPrefix(currentKey, key.Prefix)}
+	}
+
+	return output.List(printer, map[string]interface{}{
+		"keys":  keys,
+		"count": len(keys),
+	}, rows, apiKeyColumns)
+}
+
+func runCreateKey(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	cfg, _, apiClient, err := newKeysClient()
+	if err != nil {
+		return err
+	}
+
+	scopes, err := client.ParseScopes(keysScopes)
+	if err != nil {
+		return err
+	}
+
+	expiresAt, err := parseKeyExpiry(keysExpires, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if keysSaveAs != "" {
+		if _, exists := cfg.Keys[keysSaveAs]; exists {
+			return fmt.Errorf("profile '%s' already exists", keysSaveAs)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	created, err := apiClient.APIKeys.Create(ctx, &client.CreateAPIKeyRequest{
+		Name:      keysName,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if keysSaveAs != "" {
+		currentKey, _ := cfg.GetCurrentKey()
+		cfg.AddKey(keysSaveAs, created.Key, currentKey.BaseURL)
+		if currentKey.Env != "" {
+			if err := cfg.BindEnvironment(keysSaveAs, currentKey.Env); err != nil {
+				return err
+			}
+		}
+		if cfg.CredentialStore != "" {
+			if err := cfg.MigrateKey(keysSaveAs, cfg.CredentialStore); err != nil {
+				return err
+			}
+		}
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+		if outputQuiet {
+			fmt.Println(created.KeyID)
+			return nil
+		}
+		fmt.Printf("%sAPI key '%s' (%s) created and saved as profile '%s'\n", emoji("✓ ", ""), created.Name, created.KeyID, keysSaveAs)
+		return nil
+	}
+
+	// The key is shown only once, so quiet output is the key itself
+	if outputQuiet {
+		fmt.Println(created.Key)
+		return nil
+	}
+
+	row := apiKeyRow{Key: created.APIKey, Secret: created.Key}
+	return output.Object(printer, created, row, createdKeyColumns, func() error {
+		fmt.Printf("%sAPI key '%s' created (%s)\n", emoji("✓ ", ""), created.Name, created.KeyID)
+		fmt.Printf("Scopes: %s\n", formatScopes(created.Scopes))
+		if created.ExpiresAt != nil {
+			fmt.Printf("Expires: %s\n", created.ExpiresAt.Format("2006-01-02"))
+		}
+		fmt.Println()
+		fmt.Println(created.Key)
+		fmt.Println()
+		fmt.Fprintln(os.Stderr, "Copy this key now; it will not be shown again.")
+		return nil
+	})
+}
+
+func runRevokeKey(cmd *cobra.Command, args []string) error {
+	keyID := args[0]
+
+	_, _, apiClient, err := newKeysClient()
+	if err != nil {
+		return err
+	}
+
+	// Confirm revocation
+	if !keysConfirm {
+		fmt.Printf("Are you sure you want to revoke API key '%s'? [y/N]: ", keyID)
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			fmt.Println("Revocation cancelled")
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := apiClient.APIKeys.Revoke(ctx, keyID); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sAPI key '%s' revoked\n", emoji("✓ ", ""), keyID)
+	return nil
+}
+
+func runRotateKey(cmd *cobra.Command, args []string) error {
+	cfg, oldKey, apiClient, err := newKeysClient()
+	if err != nil {
+		return err
+	}
+
+	profileName := cfg.GetCurrentKeyName()
+	keyConfig := cfg.Keys[profileName]
+	if profileName == "environment" {
+		return fmt.Errorf("the 'environment' profile comes from TONECLONE_API_KEY; rotate a saved profile instead")
+	}
+	if keyConfig.Store == config.StoreCommand {
+		return fmt.Errorf("profile '%s' reads its key from key_command; rotate the key in your password manager", profileName)
+	}
+	if keyConfig.IsOAuth() {
+		return fmt.Errorf("profile '%s' uses device login; its tokens are refreshed automatically", profileName)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+	defer cancel()
+
+	// Find the old key's record to copy its name and scopes
+	oldRecord, err := apiClient.APIKeys.FindByKey(ctx, oldKey)
+	if err != nil {
+		return err
+	}
+
+	expiresAt, err := parseKeyExpiry(keysExpires, time.Now())
+	if err != nil {
+		return err
+	}
+	if expiresAt == nil && oldRecord.ExpiresAt != nil {
+		// Keep the same lifetime as the old key
+		lifetime := oldRecord.ExpiresAt.Sub(oldRecord.CreatedAt)
+		newExpiry := time.Now().Add(lifetime).UTC()
+		expiresAt = &newExpiry
+	}
+
+	// 1. Create the new key
+	created, err := apiClient.APIKeys.Create(ctx, &client.CreateAPIKeyRequest{
+		Name:      oldRecord.Name,
+		Scopes:    oldRecord.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%sCreated new API key (%s)\n", emoji("✓ ", ""), created.KeyID)
+
+	// 2. Save it to the local profile
+	if err := cfg.UpdateKey(profileName, created.Key); err != nil {
+		return rotateRollback(ctx, apiClient, created.KeyID, fmt.Errorf("failed to save new key: %w", err))
+	}
+	if err := saveConfig(cfg); err != nil {
+		return rotateRollback(ctx, apiClient, created.KeyID, err)
+	}
+	fmt.Printf("%sUpdated profile '%s'\n", emoji("✓ ", ""), profileName)
+
+	// 3. Validate the new key
+	newKeyConfig := keyConfig
+	newKeyConfig.Key = created.Key
+	newClient, err := newAPIClient(newKeyConfig, 0)
+	if err != nil {
+		return err
+	}
+	if err := newClient.ValidateConnection(ctx); err != nil {
+		// Put the old key back before revoking the new one
+		if restoreErr := cfg.UpdateKey(profileName, oldKey); restoreErr == nil {
+			saveConfig(cfg)
+		}
+		return rotateRollback(ctx, apiClient, created.KeyID, fmt.Errorf("new key failed validation: %w", err))
+	}
+	fmt.Println(emoji("✓ ", "") + "Validated new key")
+
+	// 4. Revoke the old key
+	if err := apiClient.APIKeys.Revoke(ctx, oldRecord.KeyID); err != nil {
+		return fmt.Errorf("new key is in use but revoking the old key failed (revoke %s manually): %w", oldRecord.KeyID, err)
+	}
+	fmt.Printf("%sRevoked old API key (%s)\n", emoji("✓ ", ""), oldRecord.KeyID)
+
+	return nil
+}
+
+// rotateRollback revokes a newly created key after a failed rotation
+func rotateRollback(ctx context.Context, apiClient *client.ToneCloneClient, keyID string, cause error) error {
+	if err := apiClient.APIKeys.Revoke(ctx, keyID); err != nil {
+		return fmt.Errorf("%w (also failed to revoke new key %s: %v)", cause, keyID, err)
+	}
+	return fmt.Errorf("%w; the new key was revoked and the old key is still active", cause)
+}
+
+func saveConfig(cfg *config.Config) error {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := cfg.SaveConfig(configPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// parseKeyExpiry parses a duration such as "90d" or "12h", or a date such
+// as "2026-12-31". An empty value means no expiry.
+func parseKeyExpiry(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var expiresAt time.Time
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid expiry '%s'", value)
+		}
+		expiresAt = now.AddDate(0, 0, n)
+	} else if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid expiry '%s'", value)
+		}
+		expiresAt = now.Add(d)
+	} else if t, err := time.Parse("2006-01-02", value); err == nil {
+		expiresAt = t
+	} else if t, err := time.Parse(time.RFC3339, value); err == nil {
+		expiresAt = t
+	} else {
+		return nil, fmt.Errorf("invalid expiry '%s' (use e.g. 90d, 12h or 2026-12-31)", value)
+	}
+
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("expiry '%s' is in the past", value)
+	}
+
+	expiresAt = expiresAt.UTC()
+	return &expiresAt, nil
+}
+
+func formatScopes(scopes []client.APIKeyScope) string {
+	if len(scopes) == 0 {
+		return "*"
+	}
+
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, ",")
+}
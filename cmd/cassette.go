@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// apiCassette records or replays API traffic when TONECLONE_RECORD or
+// TONECLONE_REPLAY is set. It is opened by the first client.
+var apiCassette *client.Cassette
+
+// cassetteFromEnv returns the cassette named by TONECLONE_RECORD or
+// TONECLONE_REPLAY, or nil if neither is set
+func cassetteFromEnv() (*client.Cassette, error) {
+	if apiCassette != nil {
+		return apiCassette, nil
+	}
+
+	recordPath := os.Getenv("TONECLONE_RECORD")
+	replayPath := os.Getenv("TONECLONE_REPLAY")
+	var (
+		cassette *client.Cassette
+		err      error
+	)
+	switch {
+	case recordPath != "" && replayPath != "":
+		return nil, fmt.Errorf("TONECLONE_RECORD and TONECLONE_REPLAY cannot both be set")
+	case recordPath != "":
+		cassette, err = client.OpenCassette(recordPath, client.CassetteRecord)
+	case replayPath != "":
+		cassette, err = client.OpenCassette(replayPath, client.CassetteReplay)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Commands of a session share replay progress, so a later command gets
+	// the responses recorded after those of earlier ones. Without one, every
+	// command replays from the beginning.
+	if replayPath != "" {
+		cassette.Session = os.Getenv("TONECLONE_REPLAY_SESSION")
+	}
+
+	// Match on method and URL only when bodies vary between runs
+	if os.Getenv("TONECLONE_REPLAY_MATCH") == "url" {
+		cassette.Match = client.MatchMethodAndURL
+	}
+
+	apiCassette = cassette
+	return cassette, nil
+}
+
+// usingCassette reports whether API traffic is recorded or replayed
+func usingCassette() bool {
+	return os.Getenv("TONECLONE_RECORD") != "" || os.Getenv("TONECLONE_REPLAY") != ""
+}
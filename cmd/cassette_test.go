@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCassetteFromEnvSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1, "interactions": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write cassette: %v", err)
+	}
+	t.Setenv("TONECLONE_RECORD", "")
+	t.Setenv("TONECLONE_REPLAY", path)
+	t.Cleanup(func() { apiCassette = nil })
+
+	// Shared progress is opt-in, so reruns replay from the beginning
+	for _, session := range []string{"", "ci-42"} {
+		apiCassette = nil
+		t.Setenv("TONECLONE_REPLAY_SESSION", session)
+		cassette, err := cassetteFromEnv()
+		if err != nil {
+			t.Fatalf("Failed to open cassette: %v", err)
+		}
+		if cassette.Session != session {
+			t.Errorf("Expected session %q, got %q", session, cassette.Session)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
+	"github.com/toneclone/cli/internal/textutil"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+var (
+	// Compare command flags
+	comparePersonas    string
+	compareProfiles    string
+	comparePrompt      string
+	compareFile        string
+	compareTimeout     int
+	compareConcurrency int
+	compareWidth       int
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare generations across personas and profiles",
+	Long: `Generate text for the same prompt with several personas and profiles and
+compare the results side by side.
+
+Every combination of the given personas and profiles is generated concurrently.
+Results include per-variant latency and length statistics.
+
+The prompt can be provided via --prompt, --file or stdin, just like 'write'.
+
+Examples:
+  toneclone compare --prompt="Announce our new feature" --persona=casual,professional
+  toneclone compare --persona=writer,marketer --profile=email,social --file=prompt.txt
+  toneclone compare --persona=a,b,c --prompt="Write a tagline" -o markdown
+  toneclone compare --persona=a,b --prompt="Write a tagline" -o json
+
+Output Formats:
+  -o table      Side-by-side columns in the terminal (default)
+  -o markdown   Markdown table, one row per variant
+  -o json       JSON with text, latency and length stats per variant
+
+The other -o formats, such as csv, yaml or jsonpath, work as for other
+commands, and --columns prints a plain table of the selected columns.`,
+	Annotations: withFlagScopes(requireScopes(client.ScopeTextGenerate, client.ScopePersonasRead), "profile", client.ScopeProfilesRead),
+	RunE:        runCompare,
+}
+
+// compareVariant is a single persona/profile combination to generate
+type compareVariant struct {
+	Persona *client.Persona
+	Profile *client.Profile
+}
+
+// compareResult holds the outcome of generating one variant
+type compareResult struct {
+	Variant    compareVariant
+	Text       string
+	Latency    time.Duration
+	Characters int
+	Words      int
+	Err        error
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().StringVar(&comparePersonas, "persona", "", "comma-separated persona IDs or names to compare")
+	compareCmd.Flags().StringVar(&compareProfiles, "profile", "", "comma-separated profile IDs or names to compare")
+	compareCmd.Flags().StringVar(&comparePrompt, "prompt", "", "text prompt for generation")
+	compareCmd.Flags().StringVar(&compareFile, "file", "", "file containing the prompt")
+	addOutputFlags(compareCmd)
+	compareCmd.Flags().IntVar(&compareTimeout, "timeout", 60, "request timeout in seconds")
+	compareCmd.Flags().IntVar(&compareConcurrency, "concurrency", 4, "maximum number of generations in flight")
+	compareCmd.Flags().IntVar(&compareWidth, "width", 0, "terminal width for side-by-side output (default: detected)")
+
+	compareCmd.MarkFlagRequired("persona")
+	compareCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, true))
+	compareCmd.RegisterFlagCompletionFunc("profile", completeNames(completeProfiles, true))
+}
+
+// compareMarkdown is the -o format of compare's Markdown table
+const compareMarkdown = "markdown"
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	// Markdown is compare's own format; the rest are shared
+	var printer *output.Printer
+	if outputFormat != compareMarkdown {
+		var err error
+		if printer, err = newPrinter(); err != nil {
+			return err
+		}
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Get current API key
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		return fmt.Errorf("authentication required: %w", err)
+	}
+
+	// Create API client
+	apiClient, err := newAPIClient(keyConfig, time.Duration(compareTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+
+	// Get the prompt
+	prompt, err := getComparePrompt()
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		return fmt.Errorf("prompt cannot be empty")
+	}
+
+	ctx := cmd.Context()
+
+	// Resolve personas
+	var personas []*client.Persona
+	for _, input := range splitList(comparePersonas) {
+		persona, err := validatePersona(ctx, apiClient, keyConfig, input)
+		if err != nil {
+			return fmt.Errorf("persona validation failed for '%s': %w", input, err)
+		}
+		personas = append(personas, persona)
+	}
+
+	if len(personas) == 0 {
+		return fmt.Errorf("at least one persona is required")
+	}
+
+	// Resolve profiles
+	var profiles []*client.Profile
+	for _, input := range splitList(compareProfiles) {
+		profile, err := validateProfile(ctx, apiClient, keyConfig, input)
+		if err != nil {
+			return fmt.Errorf("profile validation failed for '%s': %w", input, err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	variants := buildCompareVariants(personas, profiles)
+
+	logger.Debug("Generating variants", "count", len(variants))
+
+	results := generateCompareVariants(ctx, apiClient, prompt, variants)
+
+	switch {
+	case printer == nil:
+		err = outputCompareMarkdown(results)
+	case printer.Tabular() && len(printer.Columns) == 0:
+		err = outputCompareSideBySide(results)
+	default:
+		err = output.List(printer, compareData(prompt, results), results, compareColumns)
+	}
+	if err != nil {
+		return err
+	}
+	return compareFailures(results)
+}
+
+// compareFailures returns an error when variants failed, after their errors
+// have been printed with the results
+func compareFailures(results []compareResult) error {
+	var failed int
+	var first error
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			if first == nil {
+				first = result.Err
+			}
+		}
+	}
+	return batchError("variants", failed, len(results), first)
+}
+
+func getComparePrompt() (string, error) {
+	// Priority: --prompt flag > --file flag > stdin
+	if comparePrompt != "" {
+		return comparePrompt, nil
+	}
+
+	if compareFile != "" {
+		return readWritePromptFromFile(compareFile)
+	}
+
+	return readWritePromptFromStdin()
+}
+
+// splitList splits a comma-separated flag value, trimming blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// buildCompareVariants returns the cartesian product of personas and profiles.
+// When no profiles are given each persona is generated without a profile.
+func buildCompareVariants(personas []*client.Persona, profiles []*client.Profile) []compareVariant {
+	var variants []compareVariant
+	for _, persona := range personas {
+		if len(profiles) == 0 {
+			variants = append(variants, compareVariant{Persona: persona})
+			continue
+		}
+		for _, profile := range profiles {
+			variants = append(variants, compareVariant{Persona: persona, Profile: profile})
+		}
+	}
+	return variants
+}
+
+// generateCompareVariants generates all variants concurrently, preserving input order
+func generateCompareVariants(ctx context.Context, apiClient *client.ToneCloneClient, prompt string, variants []compareVariant) []compareResult {
+	results := make([]compareResult, len(variants))
+
+	concurrency := compareConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, variant := range variants {
+		wg.Add(1)
+		go func(i int, variant compareVariant) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			request := &client.GenerateTextRequest{
+				Prompt:    prompt,
+				PersonaID: variant.Persona.PersonaID,
+			}
+			if variant.Profile != nil {
+				request.ProfileID = variant.Profile.ProfileID
+			}
+
+			reqCtx, cancel := context.WithTimeout(ctx, time.Duration(compareTimeout)*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			response, err := apiClient.Generate.Text(reqCtx, request)
+			result := compareResult{
+				Variant: variant,
+				Latency: time.Since(start),
+				Err:     err,
+			}
+			if err == nil {
+				result.Text = strings.TrimSpace(response.Text)
+				result.Characters = len([]rune(result.Text))
+				result.Words = textutil.WordCount(result.Text)
+			}
+			results[i] = result
+		}(i, variant)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// label returns a short human-readable name for the variant
+func (v compareVariant) label() string {
+	if v.Profile == nil {
+		return v.Persona.Name
+	}
+	return fmt.Sprintf("%s / %s", v.Persona.Name, v.Profile.Name)
+}
+
+func outputCompareSideBySide(results []compareResult) error {
+	width := compareWidth
+	if width <= 0 {
+		width = 120
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+			width = w
+		}
+	}
+
+	const gutter = " │ "
+	const minColumnWidth = 24
+
+	// Fit as many columns as the terminal allows, then continue in a new block
+	perRow := (width + len(gutter)) / (minColumnWidth + len(gutter))
+	if perRow < 1 {
+		perRow = 1
+	}
+	if perRow > len(results) {
+		perRow = len(results)
+	}
+
+	for start := 0; start < len(results); start += perRow {
+		end := start + perRow
+		if end > len(results) {
+			end = len(results)
+		}
+		block := results[start:end]
+
+		columnWidth := (width - len(gutter)*(len(block)-1)) / len(block)
+		if columnWidth < 10 {
+			columnWidth = 10
+		}
+
+		if start > 0 {
+			fmt.Println()
+		}
+
+		headers := make([][]string, len(block))
+		bodies := make([][]string, len(block))
+		for i, result := range block {
+			headers[i] = []string{
+				textutil.Truncate(result.Variant.label(), columnWidth),
+				textutil.Truncate(formatCompareStats(result), columnWidth),
+				strings.Repeat("─", columnWidth),
+			}
+			if result.Err != nil {
+				bodies[i] = textutil.Wrap("ERROR: "+result.Err.Error(), columnWidth)
+			} else {
+				bodies[i] = textutil.Wrap(result.Text, columnWidth)
+			}
+		}
+
+		printCompareColumns(headers, columnWidth, gutter)
+		printCompareColumns(bodies, columnWidth, gutter)
+	}
+
+	return nil
+}
+
+func printCompareColumns(columns [][]string, columnWidth int, gutter string) {
+	rows := 0
+	for _, column := range columns {
+		if len(column) > rows {
+			rows = len(column)
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		var cells []string
+		for _, column := range columns {
+			cell := ""
+			if row < len(column) {
+				cell = column[row]
+			}
+			cells = append(cells, textutil.PadRight(cell, columnWidth))
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, gutter), " "))
+	}
+}
+
+func formatCompareStats(result compareResult) string {
+	latency := result.Latency.Round(time.Millisecond)
+	if result.Err != nil {
+		return fmt.Sprintf("failed after %v", latency)
+	}
+	return fmt.Sprintf("%v · %d words · %d chars", latency, result.Words, result.Characters)
+}
+
+func outputCompareMarkdown(results []compareResult) error {
+	fmt.Println("| Persona | Profile | Latency | Words | Characters | Output |")
+	fmt.Println("|---|---|---|---|---|---|")
+
+	for _, result := range results {
+		profileName := "-"
+		if result.Variant.Profile != nil {
+			profileName = result.Variant.Profile.Name
+		}
+
+		text := result.Text
+		if result.Err != nil {
+			text = "**Error:** " + result.Err.Error()
+		}
+
+		fmt.Printf("| %s | %s | %v | %d | %d | %s |\n",
+			escapeMarkdownCell(result.Variant.Persona.Name),
+			escapeMarkdownCell(profileName),
+			result.Latency.Round(time.Millisecond),
+			result.Words,
+			result.Characters,
+			escapeMarkdownCell(text),
+		)
+	}
+
+	return nil
+}
+
+// escapeMarkdownCell makes text safe to place in a single Markdown table cell
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// compareColumns are the columns of compare's CSV output and of tables with
+// --columns
+var compareColumns = []output.Column[compareResult]{
+	{Name: "persona", Value: func(r compareResult) string { return r.Variant.Persona.Name }},
+	{Name: "profile", Value: func(r compareResult) string {
+		if r.Variant.Profile != nil {
+			return r.Variant.Profile.Name
+		}
+		return "-"
+	}},
+	{Name: "latency", Value: func(r compareResult) string { return r.Latency.Round(time.Millisecond).String() }},
+	{Name: "words", Value: func(r compareResult) string { return strconv.Itoa(r.Words) }},
+	{Name: "characters", Value: func(r compareResult) string { return strconv.Itoa(r.Characters) }},
+	{Name: "output", Value: func(r compareResult) string {
+		if r.Err != nil {
+			return "ERROR: " + r.Err.Error()
+		}
+		return r.Text
+	}},
+}
+
+// compareData returns the results for structured output, with text,
+// latency and length stats per variant
+func compareData(prompt string, results []compareResult) map[string]interface{} {
+	variants := []map[string]interface{}{}
+	for _, result := range results {
+		variant := map[string]interface{}{
+			"persona": map[string]string{
+				"id":   result.Variant.Persona.PersonaID,
+				"name": result.Variant.Persona.Name,
+			},
+			"latency_ms": result.Latency.Milliseconds(),
+			"characters": result.Characters,
+			"words":      result.Words,
+		}
+		if result.Variant.Profile != nil {
+			variant["profile"] = map[string]string{
+				"id":   result.Variant.Profile.ProfileID,
+				"name": result.Variant.Profile.Name,
+			}
+		}
+		if result.Err != nil {
+			variant["error"] = result.Err.Error()
+		} else {
+			variant["text"] = result.Text
+		}
+		variants = append(variants, variant)
+	}
+
+	return map[string]interface{}{
+		"prompt":   prompt,
+		"variants": variants,
+		"count":    len(variants),
+	}
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/pkg/client"
 )
 
 // completionCmd represents the completion command
@@ -15,6 +22,10 @@ var completionCmd = &cobra.Command{
 The completion script must be evaluated in your shell to provide completions.
 This can be done by including it in your shell's configuration file.
 
+Persona, profile and training file names and IDs complete from your account,
+using the cached persona and profile lists when they are fresh. Completion
+gives up after two seconds, so a slow network never blocks the shell.
+
 Examples:
   # Generate bash completion
   toneclone completion bash > /etc/bash_completion.d/toneclone
@@ -71,3 +82,172 @@ Quick Setup:
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
+
+// completionTimeout bounds dynamic completion, so a slow API, key command or
+// network never hangs the shell
+const completionTimeout = 2 * time.Second
+
+// completionItem is a persona, profile or file offered by dynamic completion
+type completionItem struct {
+	ID   string
+	Name string
+}
+
+// completionLister lists the items of the current account to complete
+type completionLister func(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]completionItem, error)
+
+// completePersonas lists personas, from the cache when it is fresh
+func completePersonas(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]completionItem, error) {
+	personas, err := listPersonas(ctx, apiClient, keyConfig)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]completionItem, len(personas))
+	for i, p := range personas {
+		items[i] = completionItem{ID: p.PersonaID, Name: p.Name}
+	}
+	return items, nil
+}
+
+// completeProfiles lists profiles, from the cache when it is fresh
+func completeProfiles(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]completionItem, error) {
+	profiles, err := listProfiles(ctx, apiClient, keyConfig)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]completionItem, len(profiles))
+	for i, p := range profiles {
+		items[i] = completionItem{ID: p.ProfileID, Name: p.Name}
+	}
+	return items, nil
+}
+
+// completeFiles lists training files. Only IDs name files, so the file name
+// is the description.
+func completeFiles(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]completionItem, error) {
+	files, err := apiClient.Training.ListFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]completionItem, len(files))
+	for i, f := range files {
+		items[i] = completionItem{ID: f.FileID, Name: f.FileName}
+	}
+	return items, nil
+}
+
+// completeNames returns a completion function offering the names of the
+// items, with their IDs as descriptions. Once the word being completed starts
+// an ID, IDs are offered instead. With multiple set, the last element of a
+// comma-separated list is completed.
+func completeNames(list completionLister, multiple bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return completeItems(list, multiple, false, toComplete)
+	}
+}
+
+// completeIDs is completeNames for items such as files that are named by ID
+// only
+func completeIDs(list completionLister, multiple bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return completeItems(list, multiple, true, toComplete)
+	}
+}
+
+// completeFirstArg completes the first positional argument of a command with
+// item names
+func completeFirstArg(list completionLister) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeItems(list, false, false, toComplete)
+	}
+}
+
+func completeItems(list completionLister, multiple, idsOnly bool, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp
+
+	prefix, word := "", toComplete
+	if multiple {
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix, word = toComplete[:i+1], toComplete[i+1:]
+		}
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+
+	items, err := listForCompletion(list)
+	if err != nil {
+		cobra.CompDebugln("Dynamic completion failed: "+err.Error(), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	useIDs := idsOnly
+	for _, item := range items {
+		if word != "" && strings.HasPrefix(item.ID, word) {
+			useIDs = true
+			break
+		}
+	}
+
+	// Leave out the elements already in the list
+	used := make(map[string]bool)
+	for _, element := range splitList(prefix) {
+		used[element] = true
+	}
+
+	var completions []cobra.Completion
+	for _, item := range items {
+		if used[item.ID] || used[item.Name] {
+			continue
+		}
+		if useIDs {
+			completions = append(completions, cobra.CompletionWithDesc(prefix+item.ID, item.Name))
+		} else {
+			completions = append(completions, cobra.CompletionWithDesc(prefix+item.Name, item.ID))
+		}
+	}
+	return completions, directive
+}
+
+// listForCompletion runs list for the current key profile, giving up after
+// completionTimeout. A key command or API call still running is abandoned
+// when the completion process exits.
+func listForCompletion(list completionLister) ([]completionItem, error) {
+	// A passphrase prompt would wait for input the shell never sends
+	config.PassphraseFunc = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	type result struct {
+		items []completionItem
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		items, err := func() ([]completionItem, error) {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load config: %w", err)
+			}
+			keyConfig, err := cfg.GetCurrentKey()
+			if err != nil {
+				return nil, err
+			}
+			apiClient, err := newAPIClient(keyConfig, completionTimeout)
+			if err != nil {
+				return nil, err
+			}
+			return list(ctx, apiClient, keyConfig)
+		}()
+		done <- result{items, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.items, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out after %s", completionTimeout)
+	}
+}
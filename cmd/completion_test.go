@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+func TestCompleteItems(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("TONECLONE_SYSTEM_CONFIG", filepath.Join(tmpDir, "system.yaml"))
+	t.Setenv("TONECLONE_API_KEY", "tc_test_completion")
+
+	list := func(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]completionItem, error) {
+		return []completionItem{{ID: "persona-1", Name: "Blogger"}, {ID: "persona-2", Name: "Writer"}}, nil
+	}
+
+	tests := []struct {
+		multiple   bool
+		idsOnly    bool
+		toComplete string
+		expected   []cobra.Completion
+		directive  cobra.ShellCompDirective
+	}{
+		{false, false, "", []cobra.Completion{"Blogger\tpersona-1", "Writer\tpersona-2"}, cobra.ShellCompDirectiveNoFileComp},
+		{false, false, "pers", []cobra.Completion{"persona-1\tBlogger", "persona-2\tWriter"}, cobra.ShellCompDirectiveNoFileComp},
+		{false, true, "", []cobra.Completion{"persona-1\tBlogger", "persona-2\tWriter"}, cobra.ShellCompDirectiveNoFileComp},
+		{true, false, "Blogger,", []cobra.Completion{"Blogger,Writer\tpersona-2"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace},
+		{true, false, "persona-2,persona-", []cobra.Completion{"persona-2,persona-1\tBlogger"}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace},
+	}
+
+	for _, test := range tests {
+		completions, directive := completeItems(list, test.multiple, test.idsOnly, test.toComplete)
+		if !reflect.DeepEqual(completions, test.expected) || directive != test.directive {
+			t.Errorf("Completing %q: expected %q (%d), got %q (%d)", test.toComplete, test.expected, test.directive, completions, directive)
+		}
+	}
+}
+
+func TestCompleteFirstArg(t *testing.T) {
+	list := func(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]completionItem, error) {
+		t.Error("Expected no listing once the argument is given")
+		return nil, nil
+	}
+	completions, directive := completeFirstArg(list)(&cobra.Command{}, []string{"persona-1"}, "")
+	if len(completions) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no completions, got %q (%d)", completions, directive)
+	}
+}
@@ -1,22 +1,24 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"text/tabwriter"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
 )
 
 var (
 	// Config command flags
-	configFormat string
 	configGlobal bool
+	configOrigin bool
+	configDryRun bool
 )
 
 // configCmd represents the config command
@@ -31,6 +33,7 @@ Examples:
   toneclone config show
   toneclone config list
   toneclone config validate
+  toneclone config migrate --dry-run
   toneclone config path`,
 }
 
@@ -42,9 +45,20 @@ var configShowCmd = &cobra.Command{
 
 Displays the active configuration including API keys, profiles, and settings.
 
+Settings are layered, each overriding the one before:
+  1. System file (/etc/toneclone/config.yaml or TONECLONE_SYSTEM_CONFIG)
+  2. User file (~/.toneclone.yaml or --config)
+  3. Project file (the nearest .toneclone.yaml at or above the working directory)
+  4. Environment variables
+  5. Flags
+
+Use --origin to see which layer set each value.
+
 Examples:
   toneclone config show
-  toneclone config show --format=json`,
+  toneclone config show --origin
+  toneclone config show -o json
+  toneclone config show --origin -o csv`,
 	RunE: runConfigShow,
 }
 
@@ -58,7 +72,8 @@ Shows all configured API keys and their associated profiles.
 
 Examples:
   toneclone config list
-  toneclone config list --format=json`,
+  toneclone config list -o yaml
+  toneclone config list --columns=name --no-headers`,
 	RunE: runConfigList,
 }
 
@@ -68,7 +83,8 @@ var configValidateCmd = &cobra.Command{
 	Short: "Validate configuration",
 	Long: `Validate the ToneClone CLI configuration.
 
-Checks configuration file syntax, API key validity, and profile settings.
+Checks configuration file syntax, API key validity, and profile settings,
+and warns about unknown keys.
 
 Examples:
   toneclone config validate`,
@@ -94,7 +110,11 @@ var configInitCmd = &cobra.Command{
 	Short: "Initialize configuration file",
 	Long: `Initialize a new ToneClone CLI configuration file.
 
-Creates a new configuration file with default settings.
+Without --global, creates a project file (.toneclone.yaml) in the current
+directory. Project files may only set the default key profile name and
+default write options; they never hold API keys, so they are safe to commit.
+
+With --global, creates the user file with default settings.
 
 Examples:
   toneclone config init
@@ -102,6 +122,80 @@ Examples:
 	RunE: runConfigInit,
 }
 
+// configGetCmd represents the get subcommand
+var configGetCmd = &cobra.Command{
+	Use:   "get <path>",
+	Short: "Print a configuration value",
+	Long: `Print the effective value of a configuration setting.
+
+Settings are addressed by dotted path, for example defaults.persona or
+keys.work.defaults.formality. Lists are printed comma-separated.
+
+Examples:
+  toneclone config get defaults.persona
+  toneclone config get keys.work.base_url`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+// configSetCmd represents the set subcommand
+var configSetCmd = &cobra.Command{
+	Use:   "set <path> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a configuration setting in your user config file.
+
+Write defaults can be set globally under defaults, or per key profile under
+keys.<profile>.defaults, which take precedence for that profile:
+
+  persona        Persona ID or name used when --persona is omitted
+  profiles       Comma-separated profile IDs or names
+  formality      Formality level
+  reading_level  Reading level
+  length         Length level
+  model          Model to generate with
+  output         Output format: text or json
+
+API keys and credential stores are managed by 'toneclone auth'.
+
+Examples:
+  toneclone config set defaults.persona Professional
+  toneclone config set defaults.profiles "Email,Concise"
+  toneclone config set keys.work.defaults.formality 8
+  toneclone config set default_timeout 60`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+// configUnsetCmd represents the unset subcommand
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <path>",
+	Short: "Remove a configuration value",
+	Long: `Remove a configuration setting from your user config file.
+
+Examples:
+  toneclone config unset defaults.persona
+  toneclone config unset keys.work.defaults`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigUnset,
+}
+
+// configMigrateCmd represents the migrate subcommand
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration file format",
+	Long: `Upgrade the user configuration file to the current schema version.
+
+The original file is kept next to it as <file>.v<version>.bak. Older files
+are also upgraded automatically the first time they are loaded, unless only
+their version key is behind. --dry-run shows the result with API keys and
+tokens hidden.
+
+Examples:
+  toneclone config migrate --dry-run
+  toneclone config migrate`,
+	RunE: runConfigMigrate,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 
@@ -111,33 +205,53 @@ func init() {
 	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(configPathCmd)
 	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configMigrateCmd)
 
 	// Show command flags
-	configShowCmd.Flags().StringVar(&configFormat, "format", "table", "output format: table, json")
+	addOutputFlags(configShowCmd)
+	configShowCmd.Flags().BoolVar(&configOrigin, "origin", false, "show every setting with the layer that set it")
 
 	// List command flags
-	configListCmd.Flags().StringVar(&configFormat, "format", "table", "output format: table, json")
+	addOutputFlags(configListCmd)
+	addQuietFlag(configListCmd)
+
+	// Migrate command flags
+	configMigrateCmd.Flags().BoolVar(&configDryRun, "dry-run", false, "show the migrated file without writing it")
 
 	// Init command flags
 	configInitCmd.Flags().BoolVar(&configGlobal, "global", false, "create global config file")
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	printConfigWarnings(cfg)
 
-	// Output configuration
-	if configFormat == "json" {
-		return outputConfigJSON(cfg)
+	if configOrigin {
+		return outputOrigins(printer, cfg)
 	}
 
-	return outputConfigTable(cfg)
+	// Output configuration
+	return outputConfig(printer, cfg)
 }
 
 func runConfigList(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -145,11 +259,7 @@ func runConfigList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output API keys
-	if configFormat == "json" {
-		return outputKeysJSON(cfg.Keys)
-	}
-
-	return outputKeysTable(cfg.Keys)
+	return outputKeys(printer, cfg)
 }
 
 func runConfigValidate(cmd *cobra.Command, args []string) error {
@@ -158,6 +268,7 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
+	printConfigWarnings(cfg)
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -180,8 +291,10 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Println("✓ Configuration is valid")
-	fmt.Printf("  Config file: %s\n", viper.ConfigFileUsed())
+	fmt.Println(emoji("✓ ", "") + "Configuration is valid")
+	for _, file := range cfg.Files() {
+		fmt.Printf("  Config file: %s\n", file)
+	}
 	fmt.Printf("  Current key: %s\n", cfg.DefaultKey)
 	fmt.Printf("  API keys: %d\n", len(cfg.Keys))
 
@@ -215,6 +328,16 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration file already exists: %s", configPath)
 	}
 
+	if !configGlobal {
+		if err := os.WriteFile(configPath, []byte(projectConfigTemplate), 0644); err != nil {
+			return fmt.Errorf("failed to write project config: %w", err)
+		}
+
+		fmt.Printf("%sProject configuration file created: %s\n", emoji("✓ ", ""), configPath)
+		fmt.Println("  Set a default persona and profiles for this directory, then commit it")
+		return nil
+	}
+
 	// Create default configuration
 	cfg := config.NewConfig()
 
@@ -223,115 +346,309 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save configuration: %w", err)
 	}
 
-	fmt.Printf("✓ Configuration file created: %s\n", configPath)
+	fmt.Printf("%sConfiguration file created: %s\n", emoji("✓ ", ""), configPath)
 	fmt.Println("  Add your first API key with: toneclone auth add")
 
 	return nil
 }
 
-func outputConfigTable(cfg *config.Config) error {
-	fmt.Printf("ToneClone CLI Configuration\n")
-	fmt.Printf("===========================\n")
-	fmt.Printf("Config File:  %s\n", viper.ConfigFileUsed())
-	fmt.Printf("Current Key:  %s\n", cfg.DefaultKey)
-	fmt.Printf("API Keys:     %d\n", len(cfg.Keys))
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
 
-	if len(cfg.Keys) > 0 {
-		fmt.Printf("\nAPI Keys:\n")
+	value, err := cfg.GetValue(args[0])
+	if err != nil {
+		return err
+	}
 
-		// Create table writer
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		defer w.Flush()
-
-		// Header
-		fmt.Fprintln(w, "NAME\tBASE URL\tCURRENT")
-		fmt.Fprintln(w, "----\t--------\t-------")
-
-		// Data
-		for name, keyConfig := range cfg.Keys {
-			current := ""
-			if name == cfg.DefaultKey {
-				current = "✓"
-			}
-
-			fmt.Fprintf(w, "%s\t%s\t%s\n",
-				name,
-				keyConfig.BaseURL,
-				current,
-			)
+	setting := redactSetting(config.Setting{Path: args[0], Value: value})
+	if list, ok := setting.Value.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
 		}
+		fmt.Println(strings.Join(items, ","))
+		return nil
 	}
 
+	fmt.Println(setting.Value)
 	return nil
 }
 
-func outputConfigJSON(cfg *config.Config) error {
-	// Create a sanitized version without API keys
-	sanitized := map[string]interface{}{
-		"config_file": viper.ConfigFileUsed(),
-		"current_key": cfg.DefaultKey,
-		"api_keys": func() map[string]interface{} {
-			keys := make(map[string]interface{})
-			for name, keyConfig := range cfg.Keys {
-				keys[name] = map[string]interface{}{
-					"base_url": keyConfig.BaseURL,
-					"key":      "***REDACTED***",
-				}
-			}
-			return keys
-		}(),
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	path, value := args[0], args[1]
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(sanitized)
+	if err := cfg.SetValue(path, value); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sSet %s\n", emoji("✓ ", ""), path)
+	warnIfOverridden(path)
+	return nil
 }
 
-func outputKeysTable(keys map[string]config.APIKeyConfig) error {
-	if len(keys) == 0 {
-		fmt.Println("No API keys configured.")
-		return nil
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
+	if err := cfg.UnsetValue(path); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
 
-	// Header
-	fmt.Fprintln(w, "NAME\tBASE URL\tKEY PREFIX")
-	fmt.Fprintln(w, "----\t--------\t----------")
+	fmt.Printf("%sUnset %s\n", emoji("✓ ", ""), path)
+	warnIfOverridden(path)
+	return nil
+}
 
-	// Data
-	for name, keyConfig := range keys {
-		keyPrefix := ""
-		if len(keyConfig.Key) > 8 {
-			keyPrefix = keyConfig.Key[:8] + "..."
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return fmt.Errorf("no configuration file at %s", configPath)
+	}
+
+	result, err := config.MigrateFile(configPath, configDryRun)
+	if err != nil {
+		return err
+	}
+
+	if !result.Changed() {
+		fmt.Printf("%s%s is already at config version %d\n", emoji("✓ ", ""), configPath, result.To)
+		return nil
+	}
+
+	if configDryRun {
+		fmt.Printf("Would migrate %s from version %d to %d:\n", configPath, result.From, result.To)
+		for _, step := range result.Applied {
+			fmt.Printf("  - %s\n", step)
+		}
+		data, err := config.RedactFile(result.Data)
+		if err != nil {
+			return err
 		}
+		fmt.Printf("\n%s", data)
+		return nil
+	}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\n",
-			name,
-			keyConfig.BaseURL,
-			keyPrefix,
-		)
+	fmt.Printf("%sMigrated %s from version %d to %d\n", emoji("✓ ", ""), configPath, result.From, result.To)
+	for _, step := range result.Applied {
+		fmt.Printf("  - %s\n", step)
 	}
+	fmt.Printf("  Backup: %s\n", result.Backup)
 
 	return nil
 }
 
-func outputKeysJSON(keys map[string]config.APIKeyConfig) error {
-	// Create a sanitized version without full API keys
+// warnIfOverridden notes when a layer above the user file still sets a
+// value that was just edited there
+func warnIfOverridden(path string) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return
+	}
+
+	switch origin := cfg.Origin(path); origin.Layer {
+	case config.LayerProject, config.LayerEnv, config.LayerFlag:
+		logger.Warn("Setting is still overridden", "key", path, "by", origin.String())
+	}
+}
+
+// projectConfigTemplate is written by 'config init' for project files
+const projectConfigTemplate = `# ToneClone project configuration
+#
+# Applies to commands run in this directory and below. Only default_key and
+# defaults may be set here; API keys, base URLs and environments belong in
+# ~/.toneclone.yaml.
+
+# Key profile to use (must exist in your user config)
+# default_key: work
+
+defaults:
+  # persona: Professional
+  # profiles: [Email]
+  # formality: 7
+  # length: 5
+`
+
+// printConfigWarnings reports problems found while loading the configuration
+func printConfigWarnings(cfg *config.Config) {
+	for _, warning := range cfg.Warnings() {
+		logger.Warn(warning)
+	}
+}
+
+// keyRow is a row of the API key tables
+type keyRow struct {
+	Name    string
+	Key     config.APIKeyConfig
+	Current bool
+}
+
+// keyRows returns the API keys sorted by name
+func keyRows(cfg *config.Config) []keyRow {
+	var rows []keyRow
+	for name, keyConfig := range cfg.Keys {
+		rows = append(rows, keyRow{Name: name, Key: keyConfig, Current: name == cfg.DefaultKey})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
+
+// keyColumns returns the columns of API key tables and CSV output. Config
+// show marks the current key and config list shows key prefixes; the other
+// column is wide.
+func keyColumns(showPrefix bool) []output.Column[keyRow] {
+	current := output.Column[keyRow]{Name: "current", Wide: showPrefix, Value: func(k keyRow) string {
+		if k.Current {
+			return emoji("✓", "*")
+		}
+		return ""
+	}}
+	prefix := output.Column[keyRow]{Name: "key_prefix", Wide: !showPrefix, Value: func(k keyRow) string {
+		if k.Key.IsStored() {
+			return "(" + k.Key.Store + ")"
+		} else if len(k.Key.Key) > 8 {
+			return k.Key.Key[:8] + "..."
+		}
+		return ""
+	}}
+
+	columns := []output.Column[keyRow]{
+		{Name: "name", Value: func(k keyRow) string { return k.Name }},
+		{Name: "base_url", Value: func(k keyRow) string { return k.Key.BaseURL }},
+		{Name: "env", Value: func(k keyRow) string { return k.Key.Env }},
+	}
+	if showPrefix {
+		return append(columns, prefix, current)
+	}
+	return append(columns, current, prefix)
+}
+
+// sanitizedKeys returns the API keys for structured output, without the
+// key values
+func sanitizedKeys(keys map[string]config.APIKeyConfig) map[string]interface{} {
 	sanitized := make(map[string]interface{})
 	for name, keyConfig := range keys {
 		sanitized[name] = map[string]interface{}{
 			"base_url": keyConfig.BaseURL,
+			"env":      keyConfig.Env,
 			"key":      "***REDACTED***",
 		}
 	}
+	return sanitized
+}
 
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(map[string]interface{}{
-		"api_keys": sanitized,
-		"count":    len(keys),
-	})
+func outputConfig(printer *output.Printer, cfg *config.Config) error {
+	// Create a sanitized version without API keys
+	sanitized := map[string]interface{}{
+		"config_file": viper.ConfigFileUsed(),
+		"current_key": cfg.DefaultKey,
+		"default_env": cfg.DefaultEnv,
+		"api_keys":    sanitizedKeys(cfg.Keys),
+	}
+
+	if printer.Tabular() && !printer.NoHeaders {
+		fmt.Printf("ToneClone CLI Configuration\n")
+		fmt.Printf("===========================\n")
+		fmt.Printf("Config File:  %s\n", viper.ConfigFileUsed())
+		fmt.Printf("Current Key:  %s\n", cfg.DefaultKey)
+		fmt.Printf("API Keys:     %d\n", len(cfg.Keys))
+
+		if len(cfg.Keys) == 0 {
+			return nil
+		}
+		fmt.Printf("\nAPI Keys:\n")
+	}
+
+	return output.List(printer, sanitized, keyRows(cfg), keyColumns(false))
+}
+
+func outputKeys(printer *output.Printer, cfg *config.Config) error {
+	if len(cfg.Keys) == 0 && printer.Tabular() {
+		fmt.Println("No API keys configured.")
+		return nil
+	}
+
+	return output.List(printer, map[string]interface{}{
+		"api_keys": sanitizedKeys(cfg.Keys),
+		"count":    len(cfg.Keys),
+	}, keyRows(cfg), keyColumns(true))
+}
+
+// settingColumns are the columns of 'config show --origin' tables and CSV output
+var settingColumns = []output.Column[config.Setting]{
+	{Name: "path", Value: func(s config.Setting) string { return s.Path }},
+	{Name: "value", Value: func(s config.Setting) string { return formatSettingValue(s.Value) }},
+	{Name: "origin", Value: func(s config.Setting) string { return s.Origin.String() }},
+}
+
+func outputOrigins(printer *output.Printer, cfg *config.Config) error {
+	settings, err := cfg.Settings()
+	if err != nil {
+		return err
+	}
+
+	for i := range settings {
+		settings[i] = redactSetting(settings[i])
+	}
+
+	if printer.Tabular() && !printer.NoHeaders {
+		for _, file := range cfg.Files() {
+			fmt.Printf("Loaded %s\n", file)
+		}
+		fmt.Println()
+	}
+
+	return output.List(printer, map[string]interface{}{
+		"files":    cfg.Files(),
+		"settings": settings,
+		"warnings": cfg.Warnings(),
+	}, settings, settingColumns)
+}
+
+// redactSetting hides API key values
+func redactSetting(setting config.Setting) config.Setting {
+	if strings.HasPrefix(setting.Path, "keys.") && strings.HasSuffix(setting.Path, ".key") {
+		setting.Value = "***REDACTED***"
+	}
+	return setting
+}
+
+func formatSettingValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(value)
 }
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+var (
+	debugFile string
+	harFile   string
+
+	// httpTracer logs API traffic when debugging; nil otherwise
+	httpTracer *client.Tracer
+	// traceFile is the open --debug-file, if any
+	traceFile *os.File
+)
+
+// startTracing sets up httpTracer. --debug logs API traffic to stderr,
+// --debug-file to a file instead, and --har also records it for a HAR file.
+func startTracing() error {
+	if httpTracer != nil {
+		return nil
+	}
+
+	logPath := viper.GetString("debug_file")
+	harPath := viper.GetString("har")
+	if !viper.GetBool("debug") && logPath == "" && harPath == "" {
+		return nil
+	}
+
+	tracer := &client.Tracer{}
+	switch {
+	case logPath != "":
+		file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open debug file: %w", err)
+		}
+		traceFile = file
+		tracer.Output = file
+	case viper.GetBool("debug"):
+		tracer.Output = os.Stderr
+	}
+	if harPath != "" {
+		tracer.HAR = client.NewHAR(Version)
+	}
+
+	httpTracer = tracer
+	return nil
+}
+
+// finishTracing closes the debug file and writes the HAR file. It runs
+// after failed commands too, which is when a trace is most useful.
+func finishTracing() error {
+	if httpTracer == nil {
+		return nil
+	}
+	defer func() { httpTracer = nil }()
+
+	if traceFile != nil {
+		traceFile.Close()
+		traceFile = nil
+	}
+
+	if httpTracer.HAR == nil {
+		return nil
+	}
+	harPath := viper.GetString("har")
+	if err := httpTracer.HAR.WriteFile(harPath); err != nil {
+		return err
+	}
+	logger.Info("Recorded requests to HAR file (secrets redacted)", "count", len(httpTracer.HAR.Entries()), "file", harPath)
+	return nil
+}
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/docs"
+	"github.com/toneclone/cli/internal/fsutil"
+	"github.com/toneclone/cli/internal/output"
+	"github.com/toneclone/cli/internal/postprocess"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+var (
+	// Docs command flags
+	docsManifest string
+	docsAll      bool
+	docsDryRun   bool
+	docsTimeout  int
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Keep generated documentation in sync with its recipe",
+	Long: `Track documentation files generated with ToneClone and detect drift.
+
+A manifest (default: ./toneclone-docs.yaml) maps each output file to the
+prompt, persona, profiles and generation settings that produce it:
+
+  docs:
+    - output: docs/intro.md
+      persona: Technical Writer
+      profiles: [Documentation]
+      prompt_file: prompts/intro.txt
+      format: markdown
+      wrap: 80
+      formality: 3
+
+A fingerprint of each recipe (including prompt file contents) is recorded in
+a lock file next to the manifest (toneclone-docs.yaml.lock). Commit both
+files alongside the generated docs.`,
+}
+
+// docsCheckCmd represents the docs check command
+var docsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail if generated docs are stale",
+	Long: `Compare each output file against the recipe that produced it.
+
+A doc is reported as:
+  stale     the prompt, persona, profiles or settings changed
+  missing   the output file does not exist
+  modified  the output was edited by hand after generation
+
+The check runs offline and exits non-zero if any doc is not fresh, so it can
+run in pre-commit hooks and CI.
+
+Examples:
+  toneclone docs check
+  toneclone docs check --manifest=site/toneclone-docs.yaml -o json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDocsCheck,
+}
+
+// docsRegenerateCmd represents the docs regenerate command
+var docsRegenerateCmd = &cobra.Command{
+	Use:   "regenerate [output]...",
+	Short: "Regenerate stale docs",
+	Long: `Regenerate docs whose recipe changed or whose output is missing, and
+record their new fingerprints in the lock file.
+
+Docs edited by hand are left alone unless named explicitly or --all is set.
+
+A doc that fails to regenerate does not stop the others; the command then
+exits with code 5, or with the error of the failure when every doc failed.
+
+Examples:
+  toneclone docs regenerate
+  toneclone docs regenerate --dry-run
+  toneclone docs regenerate docs/intro.md
+  toneclone docs regenerate --all`,
+	SilenceUsage: true,
+	Annotations:  requireScopes(client.ScopeTextGenerate, client.ScopePersonasRead, client.ScopeProfilesRead),
+	RunE:         runDocsRegenerate,
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+
+	docsCmd.AddCommand(docsCheckCmd)
+	docsCmd.AddCommand(docsRegenerateCmd)
+
+	docsCmd.PersistentFlags().StringVar(&docsManifest, "manifest", docs.DefaultManifestFile, "path to the docs manifest")
+
+	addOutputFlags(docsCheckCmd)
+
+	docsRegenerateCmd.Flags().BoolVar(&docsAll, "all", false, "regenerate every doc, including fresh and hand-edited ones")
+	docsRegenerateCmd.Flags().BoolVar(&docsDryRun, "dry-run", false, "show what would be regenerated without calling the API")
+	docsRegenerateCmd.Flags().IntVar(&docsTimeout, "timeout", 60, "timeout in seconds for each generation")
+}
+
+func loadDocsState() (*docs.Manifest, *docs.Lock, []docs.Result, error) {
+	manifest, err := docs.LoadManifest(docsManifest)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	lock, err := docs.LoadLock(manifest.LockPath())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	results, err := docs.Check(manifest, lock)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return manifest, lock, results, nil
+}
+
+func runDocsCheck(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	_, _, results, err := loadDocsState()
+	if err != nil {
+		return err
+	}
+
+	if err := outputDocsCheck(printer, results); err != nil {
+		return err
+	}
+
+	outdated := 0
+	for _, result := range results {
+		if !result.OK() {
+			outdated++
+		}
+	}
+	if outdated > 0 {
+		return fmt.Errorf("%d of %d doc(s) out of date; run 'toneclone docs regenerate'", outdated, len(results))
+	}
+
+	return nil
+}
+
+// docsColumns are the columns of docs check tables and CSV output
+var docsColumns = []output.Column[docs.Result]{
+	{Name: "output", Value: func(r docs.Result) string { return r.Output }},
+	{Name: "status", Value: func(r docs.Result) string { return r.Status }},
+	{Name: "reason", Value: func(r docs.Result) string { return r.Reason }},
+}
+
+func outputDocsCheck(printer *output.Printer, results []docs.Result) error {
+	if len(results) == 0 && printer.Tabular() {
+		fmt.Println("No docs listed in manifest.")
+		return nil
+	}
+	if results == nil {
+		results = []docs.Result{}
+	}
+
+	return output.List(printer, map[string]interface{}{
+		"docs":  results,
+		"count": len(results),
+	}, results, docsColumns)
+}
+
+func runDocsRegenerate(cmd *cobra.Command, args []string) error {
+	manifest, lock, results, err := loadDocsState()
+	if err != nil {
+		return err
+	}
+
+	targets, err := selectDocsTargets(results, args)
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		fmt.Println(emoji("✓ ", "") + "No stale docs to regenerate")
+		return nil
+	}
+
+	if docsDryRun {
+		for _, result := range targets {
+			fmt.Printf("would regenerate %s (%s)\n", result.Output, result.Status)
+		}
+		return nil
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Get current API key
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		return fmt.Errorf("authentication required: %w", err)
+	}
+
+	// Create API client
+	apiClient, err := newAPIClient(keyConfig, time.Duration(docsTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+
+	// A failed doc does not stop the others
+	var failed int
+	var first error
+	for _, result := range targets {
+		content, err := generateDoc(cmd.Context(), apiClient, keyConfig, manifest, result.Doc)
+		if err != nil {
+			err = fmt.Errorf("failed to regenerate %s: %w", result.Output, err)
+		} else if err = fsutil.WriteFileAtomic(manifest.Resolve(result.Output), content, 0644); err != nil {
+			err = fmt.Errorf("failed to write %s: %w", result.Output, err)
+		}
+		if err != nil {
+			fmt.Printf("%s%s\n", emoji("✗ ", ""), err)
+			failed++
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+
+		// Save after each doc so an interrupted run keeps its progress
+		lock.Record(result.Output, result.Recipe, content)
+		if err := lock.Save(manifest.LockPath()); err != nil {
+			return err
+		}
+
+		fmt.Printf("%sRegenerated %s\n", emoji("✓ ", ""), result.Output)
+	}
+
+	if removed := lock.Prune(manifest); len(removed) > 0 {
+		if err := lock.Save(manifest.LockPath()); err != nil {
+			return err
+		}
+		logger.Debug("Removed lock entries", "outputs", removed)
+	}
+
+	return batchError("docs", failed, len(targets), first)
+}
+
+// selectDocsTargets picks the docs to regenerate: named outputs, every doc
+// with --all, or otherwise only stale and missing ones
+func selectDocsTargets(results []docs.Result, outputs []string) ([]docs.Result, error) {
+	if len(outputs) > 0 {
+		byOutput := make(map[string]docs.Result)
+		for _, result := range results {
+			byOutput[result.Output] = result
+		}
+
+		var targets []docs.Result
+		for _, output := range outputs {
+			result, ok := byOutput[output]
+			if !ok {
+				return nil, fmt.Errorf("'%s' is not listed in the manifest", output)
+			}
+			targets = append(targets, result)
+		}
+		return targets, nil
+	}
+
+	var targets []docs.Result
+	for _, result := range results {
+		switch {
+		case docsAll:
+			targets = append(targets, result)
+		case result.Status == docs.StatusStale, result.Status == docs.StatusMissing:
+			targets = append(targets, result)
+		case result.Status == docs.StatusModified:
+			logger.Warn("Skipping file edited by hand (name it or use --all to overwrite)", "file", result.Output)
+		}
+	}
+	return targets, nil
+}
+
+// generateDoc runs a doc's recipe and returns the post-processed content
+func generateDoc(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig, manifest *docs.Manifest, doc docs.Doc) ([]byte, error) {
+	prompt, err := manifest.PromptText(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	persona, err := validatePersona(ctx, apiClient, keyConfig, doc.Persona)
+	if err != nil {
+		return nil, fmt.Errorf("persona validation failed: %w", err)
+	}
+
+	request := &client.GenerateTextRequest{
+		Prompt:       prompt,
+		PersonaID:    persona.PersonaID,
+		Formality:    doc.Formality,
+		ReadingLevel: doc.ReadingLevel,
+		Length:       doc.Length,
+		Model:        doc.Model,
+	}
+
+	for _, profileInput := range doc.Profiles {
+		profile, err := validateProfile(ctx, apiClient, keyConfig, profileInput)
+		if err != nil {
+			return nil, fmt.Errorf("profile validation failed for '%s': %w", profileInput, err)
+		}
+		request.ProfileIDs = append(request.ProfileIDs, profile.ProfileID)
+	}
+	if len(request.ProfileIDs) == 1 {
+		// Single profile - use legacy field for backward compatibility
+		request.ProfileID = request.ProfileIDs[0]
+		request.ProfileIDs = nil
+	}
+
+	logger.Debug("Generating doc", "file", doc.Output, "persona", persona.Name)
+
+	genCtx, cancel := context.WithTimeout(ctx, time.Duration(docsTimeout)*time.Second)
+	defer cancel()
+
+	response, err := apiClient.Generate.Text(genCtx, request)
+	if err != nil {
+		return nil, generationError(err)
+	}
+
+	text, err := postprocess.Apply(response.Text, postprocess.Options{
+		Format: doc.Format,
+		Wrap:   doc.Wrap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(text) > 0 && text[len(text)-1] != '\n' {
+		text += "\n"
+	}
+
+	return []byte(text), nil
+}
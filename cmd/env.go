@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
+)
+
+var (
+	// Env command flags
+	envBaseURL string
+	envTimeout int
+	envForce   bool
+)
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage API environments",
+	Long: `Manage named API environments such as prod, staging or a local mock server.
+
+An environment holds the base URL and timeout of a deployment. Key profiles
+can point at an environment instead of repeating its base URL. The
+environment in use is chosen by, in order:
+  1. The --env flag
+  2. The TONECLONE_ENV environment variable
+  3. The env of the current key profile
+  4. The default environment ('toneclone env use'), for key profiles with
+     no base URL of their own
+
+Examples:
+  toneclone env add staging --base-url https://staging.api.toneclone.ai
+  toneclone env add local --base-url http://localhost:8080 --timeout 5
+  toneclone env list
+  toneclone env use staging
+  toneclone personas list --env local`,
+}
+
+// envListCmd represents the env list subcommand
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List environments",
+	Long: `List configured environments and show which one is in use.
+
+Examples:
+  toneclone env list
+  toneclone env list -o json`,
+	RunE: runEnvList,
+}
+
+// envUseCmd represents the env use subcommand
+var envUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default environment",
+	Long: `Set the environment used by key profiles that name neither an environment
+nor a base URL. 'toneclone auth login' binds new key profiles to it.
+
+Examples:
+  toneclone env use staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvUse,
+}
+
+// envAddCmd represents the env add subcommand
+var envAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add an environment",
+	Long: `Add a named environment.
+
+Examples:
+  toneclone env add staging --base-url https://staging.api.toneclone.ai
+  toneclone env add local --base-url http://localhost:8080 --timeout 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvAdd,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envUseCmd)
+	envCmd.AddCommand(envAddCmd)
+
+	// List command flags
+	addOutputFlags(envListCmd)
+	addQuietFlag(envListCmd)
+
+	// Add command flags
+	envAddCmd.Flags().StringVar(&envBaseURL, "base-url", "", "base URL of the API")
+	envAddCmd.Flags().IntVar(&envTimeout, "timeout", 0, "request timeout in seconds (default: default_timeout setting)")
+	envAddCmd.Flags().BoolVar(&envForce, "force", false, "replace an existing environment")
+	envAddCmd.MarkFlagRequired("base-url")
+}
+
+// envRow is an environment in env list output
+type envRow struct {
+	Name    string
+	Env     config.EnvironmentConfig
+	Default bool
+	Current bool
+}
+
+// envColumns are the columns of env list tables and CSV output
+var envColumns = []output.Column[envRow]{
+	{Name: "name", Value: func(r envRow) string { return r.Name }},
+	{Name: "base_url", Value: func(r envRow) string { return r.Env.BaseURL }},
+	{Name: "timeout", Value: func(r envRow) string {
+		if r.Env.Timeout > 0 {
+			return fmt.Sprintf("%ds", r.Env.Timeout)
+		}
+		return "-"
+	}},
+	{Name: "default", Value: func(r envRow) string { return checkMark(r.Default) }},
+	{Name: "current", Value: func(r envRow) string { return checkMark(r.Current) }},
+}
+
+// checkMark marks true values in tables
+func checkMark(value bool) string {
+	if value {
+		return emoji("✓", "*")
+	}
+	return ""
+}
+
+func runEnvList(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	current := cfg.ResolveEnvironment(cfg.Keys[cfg.GetCurrentKeyName()])
+
+	names := make([]string, 0, len(cfg.Environments))
+	for name := range cfg.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]envRow, len(names))
+	for i, name := range names {
+		rows[i] = envRow{Name: name, Env: cfg.Environments[name], Default: name == cfg.DefaultEnv, Current: name == current.Name}
+	}
+
+	if len(rows) == 0 && printer.Tabular() {
+		fmt.Println("No environments configured.")
+		fmt.Println("Add one with: toneclone env add <name> --base-url <url>")
+		return nil
+	}
+
+	return output.List(printer, map[string]interface{}{
+		"items":   cfg.Environments,
+		"count":   len(cfg.Environments),
+		"default": cfg.DefaultEnv,
+		"current": current.Name,
+	}, rows, envColumns)
+}
+
+func runEnvUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.SetDefaultEnvironment(name); err != nil {
+		return err
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sDefault environment set to '%s'\n", emoji("✓ ", ""), name)
+
+	// The default does not apply to keys with an environment or base URL
+	keyName := cfg.GetCurrentKeyName()
+	if selection := cfg.ResolveEnvironment(cfg.Keys[keyName]); selection.Name != name {
+		logger.Info("Environment is still in use", "env", selection.Name, "source", selection.Source, "profile", keyName)
+	}
+
+	return nil
+}
+
+func runEnvAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	parsed, err := url.Parse(envBaseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid base URL '%s'", envBaseURL)
+	}
+	if envTimeout < 0 {
+		return fmt.Errorf("--timeout cannot be negative")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if _, exists := cfg.Environments[name]; exists && !envForce {
+		return fmt.Errorf("environment '%s' already exists, use --force to replace it", name)
+	}
+
+	cfg.AddEnvironment(name, config.EnvironmentConfig{
+		BaseURL: envBaseURL,
+		Timeout: envTimeout,
+	})
+
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sAdded environment '%s' (%s)\n", emoji("✓ ", ""), name, envBaseURL)
+	if cfg.DefaultEnv == "" {
+		fmt.Printf("  Use it by default with: toneclone env use %s\n", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// Exit codes, so scripts can tell failures apart
+const (
+	ExitOK          = 0
+	ExitError       = 1 // any other failure
+	ExitUsage       = 2 // invalid flags or arguments
+	ExitAuth        = 3 // no API key, or the API rejected it
+	ExitNotFound    = 4 // a persona, profile, file or other resource does not exist
+	ExitPartial     = 5 // some items of a batch failed
+	ExitRateLimited = 6 // the API rate limit was exceeded
+)
+
+// exitError is an error that ends the CLI with a specific exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode makes err end the CLI with code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// batchError reports failed of total items of a batch, named by noun. It
+// wraps first, the first failure, when every item failed, so its exit code
+// applies; otherwise it exits with ExitPartial.
+func batchError(noun string, failed, total int, first error) error {
+	switch {
+	case failed == 0:
+		return nil
+	case failed == total:
+		return fmt.Errorf("all %d %s failed: %w", total, noun, first)
+	default:
+		return withExitCode(ExitPartial, fmt.Errorf("%d of %d %s failed", failed, total, noun))
+	}
+}
+
+// usageError is an invalid flag or argument of a command
+type usageError struct {
+	cmd *cobra.Command
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if usageCommand(err) != nil {
+		return ExitUsage
+	}
+	if errors.Is(err, config.ErrNoAPIKey) {
+		return ExitAuth
+	}
+
+	switch client.StatusCode(err) {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitAuth
+	case http.StatusNotFound:
+		return ExitNotFound
+	case http.StatusTooManyRequests:
+		return ExitRateLimited
+	}
+	return ExitError
+}
+
+// markUsageErrors makes the flag and argument errors of cmd and its
+// subcommands usage errors
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return &usageError{cmd: c, err: err}
+	})
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if args := c.Args; args != nil {
+			c.Args = func(c *cobra.Command, a []string) error {
+				if err := args(c, a); err != nil {
+					return &usageError{cmd: c, err: err}
+				}
+				return nil
+			}
+		}
+		for _, child := range c.Commands() {
+			walk(child)
+		}
+	}
+	walk(cmd)
+}
+
+// outputUsageError makes an invalid -o or --columns value, which commands
+// only notice once they print, a usage error of cmd
+func outputUsageError(cmd *cobra.Command, err error) error {
+	var flagErr *output.FlagError
+	if errors.As(err, &flagErr) && usageCommand(err) == nil {
+		return &usageError{cmd: cmd, err: err}
+	}
+	return err
+}
+
+// printError prints the error ending the CLI, with a pointer to the help
+// of the command for usage errors
+func printError(err error) {
+	logger.Error(err.Error())
+
+	if cmd := usageCommand(err); cmd != nil {
+		logger.Info("Run '" + cmd.CommandPath() + " --help' for usage.")
+	}
+}
+
+// usageCommand returns the command whose usage err violates, or nil when
+// err is not a usage error. Cobra reports unknown commands as plain errors.
+func usageCommand(err error) *cobra.Command {
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return usageErr.cmd
+	}
+	if strings.HasPrefix(err.Error(), "unknown command ") {
+		return rootCmd
+	}
+	return nil
+}
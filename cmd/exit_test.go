@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitOK},
+		{errors.New("boom"), ExitError},
+		{withExitCode(ExitPartial, errors.New("2 of 3 files failed to upload")), ExitPartial},
+		{fmt.Errorf("failed to list personas: %w", withExitCode(ExitNotFound, errors.New("persona 'x' not found"))), ExitNotFound},
+		{&usageError{cmd: &cobra.Command{}, err: errors.New("unknown flag: --x")}, ExitUsage},
+		{errors.New(`unknown command "x" for "toneclone"`), ExitUsage},
+		{fmt.Errorf("failed to get API key: %w", config.ErrNoAPIKey), ExitAuth},
+		{client.ErrorResponse{ErrorMsg: "invalid key", StatusCode: 401}, ExitAuth},
+		{client.ErrorResponse{ErrorMsg: "forbidden", StatusCode: 403}, ExitAuth},
+		{fmt.Errorf("failed to get persona: %w", client.ErrorResponse{ErrorMsg: "not found", StatusCode: 404}), ExitNotFound},
+		{client.ErrorResponse{ErrorMsg: "server error", StatusCode: 500}, ExitError},
+		{&client.RateLimitError{ErrorResponse: client.ErrorResponse{Message: "slow down"}}, ExitRateLimited},
+	}
+
+	for _, test := range tests {
+		if code := ExitCode(test.err); code != test.expected {
+			t.Errorf("ExitCode(%v) = %d, expected %d", test.err, code, test.expected)
+		}
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	notFound := client.ErrorResponse{ErrorMsg: "not found", StatusCode: 404}
+	tests := []struct {
+		failed   int
+		total    int
+		expected int
+	}{
+		{0, 3, ExitOK},
+		{1, 3, ExitPartial},
+		// Every item failing keeps the exit code of the failure
+		{3, 3, ExitNotFound},
+	}
+	for _, test := range tests {
+		err := batchError("variants", test.failed, test.total, notFound)
+		if code := ExitCode(err); code != test.expected {
+			t.Errorf("%d of %d failed: expected exit code %d, got %d (%v)", test.failed, test.total, test.expected, code, err)
+		}
+	}
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	child := &cobra.Command{Use: "child", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+	root.AddCommand(child)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	markUsageErrors(root)
+
+	for _, args := range [][]string{{"child"}, {"child", "a", "--bogus"}} {
+		root.SetArgs(args)
+		if code := ExitCode(root.Execute()); code != ExitUsage {
+			t.Errorf("Args %v: expected exit code %d, got %d", args, ExitUsage, code)
+		}
+	}
+}
+
+func TestOutputUsageError(t *testing.T) {
+	cmd := &cobra.Command{Use: "list"}
+	err := outputUsageError(cmd, fmt.Errorf("failed to print: %w", &output.FlagError{Err: errors.New("unknown column 'bogus'")}))
+	if code := ExitCode(err); code != ExitUsage || usageCommand(err) != cmd {
+		t.Errorf("Expected a usage error of the command, got exit code %d (%v)", code, err)
+	}
+
+	if code := ExitCode(outputUsageError(cmd, errors.New("boom"))); code != ExitError {
+		t.Errorf("Expected other errors to keep their exit code, got %d", code)
+	}
+}
@@ -2,21 +2,24 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"os"
-	"text/tabwriter"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
 	"github.com/toneclone/cli/pkg/client"
 )
 
 var (
 	// Health command flags
-	healthFormat  string
 	healthVerbose bool
 	healthTimeout int
 )
@@ -33,7 +36,8 @@ Use this command to diagnose connectivity issues or service problems.
 Examples:
   toneclone health
   toneclone health --verbose
-  toneclone health --format=json
+  toneclone health -o json
+  toneclone health -o jsonpath='{.overall}'
   toneclone health --timeout=10`,
 	RunE: runHealth,
 }
@@ -64,7 +68,8 @@ user account status, and service availability.
 Examples:
   toneclone health status
   toneclone health status --verbose
-  toneclone health status --format=json`,
+  toneclone health status -o yaml
+  toneclone health status -o wide`,
 	RunE: runHealthStatus,
 }
 
@@ -91,7 +96,7 @@ func init() {
 	healthCmd.AddCommand(healthStatusCmd)
 
 	// Health command flags
-	healthCmd.Flags().StringVar(&healthFormat, "format", "table", "output format: table, json")
+	addOutputFlags(healthCmd)
 	healthCmd.Flags().BoolVar(&healthVerbose, "verbose", false, "verbose output")
 	healthCmd.Flags().IntVar(&healthTimeout, "timeout", 10, "timeout in seconds")
 
@@ -99,13 +104,17 @@ func init() {
 	pingCmd.Flags().IntVar(&healthTimeout, "timeout", 5, "timeout in seconds")
 
 	// Status command flags
-	healthStatusCmd.Flags().StringVar(&healthFormat, "format", "table", "output format: table, json")
+	addOutputFlags(healthStatusCmd)
 	healthStatusCmd.Flags().BoolVar(&healthVerbose, "verbose", false, "verbose output")
 	healthStatusCmd.Flags().IntVar(&healthTimeout, "timeout", 10, "timeout in seconds")
 }
 
 func runHealth(cmd *cobra.Command, args []string) error {
-	return performHealthChecks(false)
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	return performHealthChecks(printer, false)
 }
 
 func runPing(cmd *cobra.Command, args []string) error {
@@ -113,7 +122,11 @@ func runPing(cmd *cobra.Command, args []string) error {
 }
 
 func runHealthStatus(cmd *cobra.Command, args []string) error {
-	return performHealthChecks(true)
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	return performHealthChecks(printer, true)
 }
 
 func performPingCheck() error {
@@ -130,11 +143,10 @@ func performPingCheck() error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		time.Duration(healthTimeout)*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, time.Duration(healthTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
 
 	// Ping API
 	start := time.Now()
@@ -153,7 +165,7 @@ func performPingCheck() error {
 	return nil
 }
 
-func performHealthChecks(comprehensive bool) error {
+func performHealthChecks(printer *output.Printer, comprehensive bool) error {
 	result := &HealthResult{
 		Timestamp: time.Now(),
 		Checks:    []HealthCheck{},
@@ -164,8 +176,8 @@ func performHealthChecks(comprehensive bool) error {
 	if err != nil {
 		result.Overall = "CRITICAL"
 		result.Summary = "Failed to load configuration"
-		if healthFormat == "json" {
-			return outputHealthJSON(result)
+		if printer.Structured() {
+			return printer.PrintData(result)
 		}
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -175,25 +187,27 @@ func performHealthChecks(comprehensive bool) error {
 	if err != nil {
 		result.Overall = "CRITICAL"
 		result.Summary = "Authentication required"
-		if healthFormat == "json" {
-			return outputHealthJSON(result)
+		if printer.Structured() {
+			return printer.PrintData(result)
 		}
 		return fmt.Errorf("authentication required: %w", err)
 	}
 
+	// Network settings come first, as every other check depends on them
+	result.Checks = append(result.Checks, performNetworkChecks(keyConfig)...)
+
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		time.Duration(healthTimeout)*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, time.Duration(healthTimeout)*time.Second)
+	if err != nil {
+		return outputHealthResult(printer, result)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(healthTimeout)*time.Second)
 	defer cancel()
 
 	// Check 1: API Connectivity
 	check1 := performCheck("API Connectivity", func() error {
-		return apiClient.Ping(ctx)
+		return withNetworkHint(apiClient.Ping(ctx))
 	})
 	result.Checks = append(result.Checks, check1)
 
@@ -226,6 +240,11 @@ func performHealthChecks(comprehensive bool) error {
 		result.Checks = append(result.Checks, check5)
 	}
 
+	return outputHealthResult(printer, result)
+}
+
+// outputHealthResult sets the overall status from the checks and prints the result
+func outputHealthResult(printer *output.Printer, result *HealthResult) error {
 	// Determine overall status
 	result.Overall = "OK"
 	failedChecks := 0
@@ -243,11 +262,119 @@ func performHealthChecks(comprehensive bool) error {
 	}
 
 	// Output results
-	if healthFormat == "json" {
-		return outputHealthJSON(result)
+	if printer.Tabular() && !printer.NoHeaders {
+		fmt.Printf("ToneClone Health Check\n")
+		fmt.Printf("=====================\n")
+		fmt.Printf("Overall Status: %s\n", result.Overall)
+		fmt.Printf("Timestamp: %s\n", result.Timestamp.Format(time.RFC3339))
+		fmt.Printf("Summary: %s\n\n", result.Summary)
+	}
+
+	return output.List(printer, result, result.Checks, healthCheckColumns(printer))
+}
+
+// performNetworkChecks checks the TLS and proxy settings of a key: that the
+// CA bundle and client certificate load, and that the proxy is reachable
+func performNetworkChecks(keyConfig config.APIKeyConfig) []HealthCheck {
+	var checks []HealthCheck
+	network := keyConfig.Network
+
+	if !network.IsZero() {
+		details := map[string]interface{}{}
+		check := performCheck("Network Settings", func() error {
+			if _, err := network.ClientOptions(); err != nil {
+				return err
+			}
+			if network.CACert != "" {
+				details["ca_cert"] = network.CACert
+			}
+			if network.MinTLSVersion != "" {
+				details["min_tls_version"] = network.MinTLSVersion
+			}
+			if network.ClientCert != "" {
+				cert, err := network.ClientCertificate()
+				if err != nil {
+					return err
+				}
+				if cert.Leaf != nil {
+					details["client_cert"] = cert.Leaf.Subject.String()
+					details["client_cert_expires"] = cert.Leaf.NotAfter
+				}
+			}
+			return nil
+		})
+		if len(details) > 0 {
+			check.Details = details
+		}
+		checks = append(checks, check)
+	}
+
+	// The proxy is the configured one, or the one from HTTPS_PROXY
+	proxyURL, err := healthProxyURL(keyConfig)
+	if err != nil || proxyURL == nil {
+		return checks
 	}
 
-	return outputHealthTable(result)
+	check := performCheck("Proxy", func() error {
+		address := proxyURL.Host
+		if proxyURL.Port() == "" {
+			port := "80"
+			switch proxyURL.Scheme {
+			case "https":
+				port = "443"
+			case "socks5", "socks5h":
+				port = "1080"
+			}
+			address = net.JoinHostPort(proxyURL.Hostname(), port)
+		}
+
+		conn, err := net.DialTimeout("tcp", address, time.Duration(healthTimeout)*time.Second)
+		if err != nil {
+			return fmt.Errorf("proxy unreachable: %w", err)
+		}
+		return conn.Close()
+	})
+	check.Details = map[string]string{"proxy": proxyURL.Redacted()}
+	checks = append(checks, check)
+
+	return checks
+}
+
+// healthProxyURL returns the proxy requests to the key's base URL go through
+func healthProxyURL(keyConfig config.APIKeyConfig) (*url.URL, error) {
+	req, err := http.NewRequest(http.MethodGet, keyConfig.BaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.MatchNoProxyURL(req.URL, keyConfig.Network.NoProxy) {
+		return nil, nil
+	}
+	if keyConfig.Network.Proxy == "" {
+		return http.ProxyFromEnvironment(req)
+	}
+	return client.ParseProxyURL(keyConfig.Network.Proxy)
+}
+
+// withNetworkHint adds the setting that usually fixes a TLS error
+func withNetworkHint(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var certInvalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &unknownAuthority):
+		return fmt.Errorf("%w (if a proxy inspects TLS traffic, trust its CA with --ca-cert or network.ca_cert)", err)
+	case errors.As(err, &certInvalid), errors.As(err, &hostname):
+		return fmt.Errorf("%w (check the base URL and network.ca_cert)", err)
+	case strings.Contains(err.Error(), "protocol version"):
+		return fmt.Errorf("%w (check network.min_tls_version)", err)
+	case strings.Contains(err.Error(), "certificate required"), strings.Contains(err.Error(), "bad certificate"):
+		return fmt.Errorf("%w (the server may require a client certificate: network.client_cert)", err)
+	}
+	return err
 }
 
 func performCheck(name string, checkFunc func() error) HealthCheck {
@@ -270,45 +397,18 @@ func performCheck(name string, checkFunc func() error) HealthCheck {
 	return check
 }
 
-func outputHealthTable(result *HealthResult) error {
-	// Overall status
-	fmt.Printf("ToneClone Health Check\n")
-	fmt.Printf("=====================\n")
-	fmt.Printf("Overall Status: %s\n", result.Overall)
-	fmt.Printf("Timestamp: %s\n", result.Timestamp.Format(time.RFC3339))
-	fmt.Printf("Summary: %s\n\n", result.Summary)
-
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
-
-	// Header
-	fmt.Fprintln(w, "CHECK\tSTATUS\tDURATION\tERROR")
-	fmt.Fprintln(w, "-----\t------\t--------\t-----")
-
-	// Data
-	for _, check := range result.Checks {
-		errorMsg := ""
-		if check.Error != "" {
-			errorMsg = check.Error
-			if len(errorMsg) > 50 {
-				errorMsg = errorMsg[:47] + "..."
+// healthCheckColumns returns the columns of health check tables and CSV
+// output. Errors are shortened in the default table only.
+func healthCheckColumns(printer *output.Printer) []output.Column[HealthCheck] {
+	return []output.Column[HealthCheck]{
+		{Name: "check", Value: func(c HealthCheck) string { return c.Name }},
+		{Name: "status", Value: func(c HealthCheck) string { return c.Status }},
+		{Name: "duration", Value: func(c HealthCheck) string { return c.Duration.Round(time.Millisecond).String() }},
+		{Name: "error", Value: func(c HealthCheck) string {
+			if printer.Format == output.Table && len(c.Error) > 50 {
+				return c.Error[:47] + "..."
 			}
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%v\t%s\n",
-			check.Name,
-			check.Status,
-			check.Duration.Round(time.Millisecond),
-			errorMsg,
-		)
+			return c.Error
+		}},
 	}
-
-	return nil
-}
-
-func outputHealthJSON(result *HealthResult) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(result)
 }
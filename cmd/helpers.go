@@ -4,35 +4,81 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/toneclone/cli/internal/config"
 	"github.com/toneclone/cli/pkg/client"
 )
 
-// validatePersona validates a persona by ID or name and returns the persona object
-func validatePersona(ctx context.Context, apiClient *client.ToneCloneClient, personaInput string) (*client.Persona, error) {
-	// First try to get by ID (this will work for both user and built-in personas)
-	persona, err := apiClient.Personas.Get(ctx, personaInput)
-	if err == nil {
-		return persona, nil
+// newAPIClient creates an API client for a key profile as returned by
+// GetCurrentKey, including its TLS and proxy settings. A zero timeout uses
+// the profile's own.
+func newAPIClient(keyConfig config.APIKeyConfig, timeout time.Duration) (*client.ToneCloneClient, error) {
+	if timeout == 0 {
+		timeout = time.Duration(keyConfig.Timeout) * time.Second
 	}
 
-	// If that fails, try to find by name in both user and built-in personas
-	userPersonas, err := apiClient.Personas.List(ctx)
+	options, err := clientOptions(keyConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list user personas: %w", err)
+		return nil, err
 	}
+	options = append(options, client.WithBaseURL(keyConfig.BaseURL), client.WithTimeout(timeout))
+	if keyConfig.IsOAuth() {
+		options = append(options, client.WithTokenSource(profileTokenSource(keyConfig)))
+	}
+
+	return client.NewToneCloneClient(keyConfig.Key, options...), nil
+}
 
-	builtInPersonas, err := apiClient.Personas.ListBuiltIn(ctx)
+// clientOptions returns the HTTP options shared by every client of a key
+// profile: its TLS and proxy settings, the logger, the debug tracer and any
+// cassette
+func clientOptions(keyConfig config.APIKeyConfig) ([]client.ClientOption, error) {
+	options, err := keyConfig.Network.ClientOptions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list built-in personas: %w", err)
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	options = append(options, client.WithLogger(logger))
+	if httpTracer != nil {
+		options = append(options, client.WithTracer(httpTracer))
+	}
+
+	cassette, err := cassetteFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if cassette != nil {
+		options = append(options, client.WithCassette(cassette))
+	}
+	return options, nil
+}
+
+// validatePersona validates a persona by ID or name and returns the persona object
+func validatePersona(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig, personaInput string) (*client.Persona, error) {
+	// A name in the cached listing needs only a lookup by ID
+	if id := cachedPersonaID(keyConfig, personaInput); id != "" {
+		if persona, err := apiClient.Personas.Get(ctx, id); err == nil {
+			return persona, nil
+		}
 	}
 
-	// Mark built-in personas and combine lists
-	for i := range builtInPersonas {
-		builtInPersonas[i].IsBuiltIn = true
+	// Then try to get by ID (this will work for both user and built-in personas)
+	persona, err := apiClient.Personas.Get(ctx, personaInput)
+	if err == nil {
+		return persona, nil
 	}
-	allPersonas := append(userPersonas, builtInPersonas...)
 
+	// If that fails, try to find by name in both user and built-in personas
+	personas, err := fetchPersonas(ctx, apiClient, keyConfig)
+	if err != nil {
+		return nil, err
+	}
+	return matchPersona(personas, personaInput)
+}
+
+// matchPersona finds a persona by exact ID, exact name or a unique partial
+// name. A terminal user chooses between several partial matches.
+func matchPersona(allPersonas []client.Persona, personaInput string) (*client.Persona, error) {
 	// Look for exact ID match first
 	for _, p := range allPersonas {
 		if p.PersonaID == personaInput {
@@ -56,10 +102,14 @@ func validatePersona(ctx context.Context, apiClient *client.ToneCloneClient, per
 	}
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("persona '%s' not found", personaInput)
+		return nil, withExitCode(ExitNotFound, fmt.Errorf("persona '%s' not found", personaInput))
 	}
 
 	if len(matches) > 1 {
+		if canPick() {
+			return pickPersona(fmt.Sprintf("Multiple personas match '%s', select one:", personaInput), matches)
+		}
+
 		var names []string
 		for _, p := range matches {
 			source := "user"
@@ -75,19 +125,31 @@ func validatePersona(ctx context.Context, apiClient *client.ToneCloneClient, per
 }
 
 // validateProfile validates a profile by ID or name and returns the profile object
-func validateProfile(ctx context.Context, apiClient *client.ToneCloneClient, profileInput string) (*client.Profile, error) {
-	// First try to get by ID
+func validateProfile(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig, profileInput string) (*client.Profile, error) {
+	// A name in the cached listing needs only a lookup by ID
+	if id := cachedProfileID(keyConfig, profileInput); id != "" {
+		if profile, err := apiClient.Profiles.Get(ctx, id); err == nil {
+			return profile, nil
+		}
+	}
+
+	// Then try to get by ID
 	profile, err := apiClient.Profiles.Get(ctx, profileInput)
 	if err == nil {
 		return profile, nil
 	}
 
 	// If that fails, try to find by name
-	profiles, err := apiClient.Profiles.List(ctx)
+	profiles, err := fetchProfiles(ctx, apiClient, keyConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list profiles: %w", err)
+		return nil, err
 	}
+	return matchProfile(profiles, profileInput)
+}
 
+// matchProfile finds a profile by exact name or a unique partial name. A
+// terminal user chooses between several partial matches.
+func matchProfile(profiles []client.Profile, profileInput string) (*client.Profile, error) {
 	// Look for exact name match
 	for _, p := range profiles {
 		if strings.EqualFold(p.Name, profileInput) {
@@ -104,10 +166,14 @@ func validateProfile(ctx context.Context, apiClient *client.ToneCloneClient, pro
 	}
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("profile '%s' not found", profileInput)
+		return nil, withExitCode(ExitNotFound, fmt.Errorf("profile '%s' not found", profileInput))
 	}
 
 	if len(matches) > 1 {
+		if canPick() {
+			return pickProfile(fmt.Sprintf("Multiple profiles match '%s', select one:", profileInput), matches)
+		}
+
 		var names []string
 		for _, p := range matches {
 			names = append(names, fmt.Sprintf("'%s' (%s)", p.Name, p.ProfileID))
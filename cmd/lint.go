@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/lint"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// defaultLintRulesFile is picked up from the working directory when --rules is not set
+const defaultLintRulesFile = ".toneclone-lint.yaml"
+
+var (
+	// Lint command flags
+	lintProfile string
+	lintRules   string
+	lintFormat  string
+	lintStrict  bool
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint <file>...",
+	Short: "Check text against a profile's style rules",
+	Long: `Check human-written text against style rules, locally.
+
+Rules are derived from the instructions of the given writing profiles
+(e.g. "avoid 'synergy'", "keep sentences under 20 words", "avoid passive
+voice", "grade 8 reading level") and merged with a local rules file.
+Use "-" to read from stdin.
+
+Rules File (YAML, default: ./.toneclone-lint.yaml if present):
+  banned_words: [synergy, leverage]
+  required_terms: [ToneClone]
+  max_sentence_words: 25
+  max_passive_ratio: 0.2
+  max_reading_grade: 9
+
+The command exits non-zero when errors are found (or warnings, with --strict),
+so it can run in pre-commit hooks and CI.
+
+Examples:
+  toneclone lint draft.md --profile=brand
+  toneclone lint docs/*.md --rules=style.yaml --format=sarif > lint.sarif
+  cat draft.md | toneclone lint - --profile="Email,Brand" --format=json`,
+	Annotations:  requireFlagScopes("profile", client.ScopeProfilesRead),
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVar(&lintProfile, "profile", "", "profile ID or name to derive rules from (supports comma-separated multiple profiles)")
+	lintCmd.Flags().StringVar(&lintRules, "rules", "", "local rules file (default: ./"+defaultLintRulesFile+" if present)")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "output format: text, json, sarif")
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "exit non-zero on warnings as well as errors")
+	lintCmd.RegisterFlagCompletionFunc("profile", completeNames(completeProfiles, true))
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	switch lintFormat {
+	case "text", "json", "sarif":
+	default:
+		return fmt.Errorf("unsupported format '%s' (use text, json or sarif)", lintFormat)
+	}
+
+	rules, err := loadLintRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	var diags []lint.Diagnostic
+	for _, path := range args {
+		text, name, err := readLintInput(path)
+		if err != nil {
+			return err
+		}
+		diags = append(diags, lint.Lint(name, text, rules)...)
+	}
+
+	switch lintFormat {
+	case "json":
+		err = outputLintJSON(diags, rules)
+	case "sarif":
+		err = outputLintSARIF(diags)
+	default:
+		err = outputLintText(diags)
+	}
+	if err != nil {
+		return err
+	}
+
+	errors, warnings, _ := lint.Count(diags)
+	if errors > 0 || (lintStrict && warnings > 0) {
+		return fmt.Errorf("found %d error(s) and %d warning(s)", errors, warnings)
+	}
+
+	return nil
+}
+
+// loadLintRules merges rules derived from profiles with the local rules file
+func loadLintRules(cmd *cobra.Command) (lint.Rules, error) {
+	var rules lint.Rules
+
+	if lintProfile != "" {
+		// Load configuration
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return rules, fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current API key
+		keyConfig, err := cfg.GetCurrentKey()
+		if err != nil {
+			return rules, fmt.Errorf("authentication required: %w", err)
+		}
+
+		// Create API client
+		apiClient, err := newAPIClient(keyConfig, 0)
+		if err != nil {
+			return rules, err
+		}
+
+		for _, profileInput := range splitList(lintProfile) {
+			profile, err := validateProfile(cmd.Context(), apiClient, keyConfig, profileInput)
+			if err != nil {
+				return rules, fmt.Errorf("profile validation failed for '%s': %w", profileInput, err)
+			}
+			rules = lint.Merge(rules, lint.RulesFromInstructions(profile.Instructions))
+		}
+	}
+
+	rulesPath := lintRules
+	if rulesPath == "" {
+		if _, err := os.Stat(defaultLintRulesFile); err == nil {
+			rulesPath = defaultLintRulesFile
+		}
+	}
+
+	if rulesPath != "" {
+		fileRules, err := lint.LoadRules(rulesPath)
+		if err != nil {
+			return rules, err
+		}
+		rules = lint.Merge(rules, fileRules)
+	}
+
+	logger.Debug("Lint rules", "rules", fmt.Sprintf("%+v", rules))
+
+	return rules, nil
+}
+
+func readLintInput(path string) (string, string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		return string(data), "<stdin>", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	return string(data), path, nil
+}
+
+func outputLintText(diags []lint.Diagnostic) error {
+	for _, d := range diags {
+		fmt.Println(d.String())
+	}
+
+	errors, warnings, infos := lint.Count(diags)
+	if len(diags) == 0 {
+		fmt.Fprintln(os.Stderr, emoji("✓ ", "")+"No problems found")
+	} else {
+		fmt.Fprintf(os.Stderr, "%d error(s), %d warning(s), %d info\n", errors, warnings, infos)
+	}
+
+	return nil
+}
+
+func outputLintJSON(diags []lint.Diagnostic, rules lint.Rules) error {
+	if diags == nil {
+		diags = []lint.Diagnostic{}
+	}
+
+	errors, warnings, infos := lint.Count(diags)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{
+		"rules":       rules,
+		"diagnostics": diags,
+		"summary": map[string]int{
+			"errors":   errors,
+			"warnings": warnings,
+			"info":     infos,
+		},
+	})
+}
+
+func outputLintSARIF(diags []lint.Diagnostic) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(lint.ToSARIF(diags, Version))
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/toneclone/cli/internal/config"
+)
+
+var (
+	logLevel  string
+	logFormat string
+
+	// logger receives diagnostics, which always go to stderr so stdout
+	// holds only command results. setupLogging replaces it once flags and
+	// config are read.
+	logger = newLogger(os.Stderr, slog.LevelInfo, "text")
+)
+
+// setupLogging configures logger from --log-level and --log-format, or
+// log_level and log_format in config
+func setupLogging() error {
+	configured, err := loggerFor(viper.GetString("log_level"), viper.GetString("log_format"), viper.GetBool("verbose") || viper.GetBool("debug"))
+	if err != nil {
+		return err
+	}
+	logger = configured
+	return nil
+}
+
+// loggerFor returns a stderr logger for a level and format. Without a level
+// it logs info, or debug when verbose.
+func loggerFor(name, format string, verbose bool) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	switch {
+	case name != "":
+		if !slices.Contains(config.LogLevels, strings.ToLower(name)) {
+			return nil, fmt.Errorf("invalid log level '%s' (use %s)", name, strings.Join(config.LogLevels, ", "))
+		}
+		level.UnmarshalText([]byte(name))
+	case verbose:
+		level = slog.LevelDebug
+	}
+
+	if format == "" {
+		format = "text"
+	}
+	if !slices.Contains(config.LogFormats, format) {
+		return nil, fmt.Errorf("invalid log format '%s' (use %s)", format, strings.Join(config.LogFormats, " or "))
+	}
+
+	return newLogger(os.Stderr, level, format), nil
+}
+
+func newLogger(w io.Writer, level slog.Level, format string) *slog.Logger {
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	}
+	return slog.New(&textHandler{w: w, level: level, mu: &sync.Mutex{}})
+}
+
+// textHandler writes records for people: the message with a level prefix
+// for warnings and errors, then its attributes as key=value pairs
+type textHandler struct {
+	w      io.Writer
+	level  slog.Level
+	attrs  string
+	prefix string
+	mu     *sync.Mutex
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		b.WriteString("Error: ")
+	case record.Level >= slog.LevelWarn:
+		b.WriteString("Warning: ")
+	case record.Level < slog.LevelInfo:
+		b.WriteString("Debug: ")
+	}
+	b.WriteString(record.Message)
+	b.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&b, h.prefix, attr)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, attr := range attrs {
+		appendAttr(&b, h.prefix, attr)
+	}
+	handler := *h
+	handler.attrs = b.String()
+	return &handler
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handler := *h
+	handler.prefix = h.prefix + name + "."
+	return &handler
+}
+
+// appendAttr writes attr as " key=value", quoting values with spaces
+func appendAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			appendAttr(b, prefix, member)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, attr.Key, value)
+}
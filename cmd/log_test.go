@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestTextHandler(t *testing.T) {
+	var out bytes.Buffer
+	log := newLogger(&out, slog.LevelInfo, "text")
+
+	log.Debug("Hidden")
+	log.Info("Found files to upload", "count", 2)
+	log.Warn("Candidate failed", "candidate", 1, "error", errors.New("timed out"))
+	log.With("profile", "work").WithGroup("request").Error("Failed", "path", "/personas", "body", "")
+
+	expected := "Found files to upload count=2\n" +
+		"Warning: Candidate failed candidate=1 error=\"timed out\"\n" +
+		"Error: Failed profile=work request.path=/personas request.body=\"\"\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestLoggerFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		verbose bool
+		level   slog.Level
+		wantErr bool
+	}{
+		{"", "", false, slog.LevelInfo, false},
+		{"", "", true, slog.LevelDebug, false},
+		{"warn", "", true, slog.LevelWarn, false},
+		{"ERROR", "json", false, slog.LevelError, false},
+		{"loud", "", false, 0, true},
+		{"", "xml", false, 0, true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		log, err := loggerFor(test.name, test.format, test.verbose)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Expected error for level %q format %q", test.name, test.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for level %q format %q: %v", test.name, test.format, err)
+			continue
+		}
+		if !log.Enabled(ctx, test.level) || (test.level > slog.LevelDebug && log.Enabled(ctx, test.level-4)) {
+			t.Errorf("Expected level %v for level %q verbose %v", test.level, test.name, test.verbose)
+		}
+	}
+}
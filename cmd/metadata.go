@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/toneclone/cli/internal/cache"
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// metadataCacheNamespace and metadataCacheTTL control how long persona and
+// profile listings are reused to resolve names to IDs
+const (
+	metadataCacheNamespace = "metadata"
+	metadataCacheTTL       = 5 * time.Minute
+)
+
+// refreshMetadata makes commands fetch persona and profile listings instead
+// of using the cache (--refresh)
+var refreshMetadata bool
+
+// metadataCache returns the cache for listings, or nil when it is off.
+// Cassettes hold only the commands' own traffic, which cached listings would
+// make differ from run to run.
+func metadataCache() *cache.Cache {
+	if usingCassette() {
+		return nil
+	}
+	c, err := cache.Default()
+	if err != nil {
+		logger.Debug("Metadata cache unavailable", "error", err)
+		return nil
+	}
+	return c
+}
+
+// metadataCacheKey identifies a listing of a key profile's account. OAuth
+// access tokens rotate, so those profiles are identified by name alone.
+func metadataCacheKey(keyConfig config.APIKeyConfig, listing string) string {
+	credential := keyConfig.Key
+	if keyConfig.IsOAuth() {
+		credential = "oauth"
+	}
+	return strings.Join([]string{listing, keyConfig.BaseURL, keyConfig.Profile, credential}, "\n")
+}
+
+// loadCachedListing loads a listing from the cache into v. It misses with
+// --refresh.
+func loadCachedListing(keyConfig config.APIKeyConfig, listing string, v interface{}) bool {
+	c := metadataCache()
+	if c == nil || refreshMetadata {
+		return false
+	}
+	ok, _ := c.Get(metadataCacheNamespace, metadataCacheKey(keyConfig, listing), metadataCacheTTL, v)
+	return ok
+}
+
+// storeListing caches a listing. The cache only speeds up later runs, so
+// failures are ignored.
+func storeListing(keyConfig config.APIKeyConfig, listing string, v interface{}) {
+	if c := metadataCache(); c != nil {
+		if err := c.Set(metadataCacheNamespace, metadataCacheKey(keyConfig, listing), v); err != nil {
+			logger.Debug("Failed to cache listing", "listing", listing, "error", err)
+		}
+	}
+}
+
+// invalidateListing drops a cached listing after a change to it
+func invalidateListing(keyConfig config.APIKeyConfig, listing string) {
+	if c := metadataCache(); c != nil {
+		if err := c.Delete(metadataCacheNamespace, metadataCacheKey(keyConfig, listing)); err != nil {
+			logger.Debug("Failed to invalidate listing", "listing", listing, "error", err)
+		}
+	}
+}
+
+// Listings stored in the metadata cache
+const (
+	personasListing = "personas"
+	profilesListing = "profiles"
+)
+
+// listPersonas returns the user and built-in personas of the account, from
+// the cache when it is fresh
+func listPersonas(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]client.Persona, error) {
+	var personas []client.Persona
+	if loadCachedListing(keyConfig, personasListing, &personas) {
+		return personas, nil
+	}
+	return fetchPersonas(ctx, apiClient, keyConfig)
+}
+
+// fetchPersonas lists the user and built-in personas of the account and
+// caches them, for the commands that show current data
+func fetchPersonas(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]client.Persona, error) {
+	userPersonas, err := apiClient.Personas.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user personas: %w", err)
+	}
+
+	builtInPersonas, err := apiClient.Personas.ListBuiltIn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list built-in personas: %w", err)
+	}
+
+	// Mark built-in personas and combine lists, with user personas first
+	for i := range builtInPersonas {
+		builtInPersonas[i].IsBuiltIn = true
+	}
+	personas := append(userPersonas, builtInPersonas...)
+
+	storeListing(keyConfig, personasListing, personas)
+	return personas, nil
+}
+
+// cachedPersonaID returns the ID of the persona with a name in the cached
+// listing, or "". Partial names are left to a fresh listing, which may hold
+// newer personas.
+func cachedPersonaID(keyConfig config.APIKeyConfig, name string) string {
+	var personas []client.Persona
+	if !loadCachedListing(keyConfig, personasListing, &personas) {
+		return ""
+	}
+	for _, p := range personas {
+		if strings.EqualFold(p.Name, name) {
+			return p.PersonaID
+		}
+	}
+	return ""
+}
+
+// listProfiles returns the profiles of the account, from the cache when it
+// is fresh
+func listProfiles(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]client.Profile, error) {
+	var profiles []client.Profile
+	if loadCachedListing(keyConfig, profilesListing, &profiles) {
+		return profiles, nil
+	}
+	return fetchProfiles(ctx, apiClient, keyConfig)
+}
+
+// fetchProfiles lists the profiles of the account and caches them
+func fetchProfiles(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig) ([]client.Profile, error) {
+	profiles, err := apiClient.Profiles.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	storeListing(keyConfig, profilesListing, profiles)
+	return profiles, nil
+}
+
+// cachedProfileID returns the ID of the profile with a name in the cached
+// listing, or ""
+func cachedProfileID(keyConfig config.APIKeyConfig, name string) string {
+	var profiles []client.Profile
+	if !loadCachedListing(keyConfig, profilesListing, &profiles) {
+		return ""
+	}
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p.ProfileID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/pkg/clienttest"
+)
+
+// requestPaths returns the paths requested from server since the last call
+func requestPaths(server *clienttest.Server, seen *int) []string {
+	var paths []string
+	for _, r := range server.Requests()[*seen:] {
+		paths = append(paths, r.Method+" "+r.Path)
+	}
+	*seen = len(server.Requests())
+	return paths
+}
+
+func TestValidatePersonaUsesCachedListing(t *testing.T) {
+	t.Setenv("TONECLONE_CACHE_DIR", t.TempDir())
+	t.Setenv("TONECLONE_RECORD", "")
+	t.Setenv("TONECLONE_REPLAY", "")
+
+	server, url := clienttest.NewTestServer(t)
+	apiClient := clienttest.NewClient(url)
+	keyConfig := config.APIKeyConfig{Key: clienttest.DefaultAPIKey, BaseURL: url, Profile: "default"}
+	ctx := context.Background()
+
+	blogger := server.AddPersona("Blogger")
+	seen := len(server.Requests())
+
+	// The first lookup of a partial name lists every persona
+	persona, err := validatePersona(ctx, apiClient, keyConfig, "blog")
+	if err != nil || persona.PersonaID != blogger.PersonaID {
+		t.Fatalf("Expected %s, got %v (%v)", blogger.PersonaID, persona, err)
+	}
+	if paths := requestPaths(server, &seen); len(paths) != 3 {
+		t.Errorf("Expected a lookup by ID and two listings, got %v", paths)
+	}
+
+	// Later lookups of the full name resolve it from the cache
+	if _, err := validatePersona(ctx, apiClient, keyConfig, "Blogger"); err != nil {
+		t.Fatalf("Failed to resolve from cache: %v", err)
+	}
+	if paths := requestPaths(server, &seen); len(paths) != 1 || paths[0] != "GET /personas/"+blogger.PersonaID {
+		t.Errorf("Expected a single lookup by ID, got %v", paths)
+	}
+
+	// Personas missing from the cache are still found
+	writer := server.AddPersona("Writer")
+	persona, err = validatePersona(ctx, apiClient, keyConfig, "Writer")
+	if err != nil || persona.PersonaID != writer.PersonaID {
+		t.Fatalf("Expected %s, got %v (%v)", writer.PersonaID, persona, err)
+	}
+	requestPaths(server, &seen)
+
+	// --refresh bypasses the cache
+	refreshMetadata = true
+	defer func() { refreshMetadata = false }()
+	if _, err := validatePersona(ctx, apiClient, keyConfig, "Blogger"); err != nil {
+		t.Fatalf("Failed to resolve with --refresh: %v", err)
+	}
+	if paths := requestPaths(server, &seen); len(paths) != 3 {
+		t.Errorf("Expected --refresh to list personas again, got %v", paths)
+	}
+}
+
+func TestInvalidateListing(t *testing.T) {
+	t.Setenv("TONECLONE_CACHE_DIR", t.TempDir())
+	t.Setenv("TONECLONE_RECORD", "")
+	t.Setenv("TONECLONE_REPLAY", "")
+
+	server, url := clienttest.NewTestServer(t)
+	apiClient := clienttest.NewClient(url)
+	keyConfig := config.APIKeyConfig{Key: clienttest.DefaultAPIKey, BaseURL: url, Profile: "default"}
+	ctx := context.Background()
+
+	server.AddProfile("Email", "Be brief")
+	if _, err := fetchProfiles(ctx, apiClient, keyConfig); err != nil {
+		t.Fatalf("Failed to list profiles: %v", err)
+	}
+
+	server.AddProfile("Blog", "Be friendly")
+	if profiles, _ := listProfiles(ctx, apiClient, keyConfig); len(profiles) != 1 {
+		t.Errorf("Expected the cached listing, got %d profiles", len(profiles))
+	}
+
+	invalidateListing(keyConfig, profilesListing)
+	if profiles, _ := listProfiles(ctx, apiClient, keyConfig); len(profiles) != 2 {
+		t.Errorf("Expected a fresh listing after invalidation, got %d profiles", len(profiles))
+	}
+
+	// Each account has its own listings
+	other := keyConfig
+	other.Profile = "other"
+	var cached []string
+	if loadCachedListing(other, profilesListing, &cached) {
+		t.Error("Expected no cached listing for another key profile")
+	}
+}
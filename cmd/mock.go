@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/pkg/clienttest"
+)
+
+var (
+	// Mock serve flags
+	mockAddr       string
+	mockLatency    time.Duration
+	mockErrorRate  float64
+	mockSeed       int64
+	mockRateLimit  int
+	mockRateWindow time.Duration
+	mockAPIKeys    []string
+	mockEmpty      bool
+	mockQuiet      bool
+)
+
+// mockCmd represents the mock command
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Run a fake ToneClone API for testing",
+	Long: `Run an in-memory fake of the ToneClone API for testing scripts and
+integrations without an account or network access.`,
+}
+
+// mockServeCmd represents the mock serve subcommand
+var mockServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the fake API",
+	Long: `Serve an in-memory fake of the ToneClone API until interrupted.
+
+The fake implements the endpoints the CLI uses: personas, profiles, files,
+text generation, training jobs and API keys. State is lost when it stops.
+Training jobs advance one step each time they are read. Any key starting
+with tc_ is accepted unless --api-key is given.
+
+Examples:
+  toneclone mock serve
+  toneclone mock serve --addr 127.0.0.1:9000 --latency 200ms
+  toneclone mock serve --error-rate 0.1 --seed 42
+  toneclone mock serve --rate-limit 10 --rate-window 1m
+
+  # In another shell
+  toneclone env add mock --base-url http://127.0.0.1:8089
+  TONECLONE_API_KEY=tc_test_mockserver toneclone personas list --env mock`,
+	Args: cobra.NoArgs,
+	RunE: runMockServe,
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+	mockCmd.AddCommand(mockServeCmd)
+
+	mockServeCmd.Flags().StringVar(&mockAddr, "addr", "127.0.0.1:8089", "address to listen on")
+	mockServeCmd.Flags().DurationVar(&mockLatency, "latency", 0, "delay added to every response")
+	mockServeCmd.Flags().Float64Var(&mockErrorRate, "error-rate", 0, "fraction of requests, 0 to 1, answered with a 500 error")
+	mockServeCmd.Flags().Int64Var(&mockSeed, "seed", 0, "seed for random errors (default: random)")
+	mockServeCmd.Flags().IntVar(&mockRateLimit, "rate-limit", 0, "requests allowed per key and window (default: unlimited)")
+	mockServeCmd.Flags().DurationVar(&mockRateWindow, "rate-window", time.Minute, "rate limit window")
+	mockServeCmd.Flags().StringSliceVar(&mockAPIKeys, "api-key", nil, "API keys to accept (default: any key starting with tc_)")
+	mockServeCmd.Flags().BoolVar(&mockEmpty, "empty", false, "start without built-in personas")
+	mockServeCmd.Flags().BoolVar(&mockQuiet, "quiet", false, "do not log requests")
+}
+
+func runMockServe(cmd *cobra.Command, args []string) error {
+	if mockErrorRate < 0 || mockErrorRate > 1 {
+		return fmt.Errorf("--error-rate must be between 0 and 1")
+	}
+
+	options := []clienttest.Option{
+		clienttest.WithLatency(mockLatency),
+		clienttest.WithErrorRate(mockErrorRate),
+		clienttest.WithRateLimit(mockRateLimit, mockRateWindow),
+	}
+	if cmd.Flags().Changed("seed") {
+		options = append(options, clienttest.WithSeed(mockSeed))
+	}
+	if len(mockAPIKeys) > 0 {
+		options = append(options, clienttest.WithAPIKeys(mockAPIKeys...))
+	}
+	if mockEmpty {
+		options = append(options, clienttest.WithoutSeedData())
+	}
+	if !mockQuiet {
+		options = append(options, clienttest.WithRequestLog(os.Stdout))
+	}
+
+	listener, err := net.Listen("tcp", mockAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", mockAddr, err)
+	}
+	server := &http.Server{Handler: clienttest.New(options...)}
+
+	apiKey := clienttest.DefaultAPIKey
+	if len(mockAPIKeys) > 0 {
+		apiKey = mockAPIKeys[0]
+	}
+	fmt.Printf("%sMock ToneClone API listening on http://%s\n", emoji("✓ ", ""), listener.Addr())
+	fmt.Printf("  Use it with: TONECLONE_API_KEY=%s TONECLONE_BASE_URL=http://%s toneclone ...\n", apiKey, listener.Addr())
+	fmt.Println("  Press Ctrl-C to stop")
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("mock server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to stop mock server: %w", err)
+	}
+	fmt.Println(emoji("✓ ", "") + "Mock server stopped")
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/toneclone/cli/internal/output"
+)
+
+var (
+	// Output flags shared by the commands that print results
+	outputFormat    string
+	outputNoHeaders bool
+	outputColumns   []string
+	outputQuiet     bool
+
+	// noEmoji replaces emoji status symbols with plain text
+	noEmoji bool
+)
+
+// addOutputFlags registers -o, --no-headers and --columns on cmd, plus the
+// deprecated --format alias of -o
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", output.Table, "output format: "+output.FormatHelp)
+	cmd.Flags().BoolVar(&outputNoHeaders, "no-headers", false, "omit the header row of table and CSV output")
+	cmd.Flags().StringSliceVar(&outputColumns, "columns", nil, "table and CSV columns to show, in order (e.g. name,id)")
+
+	cmd.Flags().StringVar(&outputFormat, "format", output.Table, "output format")
+	cmd.Flags().MarkDeprecated("format", "use -o/--output instead")
+}
+
+// addQuietFlag registers -q on cmd, which prints only IDs, one per line
+func addQuietFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&outputQuiet, "quiet", "q", false, "print only IDs, one per line")
+}
+
+// newPrinter returns a printer to stdout for the output flags
+func newPrinter() (*output.Printer, error) {
+	printer, err := output.NewPrinter(os.Stdout, outputFormat, outputNoHeaders, outputColumns)
+	if err != nil {
+		return nil, err
+	}
+	printer.Quiet = outputQuiet
+	return printer, nil
+}
+
+// setupEmoji turns emoji off for --no-emoji, no_emoji in config or NO_COLOR
+func setupEmoji() {
+	noEmoji = viper.GetBool("no_emoji") || os.Getenv("NO_COLOR") != ""
+}
+
+// emoji returns symbol, or plain when emoji are off
+func emoji(symbol, plain string) string {
+	if noEmoji {
+		return plain
+	}
+	return symbol
+}
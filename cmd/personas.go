@@ -2,23 +2,21 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
 	"github.com/toneclone/cli/pkg/client"
 )
 
 var (
 	// Persona command flags
-	personaFormat      string
 	personaSort        string
 	personaFilter      string
 	personaInteractive bool
@@ -60,8 +58,12 @@ Examples:
   toneclone personas list
   toneclone personas list --filter="professional"
   toneclone personas list --sort="name"
-  toneclone personas list --format="json"`,
-	RunE: runListPersonas,
+  toneclone personas list -o json
+  toneclone personas list -o wide
+  toneclone personas list -o jsonpath='{.personas[*].name}'
+  toneclone personas list --columns=name,id --no-headers`,
+	Annotations: requireScopes(client.ScopePersonasRead),
+	RunE:        runListPersonas,
 }
 
 // getPersonaCmd represents the get subcommand
@@ -74,9 +76,11 @@ Shows all metadata including training status, associated files, and usage statis
 
 Examples:
   toneclone personas get persona-id
-  toneclone personas get persona-id --format="json"`,
-	Args: cobra.ExactArgs(1),
-	RunE: runGetPersona,
+  toneclone personas get persona-id -o json
+  toneclone personas get persona-id -o yaml`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: requireScopes(client.ScopePersonasRead),
+	RunE:        runGetPersona,
 }
 
 // createPersonaCmd represents the create subcommand
@@ -92,7 +96,8 @@ Examples:
   toneclone personas create --name="Professional Writer"
   toneclone personas create --name="Casual Blogger" --preset="blogger"
   toneclone personas create --interactive`,
-	RunE: runCreatePersona,
+	Annotations: requireScopes(client.ScopePersonasWrite),
+	RunE:        runCreatePersona,
 }
 
 // updatePersonaCmd represents the update subcommand
@@ -106,8 +111,9 @@ Note: persona type is determined automatically by the system.
 
 Examples:
   toneclone personas update persona-id --name="New Name"`,
-	Args: cobra.ExactArgs(1),
-	RunE: runUpdatePersona,
+	Args:        cobra.ExactArgs(1),
+	Annotations: requireScopes(client.ScopePersonasWrite),
+	RunE:        runUpdatePersona,
 }
 
 // deletePersonaCmd represents the delete subcommand
@@ -122,8 +128,9 @@ will be disassociated (but not deleted).
 Examples:
   toneclone personas delete persona-id
   toneclone personas delete persona-id --confirm`,
-	Args: cobra.ExactArgs(1),
-	RunE: runDeletePersona,
+	Args:        cobra.ExactArgs(1),
+	Annotations: requireScopes(client.ScopePersonasWrite),
+	RunE:        runDeletePersona,
 }
 
 func init() {
@@ -137,28 +144,40 @@ func init() {
 	personasCmd.AddCommand(deletePersonaCmd)
 
 	// List command flags
-	listPersonasCmd.Flags().StringVar(&personaFormat, "format", "table", "output format: table, json")
+	addOutputFlags(listPersonasCmd)
+	addQuietFlag(listPersonasCmd)
 	listPersonasCmd.Flags().StringVar(&personaSort, "sort", "last_used", "sort by: name, type, status, last_used, created")
 	listPersonasCmd.Flags().StringVar(&personaFilter, "filter", "", "filter personas by name or type")
 
 	// Get command flags
-	getPersonaCmd.Flags().StringVar(&personaFormat, "format", "table", "output format: table, json")
+	addOutputFlags(getPersonaCmd)
+	addQuietFlag(getPersonaCmd)
+	getPersonaCmd.ValidArgsFunction = completeFirstArg(completePersonas)
 
 	// Create command flags
 	createPersonaCmd.Flags().StringVar(&personaName, "name", "", "persona name")
 	createPersonaCmd.Flags().StringVar(&personaPresetID, "preset", "", "preset ID to use for persona creation")
 	createPersonaCmd.Flags().BoolVar(&personaInteractive, "interactive", false, "interactive persona creation")
-	createPersonaCmd.Flags().StringVar(&personaFormat, "format", "table", "output format: table, json")
+	addOutputFlags(createPersonaCmd)
+	addQuietFlag(createPersonaCmd)
 
 	// Update command flags
 	updatePersonaCmd.Flags().StringVar(&personaName, "name", "", "new persona name")
-	updatePersonaCmd.Flags().StringVar(&personaFormat, "format", "table", "output format: table, json")
+	addOutputFlags(updatePersonaCmd)
+	addQuietFlag(updatePersonaCmd)
+	updatePersonaCmd.ValidArgsFunction = completeFirstArg(completePersonas)
 
 	// Delete command flags
 	deletePersonaCmd.Flags().BoolVar(&personaConfirm, "confirm", false, "skip confirmation prompt")
+	deletePersonaCmd.ValidArgsFunction = completeFirstArg(completePersonas)
 }
 
 func runListPersonas(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -172,32 +191,17 @@ func runListPersonas(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
-
-	// Get personas (both user and built-in)
-	ctx := context.Background()
-	userPersonas, err := apiClient.Personas.List(ctx)
+	apiClient, err := newAPIClient(keyConfig, 0)
 	if err != nil {
-		return fmt.Errorf("failed to list user personas: %w", err)
+		return err
 	}
 
-	builtInPersonas, err := apiClient.Personas.ListBuiltIn(ctx)
+	// Get personas (both user and built-in), refreshing the cached listing
+	personas, err := fetchPersonas(context.Background(), apiClient, keyConfig)
 	if err != nil {
-		return fmt.Errorf("failed to list built-in personas: %w", err)
-	}
-
-	// Mark built-in personas and combine lists
-	for i := range builtInPersonas {
-		builtInPersonas[i].IsBuiltIn = true
+		return err
 	}
 
-	// Combine personas with user personas first
-	personas := append(userPersonas, builtInPersonas...)
-
 	// Filter personas
 	if personaFilter != "" {
 		personas = filterPersonas(personas, personaFilter)
@@ -207,16 +211,17 @@ func runListPersonas(cmd *cobra.Command, args []string) error {
 	sortPersonas(personas, personaSort)
 
 	// Output personas
-	if personaFormat == "json" {
-		return outputPersonasJSON(personas)
-	}
-
-	return outputPersonasTable(personas)
+	return outputPersonas(printer, personas)
 }
 
 func runGetPersona(cmd *cobra.Command, args []string) error {
 	personaID := args[0]
 
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -230,31 +235,33 @@ func runGetPersona(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Get persona (supports both name and ID)
 	ctx := context.Background()
-	persona, err := validatePersona(ctx, apiClient, personaID)
+	persona, err := validatePersona(ctx, apiClient, keyConfig, personaID)
 	if err != nil {
 		return fmt.Errorf("failed to get persona: %w", err)
 	}
 
 	// Output persona
-	if personaFormat == "json" {
-		return outputPersonaJSON(persona)
-	}
-
-	return outputPersonaDetails(persona)
+	return output.Object(printer, persona, *persona, personaColumns, func() error {
+		return outputPersonaDetails(persona)
+	})
 }
 
 func runCreatePersona(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Interactive mode
 	if personaInteractive {
-		return runInteractivePersonaCreation()
+		return runInteractivePersonaCreation(printer)
 	}
 
 	// Validate required flags
@@ -275,11 +282,10 @@ func runCreatePersona(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Create persona
 	persona := &client.Persona{
@@ -291,13 +297,15 @@ func runCreatePersona(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create persona: %w", err)
 	}
+	invalidateListing(keyConfig, personasListing)
 
-	fmt.Printf("✓ Persona '%s' created successfully\n", created.Name)
-	fmt.Printf("  ID: %s\n", created.PersonaID)
-	fmt.Printf("  Type: %s\n", created.PersonaType)
-	fmt.Printf("  Status: %s\n", created.Status)
-
-	return nil
+	return output.Object(printer, created, *created, personaColumns, func() error {
+		fmt.Printf("%sPersona '%s' created successfully\n", emoji("✓ ", ""), created.Name)
+		fmt.Printf("  ID: %s\n", created.PersonaID)
+		fmt.Printf("  Type: %s\n", created.PersonaType)
+		fmt.Printf("  Status: %s\n", created.Status)
+		return nil
+	})
 }
 
 func runUpdatePersona(cmd *cobra.Command, args []string) error {
@@ -308,6 +316,11 @@ func runUpdatePersona(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("at least one update flag must be provided (--name)")
 	}
 
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -321,16 +334,15 @@ func runUpdatePersona(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
 	// Get existing persona
-	existing, err := validatePersona(ctx, apiClient, personaID)
+	existing, err := validatePersona(ctx, apiClient, keyConfig, personaID)
 	if err != nil {
 		return fmt.Errorf("failed to get persona: %w", err)
 	}
@@ -345,13 +357,15 @@ func runUpdatePersona(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to update persona: %w", err)
 	}
+	invalidateListing(keyConfig, personasListing)
 
-	fmt.Printf("✓ Persona updated successfully\n")
-	fmt.Printf("  Name: %s\n", updated.Name)
-	fmt.Printf("  Type: %s\n", updated.PersonaType)
-	fmt.Printf("  Status: %s\n", updated.Status)
-
-	return nil
+	return output.Object(printer, updated, *updated, personaColumns, func() error {
+		fmt.Printf("%sPersona updated successfully\n", emoji("✓ ", ""))
+		fmt.Printf("  Name: %s\n", updated.Name)
+		fmt.Printf("  Type: %s\n", updated.PersonaType)
+		fmt.Printf("  Status: %s\n", updated.Status)
+		return nil
+	})
 }
 
 func runDeletePersona(cmd *cobra.Command, args []string) error {
@@ -370,16 +384,15 @@ func runDeletePersona(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
 	// Get persona info for confirmation
-	persona, err := validatePersona(ctx, apiClient, personaID)
+	persona, err := validatePersona(ctx, apiClient, keyConfig, personaID)
 	if err != nil {
 		return fmt.Errorf("failed to get persona: %w", err)
 	}
@@ -400,12 +413,13 @@ func runDeletePersona(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete persona: %w", err)
 	}
+	invalidateListing(keyConfig, personasListing)
 
-	fmt.Printf("✓ Persona '%s' deleted successfully\n", persona.Name)
+	fmt.Printf("%sPersona '%s' deleted successfully\n", emoji("✓ ", ""), persona.Name)
 	return nil
 }
 
-func runInteractivePersonaCreation() error {
+func runInteractivePersonaCreation(printer *output.Printer) error {
 	fmt.Println("Interactive Persona Creation")
 	fmt.Println("============================")
 
@@ -433,11 +447,10 @@ func runInteractivePersonaCreation() error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Create persona
 	persona := &client.Persona{
@@ -449,13 +462,15 @@ func runInteractivePersonaCreation() error {
 	if err != nil {
 		return fmt.Errorf("failed to create persona: %w", err)
 	}
+	invalidateListing(keyConfig, personasListing)
 
-	fmt.Printf("\n✓ Persona '%s' created successfully\n", created.Name)
-	fmt.Printf("  ID: %s\n", created.PersonaID)
-	fmt.Printf("  Type: %s\n", created.PersonaType)
-	fmt.Printf("  Status: %s\n", created.Status)
-
-	return nil
+	return output.Object(printer, created, *created, personaColumns, func() error {
+		fmt.Printf("\n%sPersona '%s' created successfully\n", emoji("✓ ", ""), created.Name)
+		fmt.Printf("  ID: %s\n", created.PersonaID)
+		fmt.Printf("  Type: %s\n", created.PersonaType)
+		fmt.Printf("  Status: %s\n", created.Status)
+		return nil
+	})
 }
 
 func filterPersonas(personas []client.Persona, filter string) []client.Persona {
@@ -504,48 +519,37 @@ func sortPersonas(personas []client.Persona, sortBy string) {
 	}
 }
 
-func outputPersonasTable(personas []client.Persona) error {
-	if len(personas) == 0 {
+// personaColumns are the columns of persona tables and CSV output
+var personaColumns = []output.Column[client.Persona]{
+	{Name: "name", Value: func(p client.Persona) string { return p.Name }},
+	{Name: "type", Value: func(p client.Persona) string { return p.PersonaType }},
+	{Name: "status", Value: func(p client.Persona) string { return p.Status }},
+	{Name: "training", Value: func(p client.Persona) string { return p.TrainingStatus }},
+	{Name: "last_used", Value: func(p client.Persona) string { return formatTime(p.LastUsedAt) }},
+	{Name: "source", Value: personaSource},
+	{Name: "id", Value: func(p client.Persona) string { return p.PersonaID }},
+	{Name: "voice_evolution", Wide: true, Value: func(p client.Persona) string { return strconv.FormatBool(p.VoiceEvolution) }},
+	{Name: "last_modified", Wide: true, Value: func(p client.Persona) string { return formatTime(p.LastModifiedAt) }},
+}
+
+func outputPersonas(printer *output.Printer, personas []client.Persona) error {
+	if len(personas) == 0 && printer.Tabular() {
 		fmt.Println("No personas found.")
 		return nil
 	}
 
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
-
-	// Header
-	fmt.Fprintln(w, "NAME\tTYPE\tSTATUS\tTRAINING\tLAST USED\tSOURCE\tID")
-	fmt.Fprintln(w, "----\t----\t------\t--------\t---------\t------\t--")
-
-	// Rows
-	for _, persona := range personas {
-		lastUsed := formatTime(persona.LastUsedAt)
-		source := "User"
-		if persona.IsBuiltIn {
-			source = "Built-in"
-		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			persona.Name,
-			persona.PersonaType,
-			persona.Status,
-			persona.TrainingStatus,
-			lastUsed,
-			source,
-			persona.PersonaID,
-		)
-	}
-
-	return nil
-}
-
-func outputPersonasJSON(personas []client.Persona) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(map[string]interface{}{
+	return output.List(printer, map[string]interface{}{
 		"personas": personas,
 		"count":    len(personas),
-	})
+	}, personas, personaColumns)
+}
+
+// personaSource tells user personas from built-in ones
+func personaSource(persona client.Persona) string {
+	if persona.IsBuiltIn {
+		return "Built-in"
+	}
+	return "User"
 }
 
 func outputPersonaDetails(persona *client.Persona) error {
@@ -557,11 +561,7 @@ func outputPersonaDetails(persona *client.Persona) error {
 	fmt.Printf("Status:           %s\n", persona.Status)
 	fmt.Printf("Training Status:  %s\n", persona.TrainingStatus)
 	fmt.Printf("Voice Evolution:  %t\n", persona.VoiceEvolution)
-	source := "User"
-	if persona.IsBuiltIn {
-		source = "Built-in"
-	}
-	fmt.Printf("Source:           %s\n", source)
+	fmt.Printf("Source:           %s\n", personaSource(*persona))
 	fmt.Printf("Last Used:        %s\n", formatTime(persona.LastUsedAt))
 	fmt.Printf("Last Modified:    %s\n", formatTime(persona.LastModifiedAt))
 
@@ -572,12 +572,6 @@ func outputPersonaDetails(persona *client.Persona) error {
 	return nil
 }
 
-func outputPersonaJSON(persona *client.Persona) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(persona)
-}
-
 func formatTime(t time.Time) string {
 	if t.IsZero() {
 		return "Never"
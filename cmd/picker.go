@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/picker"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// canPick reports whether a picker can be shown: stdin and stderr must both
+// be terminals, so scripts and pipes keep getting errors instead
+func canPick() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// requireFlag fails with a usage error when a flag the command needs is
+// empty and no picker can be shown in its place
+func requireFlag(cmd *cobra.Command, flag, value string) error {
+	if value == "" && !canPick() {
+		return &usageError{cmd: cmd, err: fmt.Errorf("--%s is required", flag)}
+	}
+	return nil
+}
+
+// pick shows a picker for items on stderr and returns the chosen one
+func pick[T any](prompt string, items []T, item func(T) picker.Item) (*T, error) {
+	entries := make([]picker.Item, len(items))
+	for i := range items {
+		entries[i] = item(items[i])
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	p := picker.New(prompt, entries)
+	p.Plain = noEmoji
+	if width, height, err := term.GetSize(int(os.Stderr.Fd())); err == nil {
+		p.Width = width
+		// Keep the prompt, the count and a line of context on screen
+		p.Height = max(1, min(p.Height, height-3))
+	}
+
+	index, err := p.Run(os.Stdin, os.Stderr)
+	if errors.Is(err, picker.ErrCanceled) {
+		return nil, errors.New("no selection made")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &items[index], nil
+}
+
+// pickPersona lets the user choose one of personas
+func pickPersona(prompt string, personas []client.Persona) (*client.Persona, error) {
+	return pick(prompt, personas, func(p client.Persona) picker.Item {
+		return picker.Item{Label: p.Name, Detail: p.PersonaID + " (" + personaSource(p) + ")"}
+	})
+}
+
+// pickProfile lets the user choose one of profiles
+func pickProfile(prompt string, profiles []client.Profile) (*client.Profile, error) {
+	return pick(prompt, profiles, func(p client.Profile) picker.Item {
+		return picker.Item{Label: p.Name, Detail: p.ProfileID}
+	})
+}
+
+// selectPersona resolves a persona like validatePersona. Without input it
+// lets the user choose from every persona, so callers check canPick first.
+func selectPersona(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig, personaInput string) (*client.Persona, error) {
+	if personaInput != "" {
+		return validatePersona(ctx, apiClient, keyConfig, personaInput)
+	}
+
+	personas, err := listPersonas(ctx, apiClient, keyConfig)
+	if err != nil {
+		return nil, err
+	}
+	return pickPersona("Select a persona:", personas)
+}
+
+// selectProfile resolves a profile like validateProfile. Without input it
+// lets the user choose from every profile, so callers check canPick first.
+func selectProfile(ctx context.Context, apiClient *client.ToneCloneClient, keyConfig config.APIKeyConfig, profileInput string) (*client.Profile, error) {
+	if profileInput != "" {
+		return validateProfile(ctx, apiClient, keyConfig, profileInput)
+	}
+
+	profiles, err := listProfiles(ctx, apiClient, keyConfig)
+	if err != nil {
+		return nil, err
+	}
+	return pickProfile("Select a profile:", profiles)
+}
+
+// pickTrainingFile lets the user choose one of the training files
+func pickTrainingFile(ctx context.Context, apiClient *client.ToneCloneClient) (*client.TrainingFile, error) {
+	files, err := apiClient.Training.ListFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list training files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, withExitCode(ExitNotFound, errors.New("no training files found"))
+	}
+
+	return pick("Select a training file:", files, func(f client.TrainingFile) picker.Item {
+		return picker.Item{Label: f.FileName, Detail: f.FileID}
+	})
+}
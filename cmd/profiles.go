@@ -2,23 +2,19 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
-	"text/tabwriter"
-	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
 	"github.com/toneclone/cli/pkg/client"
 )
 
 var (
 	// Profile command flags
-	profileFormat       string
 	profileSort         string
 	profileFilter       string
 	profileInteractive  bool
@@ -61,8 +57,10 @@ Examples:
   toneclone profiles list
   toneclone profiles list --filter="email"
   toneclone profiles list --sort="name"
-  toneclone profiles list --format="json"`,
-	RunE: runListProfiles,
+  toneclone profiles list -o json
+  toneclone profiles list -o csv --columns=name,instructions`,
+	Annotations: requireScopes(client.ScopeProfilesRead),
+	RunE:        runListProfiles,
 }
 
 // getProfileCmd represents the get subcommand
@@ -76,9 +74,11 @@ Shows all metadata including instructions, creation date, and usage information.
 Examples:
   toneclone profiles get "Email Template"
   toneclone profiles get profile-id
-  toneclone profiles get "Email Template" --format="json"`,
-	Args: cobra.ExactArgs(1),
-	RunE: runGetProfile,
+  toneclone profiles get "Email Template" -o json
+  toneclone profiles get "Email Template" -o jsonpath='{.instructions}'`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: requireScopes(client.ScopeProfilesRead),
+	RunE:        runGetProfile,
 }
 
 // createProfileCmd represents the create subcommand
@@ -94,7 +94,8 @@ Examples:
   toneclone profiles create --name="Email" --instructions="Write professional emails"
   toneclone profiles create --name="Blog Post" --instructions="Write engaging blog posts"
   toneclone profiles create --interactive`,
-	RunE: runCreateProfile,
+	Annotations: requireScopes(client.ScopeProfilesWrite),
+	RunE:        runCreateProfile,
 }
 
 // updateProfileCmd represents the update subcommand
@@ -109,8 +110,9 @@ Examples:
   toneclone profiles update "Email Template" --name="New Name"
   toneclone profiles update profile-id --instructions="New instructions"
   toneclone profiles update "Email Template" --append=" Also include examples."`,
-	Args: cobra.ExactArgs(1),
-	RunE: runUpdateProfile,
+	Args:        cobra.ExactArgs(1),
+	Annotations: requireScopes(client.ScopeProfilesWrite),
+	RunE:        runUpdateProfile,
 }
 
 // deleteProfileCmd represents the delete subcommand
@@ -125,8 +127,9 @@ Examples:
   toneclone profiles delete "Email Template"
   toneclone profiles delete profile-id
   toneclone profiles delete "Email Template" --confirm`,
-	Args: cobra.ExactArgs(1),
-	RunE: runDeleteProfile,
+	Args:        cobra.ExactArgs(1),
+	Annotations: requireScopes(client.ScopeProfilesWrite),
+	RunE:        runDeleteProfile,
 }
 
 // associateProfileCmd represents the associate subcommand
@@ -141,7 +144,8 @@ Both profile and persona can be specified by name or ID.
 Examples:
   toneclone profiles associate --profile="Email Template" --persona=Professional
   toneclone profiles associate --profile=profile-id --persona=persona-id`,
-	RunE: runAssociateProfile,
+	Annotations: requireScopes(client.ScopeProfilesWrite, client.ScopePersonasRead),
+	RunE:        runAssociateProfile,
 }
 
 // disassociateProfileCmd represents the disassociate subcommand
@@ -156,7 +160,8 @@ Both profile and persona can be specified by name or ID.
 Examples:
   toneclone profiles disassociate --profile="Email Template" --persona=Professional
   toneclone profiles disassociate --profile=profile-id --persona=persona-id`,
-	RunE: runDisassociateProfile,
+	Annotations: requireScopes(client.ScopeProfilesWrite, client.ScopePersonasRead),
+	RunE:        runDisassociateProfile,
 }
 
 func init() {
@@ -172,42 +177,54 @@ func init() {
 	profilesCmd.AddCommand(disassociateProfileCmd)
 
 	// List command flags
-	listProfilesCmd.Flags().StringVar(&profileFormat, "format", "table", "output format: table, json")
+	addOutputFlags(listProfilesCmd)
+	addQuietFlag(listProfilesCmd)
 	listProfilesCmd.Flags().StringVar(&profileSort, "sort", "created", "sort by: name, created, updated")
 	listProfilesCmd.Flags().StringVar(&profileFilter, "filter", "", "filter profiles by name")
 
 	// Get command flags
-	getProfileCmd.Flags().StringVar(&profileFormat, "format", "table", "output format: table, json")
+	addOutputFlags(getProfileCmd)
+	getProfileCmd.ValidArgsFunction = completeFirstArg(completeProfiles)
+	addQuietFlag(getProfileCmd)
 
 	// Create command flags
 	createProfileCmd.Flags().StringVar(&profileName, "name", "", "profile name")
 	createProfileCmd.Flags().StringVar(&profileInstructions, "instructions", "", "profile instructions")
 	createProfileCmd.Flags().BoolVar(&profileInteractive, "interactive", false, "interactive profile creation")
-	createProfileCmd.Flags().StringVar(&profileFormat, "format", "table", "output format: table, json")
+	addOutputFlags(createProfileCmd)
+	addQuietFlag(createProfileCmd)
 
 	// Update command flags
 	updateProfileCmd.Flags().StringVar(&profileName, "name", "", "new profile name")
 	updateProfileCmd.Flags().StringVar(&profileInstructions, "instructions", "", "new profile instructions")
 	updateProfileCmd.Flags().StringVar(&profileAppend, "append", "", "append text to existing instructions")
-	updateProfileCmd.Flags().StringVar(&profileFormat, "format", "table", "output format: table, json")
+	updateProfileCmd.ValidArgsFunction = completeFirstArg(completeProfiles)
+	addOutputFlags(updateProfileCmd)
+	addQuietFlag(updateProfileCmd)
 
 	// Delete command flags
 	deleteProfileCmd.Flags().BoolVar(&profileConfirm, "confirm", false, "skip confirmation prompt")
+	deleteProfileCmd.ValidArgsFunction = completeFirstArg(completeProfiles)
 
 	// Associate command flags
 	associateProfileCmd.Flags().StringVar(&profilePersona, "persona", "", "persona name or ID")
 	associateProfileCmd.Flags().StringVar(&profileName, "profile", "", "profile name or ID to associate")
-	associateProfileCmd.MarkFlagRequired("persona")
-	associateProfileCmd.MarkFlagRequired("profile")
+	associateProfileCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
+	associateProfileCmd.RegisterFlagCompletionFunc("profile", completeNames(completeProfiles, false))
 
 	// Disassociate command flags
 	disassociateProfileCmd.Flags().StringVar(&profilePersona, "persona", "", "persona name or ID")
 	disassociateProfileCmd.Flags().StringVar(&profileName, "profile", "", "profile name or ID to disassociate")
-	disassociateProfileCmd.MarkFlagRequired("persona")
-	disassociateProfileCmd.MarkFlagRequired("profile")
+	disassociateProfileCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
+	disassociateProfileCmd.RegisterFlagCompletionFunc("profile", completeNames(completeProfiles, false))
 }
 
 func runListProfiles(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -221,17 +238,15 @@ func runListProfiles(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
-	// Get profiles
-	ctx := context.Background()
-	profiles, err := apiClient.Profiles.List(ctx)
+	// Get profiles, refreshing the cached listing
+	profiles, err := fetchProfiles(context.Background(), apiClient, keyConfig)
 	if err != nil {
-		return fmt.Errorf("failed to list profiles: %w", err)
+		return err
 	}
 
 	// Filter profiles
@@ -243,16 +258,17 @@ func runListProfiles(cmd *cobra.Command, args []string) error {
 	sortProfiles(profiles, profileSort)
 
 	// Output profiles
-	if profileFormat == "json" {
-		return outputProfilesJSON(profiles)
-	}
-
-	return outputProfilesTable(profiles)
+	return outputProfiles(printer, profiles)
 }
 
 func runGetProfile(cmd *cobra.Command, args []string) error {
 	profileInput := args[0]
 
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -266,31 +282,33 @@ func runGetProfile(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Validate and get profile by ID or name
 	ctx := context.Background()
-	profile, err := validateProfile(ctx, apiClient, profileInput)
+	profile, err := validateProfile(ctx, apiClient, keyConfig, profileInput)
 	if err != nil {
 		return fmt.Errorf("profile validation failed: %w", err)
 	}
 
 	// Output profile
-	if profileFormat == "json" {
-		return outputProfileJSON(profile)
-	}
-
-	return outputProfileDetails(profile)
+	return output.Object(printer, profile, *profile, profileColumns(printer), func() error {
+		return outputProfileDetails(profile)
+	})
 }
 
 func runCreateProfile(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Interactive mode
 	if profileInteractive {
-		return runInteractiveProfileCreation()
+		return runInteractiveProfileCreation(printer)
 	}
 
 	// Validate required flags
@@ -314,11 +332,10 @@ func runCreateProfile(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Create profile
 	profile := &client.Profile{
@@ -331,12 +348,14 @@ func runCreateProfile(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create profile: %w", err)
 	}
+	invalidateListing(keyConfig, profilesListing)
 
-	fmt.Printf("✓ Profile '%s' created successfully\n", created.Name)
-	fmt.Printf("  ID: %s\n", created.ProfileID)
-	fmt.Printf("  Instructions: %s\n", created.Instructions)
-
-	return nil
+	return output.Object(printer, created, *created, profileColumns(printer), func() error {
+		fmt.Printf("%sProfile '%s' created successfully\n", emoji("✓ ", ""), created.Name)
+		fmt.Printf("  ID: %s\n", created.ProfileID)
+		fmt.Printf("  Instructions: %s\n", created.Instructions)
+		return nil
+	})
 }
 
 func runUpdateProfile(cmd *cobra.Command, args []string) error {
@@ -352,6 +371,11 @@ func runUpdateProfile(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--instructions and --append cannot be used together")
 	}
 
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -365,16 +389,15 @@ func runUpdateProfile(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
 	// Validate and get existing profile by ID or name
-	existing, err := validateProfile(ctx, apiClient, profileInput)
+	existing, err := validateProfile(ctx, apiClient, keyConfig, profileInput)
 	if err != nil {
 		return fmt.Errorf("profile validation failed: %w", err)
 	}
@@ -395,12 +418,14 @@ func runUpdateProfile(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to update profile: %w", err)
 	}
+	invalidateListing(keyConfig, profilesListing)
 
-	fmt.Printf("✓ Profile updated successfully\n")
-	fmt.Printf("  Name: %s\n", updated.Name)
-	fmt.Printf("  Instructions: %s\n", updated.Instructions)
-
-	return nil
+	return output.Object(printer, updated, *updated, profileColumns(printer), func() error {
+		fmt.Printf("%sProfile updated successfully\n", emoji("✓ ", ""))
+		fmt.Printf("  Name: %s\n", updated.Name)
+		fmt.Printf("  Instructions: %s\n", updated.Instructions)
+		return nil
+	})
 }
 
 func runDeleteProfile(cmd *cobra.Command, args []string) error {
@@ -419,16 +444,15 @@ func runDeleteProfile(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
 	// Validate and get profile by ID or name
-	profile, err := validateProfile(ctx, apiClient, profileInput)
+	profile, err := validateProfile(ctx, apiClient, keyConfig, profileInput)
 	if err != nil {
 		return fmt.Errorf("profile validation failed: %w", err)
 	}
@@ -449,12 +473,20 @@ func runDeleteProfile(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete profile: %w", err)
 	}
+	invalidateListing(keyConfig, profilesListing)
 
-	fmt.Printf("✓ Profile '%s' deleted successfully\n", profile.Name)
+	fmt.Printf("%sProfile '%s' deleted successfully\n", emoji("✓ ", ""), profile.Name)
 	return nil
 }
 
 func runAssociateProfile(cmd *cobra.Command, args []string) error {
+	if err := requireFlag(cmd, "persona", profilePersona); err != nil {
+		return err
+	}
+	if err := requireFlag(cmd, "profile", profileName); err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -468,22 +500,20 @@ func runAssociateProfile(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
-	// Validate persona
-	persona, err := validatePersona(ctx, apiClient, profilePersona)
+	// Validate persona and profile, or let the user choose them
+	persona, err := selectPersona(ctx, apiClient, keyConfig, profilePersona)
 	if err != nil {
 		return fmt.Errorf("persona validation failed: %w", err)
 	}
 
-	// Validate profile
-	profile, err := validateProfile(ctx, apiClient, profileName)
+	profile, err := selectProfile(ctx, apiClient, keyConfig, profileName)
 	if err != nil {
 		return fmt.Errorf("profile validation failed: %w", err)
 	}
@@ -494,11 +524,18 @@ func runAssociateProfile(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to associate profile: %w", err)
 	}
 
-	fmt.Printf("✓ Profile '%s' associated with persona '%s'\n", profileName, persona.Name)
+	fmt.Printf("%sProfile '%s' associated with persona '%s'\n", emoji("✓ ", ""), profileName, persona.Name)
 	return nil
 }
 
 func runDisassociateProfile(cmd *cobra.Command, args []string) error {
+	if err := requireFlag(cmd, "persona", profilePersona); err != nil {
+		return err
+	}
+	if err := requireFlag(cmd, "profile", profileName); err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -512,22 +549,20 @@ func runDisassociateProfile(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
-	// Validate persona
-	persona, err := validatePersona(ctx, apiClient, profilePersona)
+	// Validate persona and profile, or let the user choose them
+	persona, err := selectPersona(ctx, apiClient, keyConfig, profilePersona)
 	if err != nil {
 		return fmt.Errorf("persona validation failed: %w", err)
 	}
 
-	// Validate profile
-	profile, err := validateProfile(ctx, apiClient, profileName)
+	profile, err := selectProfile(ctx, apiClient, keyConfig, profileName)
 	if err != nil {
 		return fmt.Errorf("profile validation failed: %w", err)
 	}
@@ -538,11 +573,11 @@ func runDisassociateProfile(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to disassociate profile: %w", err)
 	}
 
-	fmt.Printf("✓ Profile '%s' disassociated from persona '%s'\n", profileName, persona.Name)
+	fmt.Printf("%sProfile '%s' disassociated from persona '%s'\n", emoji("✓ ", ""), profileName, persona.Name)
 	return nil
 }
 
-func runInteractiveProfileCreation() error {
+func runInteractiveProfileCreation(printer *output.Printer) error {
 	fmt.Println("Interactive Profile Creation")
 	fmt.Println("============================")
 
@@ -587,11 +622,10 @@ func runInteractiveProfileCreation() error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Create profile
 	profile := &client.Profile{
@@ -604,12 +638,14 @@ func runInteractiveProfileCreation() error {
 	if err != nil {
 		return fmt.Errorf("failed to create profile: %w", err)
 	}
+	invalidateListing(keyConfig, profilesListing)
 
-	fmt.Printf("\n✓ Profile '%s' created successfully\n", created.Name)
-	fmt.Printf("  ID: %s\n", created.ProfileID)
-	fmt.Printf("  Instructions: %s\n", created.Instructions)
-
-	return nil
+	return output.Object(printer, created, *created, profileColumns(printer), func() error {
+		fmt.Printf("\n%sProfile '%s' created successfully\n", emoji("✓ ", ""), created.Name)
+		fmt.Printf("  ID: %s\n", created.ProfileID)
+		fmt.Printf("  Instructions: %s\n", created.Instructions)
+		return nil
+	})
 }
 
 func filterProfiles(profiles []client.Profile, filter string) []client.Profile {
@@ -649,50 +685,33 @@ func sortProfiles(profiles []client.Profile, sortBy string) {
 	}
 }
 
-func outputProfilesTable(profiles []client.Profile) error {
-	if len(profiles) == 0 {
-		fmt.Println("No profiles found.")
-		return nil
+// profileColumns returns the columns of profile tables and CSV output.
+// Instructions are shortened in the default table only.
+func profileColumns(printer *output.Printer) []output.Column[client.Profile] {
+	return []output.Column[client.Profile]{
+		{Name: "name", Value: func(p client.Profile) string { return p.Name }},
+		{Name: "instructions", Value: func(p client.Profile) string {
+			if printer.Format == output.Table && len(p.Instructions) > 50 {
+				return p.Instructions[:47] + "..."
+			}
+			return p.Instructions
+		}},
+		{Name: "created", Value: func(p client.Profile) string { return formatTime(p.CreatedAt) }},
+		{Name: "updated", Value: func(p client.Profile) string { return formatTime(p.UpdatedAt) }},
+		{Name: "id", Value: func(p client.Profile) string { return p.ProfileID }},
 	}
+}
 
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
-
-	// Header
-	fmt.Fprintln(w, "NAME\tINSTRUCTIONS\tCREATED\tUPDATED\tID")
-	fmt.Fprintln(w, "----\t------------\t-------\t-------\t--")
-
-	// Rows
-	for _, profile := range profiles {
-		created := formatTime(profile.CreatedAt)
-		updated := formatTime(profile.UpdatedAt)
-
-		// Truncate instructions if too long
-		instructions := profile.Instructions
-		if len(instructions) > 50 {
-			instructions = instructions[:47] + "..."
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			profile.Name,
-			instructions,
-			created,
-			updated,
-			profile.ProfileID,
-		)
+func outputProfiles(printer *output.Printer, profiles []client.Profile) error {
+	if len(profiles) == 0 && printer.Tabular() {
+		fmt.Println("No profiles found.")
+		return nil
 	}
 
-	return nil
-}
-
-func outputProfilesJSON(profiles []client.Profile) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(map[string]interface{}{
+	return output.List(printer, map[string]interface{}{
 		"profiles": profiles,
 		"count":    len(profiles),
-	})
+	}, profiles, profileColumns(printer))
 }
 
 func outputProfileDetails(profile *client.Profile) error {
@@ -706,9 +725,3 @@ func outputProfileDetails(profile *client.Profile) error {
 
 	return nil
 }
-
-func outputProfileJSON(profile *client.Profile) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(profile)
-}
@@ -1,11 +1,12 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/toneclone/cli/internal/config"
 )
 
 var (
@@ -13,6 +14,14 @@ var (
 	verbose bool
 	debug   bool
 	profile string
+	account string
+	envName string
+
+	// Network flags, overriding the network settings in config
+	networkFlags config.NetworkConfig
+
+	// configFileRead is set when initConfig found a config file
+	configFileRead bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -35,12 +44,45 @@ Get started by configuring your API key:
 For more help on any command, use:
   toneclone [command] --help`,
 	Version: Version,
+	// Execute prints errors once, with a pointer to --help for usage errors
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		setupEmoji()
+		if configFileRead {
+			logger.Debug("Using config file", "path", viper.ConfigFileUsed())
+		}
+		warnDeprecatedAccountSelection()
+		if err := startTracing(); err != nil {
+			return err
+		}
+		return preflightScopes(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed to stderr; ExitCode maps them to the exit code.
 func Execute() error {
-	return rootCmd.Execute()
+	markUsageErrors(rootCmd)
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		err = outputUsageError(cmd, err)
+	}
+	if traceErr := finishTracing(); traceErr != nil {
+		if err == nil {
+			err = traceErr
+		} else {
+			logger.Warn(traceErr.Error())
+		}
+	}
+	if err != nil {
+		printError(err)
+	}
+	return err
 }
 
 func init() {
@@ -49,13 +91,37 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.toneclone.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "debug output (includes verbose)")
-	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "configuration profile to use")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "debug output (includes verbose), with a trace of API requests on stderr")
+	rootCmd.PersistentFlags().StringVar(&debugFile, "debug-file", "", "write the API request trace to a file instead of stderr")
+	rootCmd.PersistentFlags().StringVar(&harFile, "har", "", "record API requests to a HAR file, with secrets redacted")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "diagnostics to show on stderr: debug, info, warn or error (default: info, debug with --verbose)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "diagnostics format: text or json (default: text)")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "print plain text instead of emoji symbols (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&account, "account", "", "account (API key profile) to use")
+	rootCmd.PersistentFlags().StringVar(&envName, "env", "", "environment to use (see 'toneclone env list')")
+	rootCmd.PersistentFlags().BoolVar(&refreshMetadata, "refresh", false, "fetch persona and profile lists instead of using the cached ones")
+
+	// TLS and proxy settings for corporate networks
+	rootCmd.PersistentFlags().StringVar(&networkFlags.CACert, "ca-cert", "", "PEM CA bundle to trust in addition to the system roots")
+	rootCmd.PersistentFlags().StringVar(&networkFlags.ClientCert, "client-cert", "", "PEM client certificate for mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&networkFlags.ClientKey, "client-key", "", "PEM private key for --client-cert")
+	rootCmd.PersistentFlags().StringVar(&networkFlags.Proxy, "proxy", "", "HTTP, HTTPS or SOCKS5 proxy URL (default: HTTPS_PROXY)")
+	rootCmd.PersistentFlags().StringSliceVar(&networkFlags.NoProxy, "no-proxy", nil, "hosts, domains or CIDR ranges to reach without the proxy")
+	rootCmd.PersistentFlags().StringVar(&networkFlags.MinTLSVersion, "min-tls-version", "", "minimum TLS version: 1.2 or 1.3")
+
+	// --profile used to select the account, which clashed with the writing
+	// profile flags of write, compare and lint. It still works elsewhere.
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "account (API key profile) to use")
+	rootCmd.PersistentFlags().MarkDeprecated("profile", "use --account instead")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
-	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	viper.BindPFlag("debug_file", rootCmd.PersistentFlags().Lookup("debug-file"))
+	viper.BindPFlag("har", rootCmd.PersistentFlags().Lookup("har"))
+	viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
+	viper.BindPFlag("log_format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("no_emoji", rootCmd.PersistentFlags().Lookup("no-emoji"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -69,8 +135,9 @@ func initConfig() {
 		cobra.CheckErr(err)
 
 		// Search config in home directory with name ".toneclone" (without extension).
+		// Project files (.toneclone.yaml in the working directory or
+		// above) are layered on top by config.LoadConfig.
 		viper.AddConfigPath(home)
-		viper.AddConfigPath(".")
 		viper.SetConfigType("yaml")
 		viper.SetConfigName(".toneclone")
 	}
@@ -79,8 +146,38 @@ func initConfig() {
 	viper.SetEnvPrefix("TONECLONE")
 	viper.AutomaticEnv() // read in environment variables that match
 
+	// Account selection is resolved by the config package
+	config.AccountFlag = account
+	config.ProfileFlag = profile
+	config.EnvFlag = envName
+	config.NetworkFlags = networkFlags
+
+	// Flags take precedence over every config file
+	if rootCmd.PersistentFlags().Changed("verbose") {
+		config.FlagOverrides["verbose"] = verbose
+	}
+	if rootCmd.PersistentFlags().Changed("debug") {
+		config.FlagOverrides["debug"] = debug
+	}
+	if rootCmd.PersistentFlags().Changed("log-level") {
+		config.FlagOverrides["log_level"] = logLevel
+	}
+	if rootCmd.PersistentFlags().Changed("log-format") {
+		config.FlagOverrides["log_format"] = logFormat
+	}
+	if rootCmd.PersistentFlags().Changed("no-emoji") {
+		config.FlagOverrides["no_emoji"] = noEmoji
+	}
+
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil && (verbose || debug) {
-		fmt.Fprintf(os.Stderr, "Using config file: %s\n", viper.ConfigFileUsed())
+	configFileRead = viper.ReadInConfig() == nil
+}
+
+// warnDeprecatedAccountSelection points users of TONECLONE_PROFILE at
+// TONECLONE_ACCOUNT. Cobra already warns about the deprecated flag.
+func warnDeprecatedAccountSelection() {
+	selection := config.NewConfig().ResolveAccount()
+	if selection.Source == config.AccountSourceProfileEnv {
+		logger.Warn(selection.DeprecationWarning())
 	}
 }
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/internal/cache"
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// scopesAnnotation is the command annotation listing the API key scopes a
+// command needs, comma-separated
+const scopesAnnotation = "toneclone/scopes"
+
+// scopesCacheNamespace and scopesCacheTTL control how long introspected key
+// scopes are reused
+const (
+	scopesCacheNamespace = "scopes"
+	scopesCacheTTL       = time.Hour
+)
+
+// requireScopes builds the annotations declaring the scopes a command needs
+func requireScopes(scopes ...client.APIKeyScope) map[string]string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return map[string]string{scopesAnnotation: strings.Join(names, ",")}
+}
+
+// requireFlagScopes builds the annotations declaring the scopes a command
+// needs only when flag is given, for commands that otherwise work offline
+func requireFlagScopes(flag string, scopes ...client.APIKeyScope) map[string]string {
+	annotations := requireScopes(scopes...)
+	return map[string]string{scopesAnnotation + ":" + flag: annotations[scopesAnnotation]}
+}
+
+// withFlagScopes adds to annotations the scopes a command needs only when
+// flag is given
+func withFlagScopes(annotations map[string]string, flag string, scopes ...client.APIKeyScope) map[string]string {
+	for key, value := range requireFlagScopes(flag, scopes...) {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+// commandScopes returns the scopes declared by a command, including those
+// of the flags it was given
+func commandScopes(cmd *cobra.Command) []client.APIKeyScope {
+	names := splitList(cmd.Annotations[scopesAnnotation])
+	for key, value := range cmd.Annotations {
+		flag, ok := strings.CutPrefix(key, scopesAnnotation+":")
+		if ok && cmd.Flags().Changed(flag) {
+			names = append(names, splitList(value)...)
+		}
+	}
+
+	var scopes []client.APIKeyScope
+	for _, name := range names {
+		scopes = append(scopes, client.APIKeyScope(name))
+	}
+	return scopes
+}
+
+// preflightScopes fails fast when the current key lacks a scope the command
+// needs. If the key's scopes cannot be determined, the command runs and the
+// API has the final say. Set TONECLONE_SKIP_SCOPE_CHECK=1 to disable.
+func preflightScopes(cmd *cobra.Command) error {
+	return checkScopes(cmd, commandScopes(cmd))
+}
+
+// checkScopes fails when the current key lacks one of required, for scopes
+// only known once a command has resolved its options
+func checkScopes(cmd *cobra.Command, required []client.APIKeyScope) error {
+	if len(required) == 0 || os.Getenv("TONECLONE_SKIP_SCOPE_CHECK") != "" {
+		return nil
+	}
+
+	// Cassettes hold only the commands' own traffic, which a cached scope
+	// lookup would make differ from run to run
+	if usingCassette() {
+		return nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		// Let the command report configuration problems itself
+		return nil
+	}
+
+	// Device logins are granted their scopes when the user approves them
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil || keyConfig.IsOAuth() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
+
+	granted, err := keyScopes(ctx, keyConfig, false)
+	if err != nil {
+		logger.Debug("Skipping scope check", "error", err)
+		return nil
+	}
+
+	// Cached scopes may predate a change to the key, so confirm before failing
+	if len(client.MissingScopes(granted, required)) > 0 {
+		if fresh, err := keyScopes(ctx, keyConfig, true); err == nil {
+			granted = fresh
+		}
+	}
+
+	missing := client.MissingScopes(granted, required)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(missing))
+	for i, scope := range missing {
+		names[i] = string(scope)
+	}
+
+	return withExitCode(ExitAuth, fmt.Errorf("this key lacks %s (run 'toneclone auth status' to see its scopes)", strings.Join(names, ", ")))
+}
+
+// keyScopes returns the scopes granted to a key, from the cache unless
+// refresh is set
+func keyScopes(ctx context.Context, keyConfig config.APIKeyConfig, refresh bool) ([]client.APIKeyScope, error) {
+	scopeCache, cacheErr := cache.Default()
+	cacheKey := keyConfig.BaseURL + "\n" + keyConfig.Key
+
+	var scopes []client.APIKeyScope
+	if cacheErr == nil && !refresh {
+		if ok, _ := scopeCache.Get(scopesCacheNamespace, cacheKey, scopesCacheTTL, &scopes); ok {
+			return scopes, nil
+		}
+	}
+
+	apiClient, err := newAPIClient(keyConfig, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	key, err := apiClient.APIKeys.Current(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up key scopes: %w", err)
+	}
+
+	if cacheErr == nil {
+		// The cache only speeds up later runs
+		scopeCache.Set(scopesCacheNamespace, cacheKey, key.Scopes)
+	}
+
+	return key.Scopes, nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+func TestCommandScopes(t *testing.T) {
+	cmd := &cobra.Command{Use: "lint", Annotations: requireFlagScopes("profile", client.ScopeProfilesRead)}
+	cmd.Flags().String("profile", "", "")
+
+	if scopes := commandScopes(cmd); len(scopes) != 0 {
+		t.Errorf("Expected no scopes without --profile, got %v", scopes)
+	}
+	cmd.Flags().Set("profile", "brand")
+	if scopes := commandScopes(cmd); !reflect.DeepEqual(scopes, []client.APIKeyScope{client.ScopeProfilesRead}) {
+		t.Errorf("Expected profiles:read with --profile, got %v", scopes)
+	}
+
+	// Commands generating with optional profiles need profiles:read only
+	// with --profile
+	profilesRead := []client.APIKeyScope{client.ScopeProfilesRead}
+	for _, c := range []*cobra.Command{writeCmd, compareCmd} {
+		resetFlags(t, c.Flags())
+		scopes := commandScopes(c)
+		if len(client.MissingScopes(scopes, profilesRead)) == 0 {
+			t.Errorf("Expected %s to need no profiles:read without --profile, got %v", c.Name(), scopes)
+		}
+		if len(client.MissingScopes(scopes, []client.APIKeyScope{client.ScopeTextGenerate, client.ScopePersonasRead})) > 0 {
+			t.Errorf("Expected %s to require text:generate and personas:read, got %v", c.Name(), scopes)
+		}
+		c.Flags().Set("profile", "Email")
+		if scopes := commandScopes(c); len(client.MissingScopes(scopes, profilesRead)) > 0 {
+			t.Errorf("Expected %s to require profiles:read with --profile, got %v", c.Name(), scopes)
+		}
+	}
+	if scopes := commandScopes(docsRegenerateCmd); len(client.MissingScopes(scopes, profilesRead)) > 0 {
+		t.Errorf("Expected docs regenerate to require profiles:read, got %v", scopes)
+	}
+}
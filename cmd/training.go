@@ -14,12 +14,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
 	"github.com/toneclone/cli/pkg/client"
 )
 
 var (
 	// Training command flags
-	trainingFormat    string
 	trainingPersona   string
 	trainingFile      string
 	trainingText      string
@@ -59,8 +59,11 @@ Files can be filtered by persona association and sorted by various criteria.
 Examples:
   toneclone training list
   toneclone training list --persona=professional
-  toneclone training list --format=json`,
-	RunE: runListTraining,
+  toneclone training list -o json
+  toneclone training list -o wide
+  toneclone training list -o csv --columns=filename,size,id`,
+	Annotations: requireScopes(client.ScopeFilesRead),
+	RunE:        runListTraining,
 }
 
 // addTrainingCmd represents the add subcommand
@@ -76,7 +79,8 @@ Examples:
   toneclone training add --file=document.txt --persona=professional
   toneclone training add --text="Sample content" --persona=casual --filename=sample.txt
   toneclone training add --directory=./docs --persona=writer --recursive`,
-	RunE: runAddTraining,
+	Annotations: requireScopes(client.ScopeFilesWrite, client.ScopeTrainingWrite),
+	RunE:        runAddTraining,
 }
 
 // removeTrainingCmd represents the remove subcommand
@@ -90,7 +94,8 @@ Files can be completely deleted or just disassociated from specific personas.
 Examples:
   toneclone training remove --file-id=file-123 --persona=professional
   toneclone training remove --file-id=file-123 --confirm`,
-	RunE: runRemoveTraining,
+	Annotations: requireScopes(client.ScopeFilesWrite),
+	RunE:        runRemoveTraining,
 }
 
 // associateTrainingCmd represents the associate subcommand
@@ -104,7 +109,8 @@ Files must be uploaded before they can be associated with personas.
 Examples:
   toneclone training associate --file-id=file-123 --persona=professional
   toneclone training associate --file-id=file-123,file-456 --persona=writer`,
-	RunE: runAssociateTraining,
+	Annotations: requireScopes(client.ScopeTrainingWrite, client.ScopePersonasRead),
+	RunE:        runAssociateTraining,
 }
 
 // disassociateTrainingCmd represents the disassociate subcommand
@@ -118,7 +124,8 @@ Files remain available for association with other personas.
 Examples:
   toneclone training disassociate --file-id=file-123 --persona=professional
   toneclone training disassociate --file-id=file-123,file-456 --persona=writer`,
-	RunE: runDisassociateTraining,
+	Annotations: requireScopes(client.ScopeTrainingWrite, client.ScopePersonasRead),
+	RunE:        runDisassociateTraining,
 }
 
 func init() {
@@ -132,8 +139,10 @@ func init() {
 	trainingCmd.AddCommand(disassociateTrainingCmd)
 
 	// List command flags
-	listTrainingCmd.Flags().StringVar(&trainingFormat, "format", "table", "output format: table, json")
+	addOutputFlags(listTrainingCmd)
+	addQuietFlag(listTrainingCmd)
 	listTrainingCmd.Flags().StringVar(&trainingPersona, "persona", "", "filter by persona name or ID")
+	listTrainingCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
 
 	// Add command flags
 	addTrainingCmd.Flags().StringVar(&trainingFile, "file", "", "file to upload")
@@ -143,26 +152,35 @@ func init() {
 	addTrainingCmd.Flags().StringVar(&trainingDirectory, "directory", "", "directory to upload files from")
 	addTrainingCmd.Flags().BoolVar(&trainingRecursive, "recursive", false, "recursively upload files from directory")
 	addTrainingCmd.Flags().BoolVar(&trainingVerbose, "verbose", false, "verbose output")
+	addQuietFlag(addTrainingCmd)
+	addTrainingCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
 
 	// Remove command flags
 	removeTrainingCmd.Flags().StringVar(&trainingFileID, "file-id", "", "file ID to remove")
 	removeTrainingCmd.Flags().StringVar(&trainingPersona, "persona", "", "persona to disassociate from")
 	removeTrainingCmd.Flags().BoolVar(&trainingConfirm, "confirm", false, "skip confirmation prompt")
+	removeTrainingCmd.RegisterFlagCompletionFunc("file-id", completeIDs(completeFiles, false))
+	removeTrainingCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
 
 	// Associate command flags
 	associateTrainingCmd.Flags().StringVar(&trainingFileID, "file-id", "", "file ID(s) to associate (comma-separated)")
 	associateTrainingCmd.Flags().StringVar(&trainingPersona, "persona", "", "persona to associate with")
-	associateTrainingCmd.MarkFlagRequired("file-id")
-	associateTrainingCmd.MarkFlagRequired("persona")
+	associateTrainingCmd.RegisterFlagCompletionFunc("file-id", completeIDs(completeFiles, true))
+	associateTrainingCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
 
 	// Disassociate command flags
 	disassociateTrainingCmd.Flags().StringVar(&trainingFileID, "file-id", "", "file ID(s) to disassociate (comma-separated)")
 	disassociateTrainingCmd.Flags().StringVar(&trainingPersona, "persona", "", "persona to disassociate from")
-	disassociateTrainingCmd.MarkFlagRequired("file-id")
-	disassociateTrainingCmd.MarkFlagRequired("persona")
+	disassociateTrainingCmd.RegisterFlagCompletionFunc("file-id", completeIDs(completeFiles, true))
+	disassociateTrainingCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
 }
 
 func runListTraining(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -176,11 +194,10 @@ func runListTraining(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
@@ -192,7 +209,7 @@ func runListTraining(cmd *cobra.Command, args []string) error {
 
 	// Filter by persona if specified
 	if trainingPersona != "" {
-		persona, err := validatePersona(ctx, apiClient, trainingPersona)
+		persona, err := validatePersona(ctx, apiClient, keyConfig, trainingPersona)
 		if err != nil {
 			return fmt.Errorf("persona validation failed: %w", err)
 		}
@@ -207,11 +224,7 @@ func runListTraining(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output files
-	if trainingFormat == "json" {
-		return outputTrainingFilesJSON(files)
-	}
-
-	return outputTrainingFilesTable(files)
+	return outputTrainingFiles(printer, files)
 }
 
 func runAddTraining(cmd *cobra.Command, args []string) error {
@@ -233,18 +246,17 @@ func runAddTraining(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
 	// Validate persona if specified
 	var persona *client.Persona
 	if trainingPersona != "" {
-		persona, err = validatePersona(ctx, apiClient, trainingPersona)
+		persona, err = validatePersona(ctx, apiClient, keyConfig, trainingPersona)
 		if err != nil {
 			return fmt.Errorf("persona validation failed: %w", err)
 		}
@@ -267,8 +279,8 @@ func runAddTraining(cmd *cobra.Command, args []string) error {
 }
 
 func runRemoveTraining(cmd *cobra.Command, args []string) error {
-	if trainingFileID == "" {
-		return fmt.Errorf("--file-id is required")
+	if err := requireFlag(cmd, "file-id", trainingFileID); err != nil {
+		return err
 	}
 
 	// Load configuration
@@ -284,17 +296,24 @@ func runRemoveTraining(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
+	if trainingFileID == "" {
+		file, err := pickTrainingFile(ctx, apiClient)
+		if err != nil {
+			return err
+		}
+		trainingFileID = file.FileID
+	}
+
 	// If persona is specified, disassociate instead of delete
 	if trainingPersona != "" {
-		persona, err := validatePersona(ctx, apiClient, trainingPersona)
+		persona, err := validatePersona(ctx, apiClient, keyConfig, trainingPersona)
 		if err != nil {
 			return fmt.Errorf("persona validation failed: %w", err)
 		}
@@ -309,7 +328,7 @@ func runRemoveTraining(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to disassociate files: %w", err)
 		}
 
-		fmt.Printf("✓ Files disassociated from persona '%s'\n", persona.Name)
+		fmt.Printf("%sFiles disassociated from persona '%s'\n", emoji("✓ ", ""), persona.Name)
 		return nil
 	}
 
@@ -336,11 +355,18 @@ func runRemoveTraining(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
 
-	fmt.Printf("✓ File '%s' deleted successfully\n", file.FileName)
+	fmt.Printf("%sFile '%s' deleted successfully\n", emoji("✓ ", ""), file.FileName)
 	return nil
 }
 
 func runAssociateTraining(cmd *cobra.Command, args []string) error {
+	if err := requireFlag(cmd, "file-id", trainingFileID); err != nil {
+		return err
+	}
+	if err := requireFlag(cmd, "persona", trainingPersona); err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -354,20 +380,27 @@ func runAssociateTraining(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
-	// Validate persona
-	persona, err := validatePersona(ctx, apiClient, trainingPersona)
+	// Validate persona, or let the user choose one
+	persona, err := selectPersona(ctx, apiClient, keyConfig, trainingPersona)
 	if err != nil {
 		return fmt.Errorf("persona validation failed: %w", err)
 	}
 
+	if trainingFileID == "" {
+		file, err := pickTrainingFile(ctx, apiClient)
+		if err != nil {
+			return err
+		}
+		trainingFileID = file.FileID
+	}
+
 	// Parse file IDs
 	fileIDs := strings.Split(trainingFileID, ",")
 	for i, id := range fileIDs {
@@ -380,11 +413,18 @@ func runAssociateTraining(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to associate files: %w", err)
 	}
 
-	fmt.Printf("✓ %d file(s) associated with persona '%s'\n", len(fileIDs), persona.Name)
+	fmt.Printf("%s%d file(s) associated with persona '%s'\n", emoji("✓ ", ""), len(fileIDs), persona.Name)
 	return nil
 }
 
 func runDisassociateTraining(cmd *cobra.Command, args []string) error {
+	if err := requireFlag(cmd, "file-id", trainingFileID); err != nil {
+		return err
+	}
+	if err := requireFlag(cmd, "persona", trainingPersona); err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -398,20 +438,27 @@ func runDisassociateTraining(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	ctx := context.Background()
 
-	// Validate persona
-	persona, err := validatePersona(ctx, apiClient, trainingPersona)
+	// Validate persona, or let the user choose one
+	persona, err := selectPersona(ctx, apiClient, keyConfig, trainingPersona)
 	if err != nil {
 		return fmt.Errorf("persona validation failed: %w", err)
 	}
 
+	if trainingFileID == "" {
+		file, err := pickTrainingFile(ctx, apiClient)
+		if err != nil {
+			return err
+		}
+		trainingFileID = file.FileID
+	}
+
 	// Parse file IDs
 	fileIDs := strings.Split(trainingFileID, ",")
 	for i, id := range fileIDs {
@@ -424,7 +471,7 @@ func runDisassociateTraining(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to disassociate files: %w", err)
 	}
 
-	fmt.Printf("✓ %d file(s) disassociated from persona '%s'\n", len(fileIDs), persona.Name)
+	fmt.Printf("%s%d file(s) disassociated from persona '%s'\n", emoji("✓ ", ""), len(fileIDs), persona.Name)
 	return nil
 }
 
@@ -448,10 +495,14 @@ func addTextTraining(ctx context.Context, apiClient *client.ToneCloneClient, per
 		return fmt.Errorf("failed to upload text: %w", err)
 	}
 
-	fmt.Printf("✓ Text uploaded successfully\n")
-	fmt.Printf("  File ID: %s\n", file.FileID)
-	fmt.Printf("  Filename: %s\n", file.FileName)
-	fmt.Printf("  Size: %d bytes\n", file.FileSize)
+	if outputQuiet {
+		fmt.Println(file.FileID)
+	} else {
+		fmt.Printf("%sText uploaded successfully\n", emoji("✓ ", ""))
+		fmt.Printf("  File ID: %s\n", file.FileID)
+		fmt.Printf("  Filename: %s\n", file.FileName)
+		fmt.Printf("  Size: %d bytes\n", file.FileSize)
+	}
 
 	// Associate with persona if specified
 	if persona != nil {
@@ -459,7 +510,9 @@ func addTextTraining(ctx context.Context, apiClient *client.ToneCloneClient, per
 		if err != nil {
 			return fmt.Errorf("failed to associate with persona: %w", err)
 		}
-		fmt.Printf("  Associated with persona: %s\n", persona.Name)
+		if !outputQuiet {
+			fmt.Printf("  Associated with persona: %s\n", persona.Name)
+		}
 	}
 
 	return nil
@@ -487,7 +540,7 @@ func addFileTraining(ctx context.Context, apiClient *client.ToneCloneClient, per
 	filename := filepath.Base(trainingFile)
 
 	if trainingVerbose {
-		fmt.Printf("Uploading file: %s (%d bytes)\n", filename, fileInfo.Size())
+		logger.Info("Uploading file", "file", filename, "bytes", fileInfo.Size())
 	}
 
 	// Upload file
@@ -496,10 +549,14 @@ func addFileTraining(ctx context.Context, apiClient *client.ToneCloneClient, per
 		return fmt.Errorf("failed to upload file: %w", err)
 	}
 
-	fmt.Printf("✓ File uploaded successfully\n")
-	fmt.Printf("  File ID: %s\n", uploadedFile.FileID)
-	fmt.Printf("  Filename: %s\n", uploadedFile.FileName)
-	fmt.Printf("  Size: %d bytes\n", uploadedFile.FileSize)
+	if outputQuiet {
+		fmt.Println(uploadedFile.FileID)
+	} else {
+		fmt.Printf("%sFile uploaded successfully\n", emoji("✓ ", ""))
+		fmt.Printf("  File ID: %s\n", uploadedFile.FileID)
+		fmt.Printf("  Filename: %s\n", uploadedFile.FileName)
+		fmt.Printf("  Size: %d bytes\n", uploadedFile.FileSize)
+	}
 
 	// Associate with persona if specified
 	if persona != nil {
@@ -507,7 +564,9 @@ func addFileTraining(ctx context.Context, apiClient *client.ToneCloneClient, per
 		if err != nil {
 			return fmt.Errorf("failed to associate with persona: %w", err)
 		}
-		fmt.Printf("  Associated with persona: %s\n", persona.Name)
+		if !outputQuiet {
+			fmt.Printf("  Associated with persona: %s\n", persona.Name)
+		}
 	}
 
 	return nil
@@ -557,12 +616,13 @@ func addDirectoryTraining(ctx context.Context, apiClient *client.ToneCloneClient
 		return fmt.Errorf("no supported files found in directory")
 	}
 
-	fmt.Printf("Found %d files to upload\n", len(files))
+	logger.Info("Found files to upload", "count", len(files))
 
 	// Process files in batches for better efficiency
 	const batchSize = 10
 	var totalUploaded int
 	var totalAssociated int
+	var totalFailed int
 
 	for i := 0; i < len(files); i += batchSize {
 		end := i + batchSize
@@ -575,7 +635,7 @@ func addDirectoryTraining(ctx context.Context, apiClient *client.ToneCloneClient
 		totalBatches := (len(files) + batchSize - 1) / batchSize
 
 		if trainingVerbose {
-			fmt.Printf("Processing batch %d/%d (%d files)\n", batchNum, totalBatches, len(batch))
+			logger.Info("Processing batch", "batch", fmt.Sprintf("%d/%d", batchNum, totalBatches), "files", len(batch))
 		}
 
 		// Prepare file uploads for this batch
@@ -587,7 +647,8 @@ func addDirectoryTraining(ctx context.Context, apiClient *client.ToneCloneClient
 			// Open file
 			file, err := os.Open(filePath)
 			if err != nil {
-				fmt.Printf("  ✗ Failed to open %s: %v\n", filename, err)
+				logger.Warn("Failed to open file", "file", filename, "error", err)
+				totalFailed++
 				continue
 			}
 
@@ -619,14 +680,28 @@ func addDirectoryTraining(ctx context.Context, apiClient *client.ToneCloneClient
 		}
 
 		if err != nil {
-			fmt.Printf("  ✗ Batch upload failed: %v\n", err)
+			logger.Error("Batch upload failed", "batch", batchNum, "error", err)
+			totalFailed += len(fileUploads)
 			continue
 		}
 
 		// Report results for this batch
 		for _, result := range response.Files {
+			if result.Status != "success" {
+				totalFailed++
+			}
+
+			if outputQuiet {
+				if result.Status == "success" {
+					fmt.Println(result.FileID)
+				} else {
+					logger.Warn("Upload failed", "file", result.Filename, "error", result.Error)
+				}
+				continue
+			}
+
 			if result.Status == "success" {
-				fmt.Printf("  ✓ %s uploaded", result.Filename)
+				fmt.Printf("  %s%s uploaded", emoji("✓ ", ""), result.Filename)
 				if result.FileID != "" {
 					fmt.Printf(" (ID: %s)", result.FileID)
 				}
@@ -635,7 +710,7 @@ func addDirectoryTraining(ctx context.Context, apiClient *client.ToneCloneClient
 				}
 				fmt.Printf("\n")
 			} else {
-				fmt.Printf("  ✗ %s failed: %s\n", result.Filename, result.Error)
+				fmt.Printf("  %s%s failed: %s\n", emoji("✗ ", ""), result.Filename, result.Error)
 			}
 		}
 
@@ -643,12 +718,20 @@ func addDirectoryTraining(ctx context.Context, apiClient *client.ToneCloneClient
 		totalAssociated += response.Summary.Associated
 	}
 
-	fmt.Printf("✓ %d files uploaded successfully", totalUploaded)
-	if persona != nil && totalAssociated > 0 {
-		fmt.Printf(", %d associated with persona '%s'", totalAssociated, persona.Name)
+	if !outputQuiet {
+		fmt.Printf("%s%d files uploaded successfully", emoji("✓ ", ""), totalUploaded)
+		if persona != nil && totalAssociated > 0 {
+			fmt.Printf(", %d associated with persona '%s'", totalAssociated, persona.Name)
+		}
+		fmt.Printf("\n")
 	}
-	fmt.Printf("\n")
 
+	switch {
+	case totalFailed == len(files):
+		return fmt.Errorf("all %d files failed to upload", len(files))
+	case totalFailed > 0:
+		return withExitCode(ExitPartial, fmt.Errorf("%d of %d files failed to upload", totalFailed, len(files)))
+	}
 	return nil
 }
 
@@ -703,7 +786,7 @@ func watchJobStatus(ctx context.Context, apiClient *client.ToneCloneClient, jobI
 		case <-ticker.C:
 			job, err := apiClient.Training.GetJob(ctx, jobID)
 			if err != nil {
-				fmt.Printf("Error getting job status: %v\n", err)
+				logger.Warn("Failed to get job status", "job", jobID, "error", err)
 				continue
 			}
 
@@ -725,48 +808,33 @@ func watchJobStatus(ctx context.Context, apiClient *client.ToneCloneClient, jobI
 	}
 }
 
-func outputTrainingFilesTable(files []client.TrainingFile) error {
-	if len(files) == 0 {
-		fmt.Println("No training files found.")
-		return nil
-	}
-
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
-
-	// Header
-	fmt.Fprintln(w, "FILENAME\tSIZE\tCONTENT TYPE\tUPLOADED\tUSED FOR TRAINING\tID")
-	fmt.Fprintln(w, "--------\t----\t------------\t--------\t-----------------\t--")
-
-	// Rows
-	for _, file := range files {
-		uploaded := formatTime(file.CreatedAt)
-		used := "No"
-		if file.UsedForTraining {
-			used = "Yes"
+// trainingFileColumns are the columns of training file tables and CSV output
+var trainingFileColumns = []output.Column[client.TrainingFile]{
+	{Name: "filename", Value: func(f client.TrainingFile) string { return f.FileName }},
+	{Name: "size", Value: func(f client.TrainingFile) string { return formatFileSize(f.FileSize) }},
+	{Name: "content_type", Value: func(f client.TrainingFile) string { return f.ContentType }},
+	{Name: "uploaded", Value: func(f client.TrainingFile) string { return formatTime(f.CreatedAt) }},
+	{Name: "used_for_training", Value: func(f client.TrainingFile) string {
+		if f.UsedForTraining {
+			return "Yes"
 		}
+		return "No"
+	}},
+	{Name: "id", Value: func(f client.TrainingFile) string { return f.FileID }},
+	{Name: "source", Wide: true, Value: func(f client.TrainingFile) string { return f.Source }},
+	{Name: "modified", Wide: true, Value: func(f client.TrainingFile) string { return formatTime(f.ModifiedAt) }},
+}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			file.FileName,
-			formatFileSize(file.FileSize),
-			file.ContentType,
-			uploaded,
-			used,
-			file.FileID,
-		)
+func outputTrainingFiles(printer *output.Printer, files []client.TrainingFile) error {
+	if len(files) == 0 && printer.Tabular() {
+		fmt.Println("No training files found.")
+		return nil
 	}
 
-	return nil
-}
-
-func outputTrainingFilesJSON(files []client.TrainingFile) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(map[string]interface{}{
+	return output.List(printer, map[string]interface{}{
 		"files": files,
 		"count": len(files),
-	})
+	}, files, trainingFileColumns)
 }
 
 func outputTrainingJobsTable(jobs []client.TrainingJob) error {
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/tui"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse personas, profiles and training jobs in a terminal UI",
+	Long: `Open a full-screen dashboard for your ToneClone account.
+
+The dashboard has three tabs, switched with Tab or the keys 1 to 3:
+
+  Personas  your personas and the built-in ones, with the profiles and
+            training files of the selected persona. Press g to open a side
+            pane and generate text with it.
+  Profiles  your profiles and their instructions. Press e to edit the
+            instructions, Ctrl-S to save them and Esc to discard changes.
+  Jobs      training jobs, refreshed every few seconds while shown.
+
+Move with the arrow keys or j and k, press r to refresh and q to quit.
+
+Browsing needs an API key with read access to personas, profiles and
+training; editing and generating need the matching write scopes.
+
+Examples:
+  toneclone tui
+  toneclone tui --account=work
+  NO_COLOR=1 toneclone tui`,
+	Annotations: requireScopes(client.ScopePersonasRead, client.ScopeProfilesRead, client.ScopeTrainingRead),
+	Args:        cobra.NoArgs,
+	RunE:        runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) {
+		return errors.New("the terminal UI needs an interactive terminal")
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Get current API key
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		return fmt.Errorf("authentication required: %w", err)
+	}
+
+	// Create API client
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(stdin)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(stdin, state)
+
+	app := tui.New(context.Background(), apiClient)
+	app.Plain = noEmoji
+	return tui.Run(context.Background(), app, os.Stdin, os.Stdout, func() (int, int, error) {
+		return term.GetSize(stdout)
+	})
+}
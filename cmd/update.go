@@ -55,24 +55,23 @@ func init() {
 
 // checkForUpdates checks if a newer version is available
 func checkForUpdates() {
-	fmt.Println("Checking for updates...")
+	logger.Info("Checking for updates")
 	
 	latest, found, err := selfupdate.DetectLatest("toneclone/cli")
 	if err != nil {
-		fmt.Printf("Error checking for updates: %v\n", err)
+		logger.Error("Failed to check for updates", "error", err)
 		os.Exit(1)
 	}
 
 	if !found {
 		fmt.Println("No release information found")
-		fmt.Printf("Debug: looking for repository: toneclone/cli\n")
-		fmt.Printf("Debug: current platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+		logger.Debug("Looked for releases", "repository", "toneclone/cli", "platform", runtime.GOOS+"/"+runtime.GOARCH)
 		return
 	}
 
 	currentVersion, err := semver.Parse(strings.TrimPrefix(Version, "v"))
 	if err != nil {
-		fmt.Printf("Error parsing current version: %v\n", err)
+		logger.Error("Failed to parse current version", "error", err)
 		return
 	}
 
@@ -80,11 +79,11 @@ func checkForUpdates() {
 	fmt.Printf("Latest version:  %s\n", latest.Version)
 
 	if latest.Version.LTE(currentVersion) {
-		fmt.Println("✅ You are running the latest version!")
+		fmt.Println(emoji("✅ ", "") + "You are running the latest version!")
 		return
 	}
 
-	fmt.Printf("🆙 A newer version is available: %s → %s\n", currentVersion, latest.Version)
+	fmt.Printf("%sA newer version is available: %s %s %s\n", emoji("🆙 ", ""), currentVersion, emoji("→", "->"), latest.Version)
 	fmt.Printf("Release URL: %s\n", latest.URL)
 	fmt.Println("\nRun 'toneclone update' to upgrade.")
 }
@@ -95,25 +94,25 @@ func performUpdate() {
 	installMethod := detectInstallationMethod()
 	
 	if installMethod == "homebrew" {
-		fmt.Println("🍺 Homebrew installation detected.")
+		fmt.Println(emoji("🍺 ", "") + "Homebrew installation detected.")
 		fmt.Println("Please update using: brew upgrade toneclone")
 		fmt.Println("Or: brew update && brew upgrade toneclone")
 		return
 	}
 
-	fmt.Println("Checking for updates...")
+	logger.Info("Checking for updates")
 	
 	// Parse current version
 	currentVersion, err := semver.Parse(strings.TrimPrefix(Version, "v"))
 	if err != nil {
-		fmt.Printf("Error parsing current version: %v\n", err)
+		logger.Error("Failed to parse current version", "error", err)
 		os.Exit(1)
 	}
 
 	// Check for latest version
 	latest, found, err := selfupdate.DetectLatest("toneclone/cli")
 	if err != nil {
-		fmt.Printf("Error checking for updates: %v\n", err)
+		logger.Error("Failed to check for updates", "error", err)
 		os.Exit(1)
 	}
 
@@ -127,32 +126,32 @@ func performUpdate() {
 
 	// Check if update is needed
 	if latest.Version.LTE(currentVersion) && !forceUpdate {
-		fmt.Println("✅ You are already running the latest version!")
+		fmt.Println(emoji("✅ ", "") + "You are already running the latest version!")
 		if !forceUpdate {
 			return
 		}
-		fmt.Println("Forcing update due to --force flag...")
+		logger.Info("Forcing update due to --force flag")
 	}
 
 	// Perform the update
-	fmt.Printf("🔄 Updating from %s to %s...\n", currentVersion, latest.Version)
+	fmt.Printf("%sUpdating from %s to %s...\n", emoji("🔄 ", ""), currentVersion, latest.Version)
 	
 	// Show download progress
-	fmt.Println("📥 Downloading update...")
+	logger.Info("Downloading update")
 	
 	// Perform the update using the simpler API
 	release, err := selfupdate.UpdateSelf(currentVersion, "toneclone/cli")
 	if err != nil {
-		fmt.Printf("❌ Update failed: %v\n", err)
+		logger.Error("Update failed", "error", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Successfully updated to %s!\n", release.Version)
-	fmt.Println("🎉 ToneClone CLI has been updated. Restart any running instances to use the new version.")
+	fmt.Printf("%sSuccessfully updated to %s!\n", emoji("✅ ", ""), release.Version)
+	fmt.Println(emoji("🎉 ", "") + "ToneClone CLI has been updated. Restart any running instances to use the new version.")
 	
 	// Show release notes if available
 	if release.ReleaseNotes != "" {
-		fmt.Println("\n📋 Release Notes:")
+		fmt.Println("\n" + emoji("📋 ", "") + "Release Notes:")
 		fmt.Println(release.ReleaseNotes)
 	}
 }
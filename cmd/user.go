@@ -2,24 +2,16 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
-	"text/tabwriter"
-	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/output"
 	"github.com/toneclone/cli/pkg/client"
 )
 
-var (
-	// User command flags
-	userFormat string
-)
-
 // userCmd represents the user command
 var userCmd = &cobra.Command{
 	Use:   "user",
@@ -44,8 +36,10 @@ Shows user ID, email, plan, and account creation date.
 
 Examples:
   toneclone user whoami
-  toneclone user whoami --format=json`,
-	RunE: runWhoami,
+  toneclone user whoami -o json
+  toneclone user whoami -o jsonpath='{.email}'`,
+	Annotations: requireScopes(client.ScopeUserRead),
+	RunE:        runWhoami,
 }
 
 // infoCmd represents the info subcommand (alias for whoami)
@@ -58,8 +52,9 @@ Shows user ID, email, plan, account creation date, and usage statistics.
 
 Examples:
   toneclone user info
-  toneclone user info --format=json`,
-	RunE: runUserInfo,
+  toneclone user info -o yaml`,
+	Annotations: requireScopes(client.ScopeUserRead),
+	RunE:        runUserInfo,
 }
 
 // settingsCmd represents the settings subcommand
@@ -72,8 +67,9 @@ View and update account preferences, default settings, and configurations.
 
 Examples:
   toneclone user settings
-  toneclone user settings --format=json`,
-	RunE: runUserSettings,
+  toneclone user settings -o json`,
+	Annotations: requireScopes(client.ScopeUserRead),
+	RunE:        runUserSettings,
 }
 
 func init() {
@@ -84,17 +80,18 @@ func init() {
 	userCmd.AddCommand(infoCmd)
 	userCmd.AddCommand(settingsCmd)
 
-	// Whoami command flags
-	whoamiCmd.Flags().StringVar(&userFormat, "format", "table", "output format: table, json")
-
-	// Info command flags
-	infoCmd.Flags().StringVar(&userFormat, "format", "table", "output format: table, json")
-
-	// Settings command flags
-	settingsCmd.Flags().StringVar(&userFormat, "format", "table", "output format: table, json")
+	// Output flags
+	addOutputFlags(whoamiCmd)
+	addOutputFlags(infoCmd)
+	addOutputFlags(settingsCmd)
 }
 
 func runWhoami(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -108,11 +105,10 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Get user info
 	ctx := context.Background()
@@ -122,14 +118,15 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output user info
-	if userFormat == "json" {
-		return outputUserJSON(user)
-	}
-
-	return outputUserTable(user)
+	return output.List(printer, user, []client.User{*user}, userColumns)
 }
 
 func runUserInfo(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -143,11 +140,10 @@ func runUserInfo(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Get user info
 	ctx := context.Background()
@@ -157,14 +153,17 @@ func runUserInfo(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output detailed user info
-	if userFormat == "json" {
-		return outputUserJSON(user)
-	}
-
-	return outputUserDetails(user)
+	return output.Object(printer, user, *user, userColumns, func() error {
+		return outputUserDetails(user)
+	})
 }
 
 func runUserSettings(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -178,11 +177,10 @@ func runUserSettings(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		30*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, 0)
+	if err != nil {
+		return err
+	}
 
 	// Get user info
 	ctx := context.Background()
@@ -191,47 +189,68 @@ func runUserSettings(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get user info: %w", err)
 	}
 
-	// Display current settings
-	fmt.Printf("User Settings\n")
-	fmt.Printf("=============\n")
-	fmt.Printf("User ID:      %s\n", user.UserID)
-	fmt.Printf("Email:        %s\n", user.Email)
-	fmt.Printf("Plan:         %s\n", user.Plan)
-	fmt.Printf("API Key:      %s\n", cfg.GetCurrentKeyName())
-	fmt.Printf("Base URL:     %s\n", keyConfig.BaseURL)
 	configPath := viper.ConfigFileUsed()
 	if configPath == "" {
 		configPath, _ = config.GetConfigPath()
 	}
-	fmt.Printf("Config File:  %s\n", configPath)
+	settings := userSettings{
+		UserID:     user.UserID,
+		Email:      user.Email,
+		Plan:       user.Plan,
+		APIKey:     cfg.GetCurrentKeyName(),
+		BaseURL:    keyConfig.BaseURL,
+		ConfigFile: configPath,
+	}
 
-	return nil
+	// Display current settings
+	return output.Object(printer, settings, settings, userSettingsColumns, func() error {
+		fmt.Printf("User Settings\n")
+		fmt.Printf("=============\n")
+		fmt.Printf("User ID:      %s\n", settings.UserID)
+		fmt.Printf("Email:        %s\n", settings.Email)
+		fmt.Printf("Plan:         %s\n", settings.Plan)
+		fmt.Printf("API Key:      %s\n", settings.APIKey)
+		fmt.Printf("Base URL:     %s\n", settings.BaseURL)
+		fmt.Printf("Config File:  %s\n", settings.ConfigFile)
+		return nil
+	})
 }
 
-func outputUserTable(user *client.User) error {
-	// Create table writer
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
-
-	// Header
-	fmt.Fprintln(w, "USER ID\tEMAIL\tPLAN\tCREATED")
-	fmt.Fprintln(w, "-------\t-----\t----\t-------")
+// userSettings is the result of 'user settings'
+type userSettings struct {
+	UserID     string `json:"userId"`
+	Email      string `json:"email"`
+	Plan       string `json:"plan"`
+	APIKey     string `json:"apiKey"`
+	BaseURL    string `json:"baseUrl"`
+	ConfigFile string `json:"configFile"`
+}
 
-	// Data
-	created := formatTime(user.CreatedAt)
-	plan := user.Plan
-	if plan == "" {
-		plan = "Free"
-	}
+// userSettingsColumns are the columns of 'user settings' with --columns or CSV
+var userSettingsColumns = []output.Column[userSettings]{
+	{Name: "user_id", Value: func(s userSettings) string { return s.UserID }},
+	{Name: "email", Value: func(s userSettings) string { return s.Email }},
+	{Name: "plan", Value: func(s userSettings) string { return s.Plan }},
+	{Name: "api_key", Value: func(s userSettings) string { return s.APIKey }},
+	{Name: "base_url", Value: func(s userSettings) string { return s.BaseURL }},
+	{Name: "config_file", Value: func(s userSettings) string { return s.ConfigFile }},
+}
 
-	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-		user.UserID,
-		user.Email,
-		plan,
-		created,
-	)
+// userColumns are the columns of user tables and CSV output
+var userColumns = []output.Column[client.User]{
+	{Name: "user_id", Value: func(u client.User) string { return u.UserID }},
+	{Name: "email", Value: func(u client.User) string { return u.Email }},
+	{Name: "plan", Value: userPlan},
+	{Name: "created", Value: func(u client.User) string { return formatTime(u.CreatedAt) }},
+	{Name: "name", Wide: true, Value: func(u client.User) string { return u.Name }},
+}
 
-	return nil
+// userPlan returns the user's plan, which is Free when unset
+func userPlan(user client.User) string {
+	if user.Plan == "" {
+		return "Free"
+	}
+	return user.Plan
 }
 
 func outputUserDetails(user *client.User) error {
@@ -242,18 +261,8 @@ func outputUserDetails(user *client.User) error {
 	if user.Name != "" {
 		fmt.Printf("Name:         %s\n", user.Name)
 	}
-	plan := user.Plan
-	if plan == "" {
-		plan = "Free"
-	}
-	fmt.Printf("Plan:         %s\n", plan)
+	fmt.Printf("Plan:         %s\n", userPlan(*user))
 	fmt.Printf("Created:      %s\n", formatTime(user.CreatedAt))
 
 	return nil
 }
-
-func outputUserJSON(user *client.User) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(user)
-}
@@ -50,11 +50,11 @@ func init() {
 
 // checkForVersionUpdates checks if a newer version is available (used by version --check)
 func checkForVersionUpdates() {
-	fmt.Println("Checking for updates...")
+	logger.Info("Checking for updates")
 	
 	latest, found, err := selfupdate.DetectLatest("toneclone/cli")
 	if err != nil {
-		fmt.Printf("Error checking for updates: %v\n", err)
+		logger.Error("Failed to check for updates", "error", err)
 		return
 	}
 
@@ -65,7 +65,7 @@ func checkForVersionUpdates() {
 
 	currentVersion, err := semver.Parse(strings.TrimPrefix(Version, "v"))
 	if err != nil {
-		fmt.Printf("Error parsing current version: %v\n", err)
+		logger.Error("Failed to parse current version", "error", err)
 		return
 	}
 
@@ -73,11 +73,11 @@ func checkForVersionUpdates() {
 	fmt.Printf("Latest version:  %s\n", latest.Version)
 
 	if latest.Version.LTE(currentVersion) {
-		fmt.Println("✅ You are running the latest version!")
+		fmt.Println(emoji("✅ ", "") + "You are running the latest version!")
 		return
 	}
 
-	fmt.Printf("🆙 A newer version is available: %s → %s\n", currentVersion, latest.Version)
+	fmt.Printf("%sA newer version is available: %s %s %s\n", emoji("🆙 ", ""), currentVersion, emoji("→", "->"), latest.Version)
 	fmt.Printf("Release URL: %s\n", latest.URL)
 	fmt.Println("\nRun 'toneclone update' to upgrade.")
 }
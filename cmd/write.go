@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/toneclone/cli/internal/config"
+	"github.com/toneclone/cli/internal/fsutil"
+	"github.com/toneclone/cli/internal/postprocess"
+	"github.com/toneclone/cli/internal/ranking"
 	"github.com/toneclone/cli/pkg/client"
 )
 
@@ -26,8 +33,31 @@ var (
 	writeVerbose  bool
 	writeTimeout  int
 	writeJson     bool
+
+	// Generation settings
+	writeFormality    int
+	writeReadingLevel int
+	writeLength       int
+	writeModel        string
+
+	// Candidate ranking flags
+	writeCandidates  int
+	writeBest        bool
+	writeTargetWords int
+	writeTargetGrade float64
+	writeBanned      string
+	writeSamples     []string
+
+	// Post-processing flags
+	writeConvert    string
+	writeWrap       int
+	writeOutFile    string
+	writeAppendFile string
 )
 
+// maxWriteCandidates caps --n to keep accidental fan-out in check
+const maxWriteCandidates = 10
+
 // writeCmd represents the write command
 var writeCmd = &cobra.Command{
 	Use:   "write",
@@ -54,11 +84,47 @@ Profile Support:
   --profile "name1,name2"    Multiple profiles (comma-separated)
   --profile "123,456"        Multiple profiles by ID
 
+Defaults:
+  --persona, --profile, --formality, --reading-level, --length, --model and
+  --output fall back to the defaults in your configuration when omitted.
+  Set them with 'toneclone config set defaults.persona <name>', or per key
+  profile under keys.<profile>.defaults.
+
+Generation Settings:
+  --formality 7        Formality level
+  --reading-level 8    Reading level
+  --length 5           Length level
+  --model <name>       Model to generate with
+
 Output Options:
   --output text     Plain text output (default)
   --output json     JSON output with metadata
-  --verbose         Show generation metadata and statistics`,
-	RunE: runWrite,
+  --verbose         Show generation metadata and statistics
+
+Multiple Candidates:
+  --n 5                   Generate several candidates concurrently
+  --best                  Print only the highest ranked candidate
+  --target-words 120      Prefer candidates close to this word count
+  --target-grade 8        Prefer candidates close to this reading grade
+  --banned "a,b"          Penalize candidates containing these phrases
+  --samples ./writing     Prefer candidates similar to these local samples
+
+  Candidates are ranked locally. Similarity is measured against the
+  --samples files only: training files cannot be downloaded from the API,
+  so point --samples at local copies of the persona's training samples.
+  With --output json the full ranking, including per-criterion scores, is
+  printed.
+
+Post-processing:
+  --convert plain         Strip Markdown syntax
+  --convert markdown      Keep Markdown (normalized)
+  --convert html          Render Markdown to HTML (text is escaped, unsafe links dropped)
+  --convert email         Plain text with a detected "Subject:" header
+  --wrap 72               Hard-wrap text output at 72 columns
+  --out result.md         Write output to a file (atomically replaced)
+  --append notes.md       Append output to a file`,
+	Annotations: withFlagScopes(requireScopes(client.ScopeTextGenerate, client.ScopePersonasRead), "profile", client.ScopeProfilesRead),
+	RunE:        runWrite,
 }
 
 func init() {
@@ -73,9 +139,31 @@ func init() {
 	writeCmd.Flags().BoolVar(&writeVerbose, "verbose", false, "show generation metadata and statistics")
 	writeCmd.Flags().IntVar(&writeTimeout, "timeout", 30, "request timeout in seconds")
 	writeCmd.Flags().BoolVar(&writeJson, "json", false, "output in JSON format (shorthand for --output json)")
-
-	// Make persona required
-	writeCmd.MarkFlagRequired("persona")
+	writeCmd.RegisterFlagCompletionFunc("persona", completeNames(completePersonas, false))
+	writeCmd.RegisterFlagCompletionFunc("profile", completeNames(completeProfiles, true))
+
+	// Generation settings
+	writeCmd.Flags().IntVar(&writeFormality, "formality", 0, "formality level")
+	writeCmd.Flags().IntVar(&writeReadingLevel, "reading-level", 0, "reading level")
+	writeCmd.Flags().IntVar(&writeLength, "length", 0, "length level")
+	writeCmd.Flags().StringVar(&writeModel, "model", "", "model to generate with")
+
+	// Candidate ranking flags
+	writeCmd.Flags().IntVar(&writeCandidates, "n", 1, fmt.Sprintf("number of candidates to generate (max %d)", maxWriteCandidates))
+	writeCmd.Flags().BoolVar(&writeBest, "best", false, "print only the best ranked candidate")
+	writeCmd.Flags().IntVar(&writeTargetWords, "target-words", 0, "rank candidates by closeness to this word count")
+	writeCmd.Flags().Float64Var(&writeTargetGrade, "target-grade", 0, "rank candidates by closeness to this reading grade level")
+	writeCmd.Flags().StringVar(&writeBanned, "banned", "", "comma-separated phrases that penalize a candidate")
+	writeCmd.Flags().StringSliceVar(&writeSamples, "samples", nil, "local files or directories of writing samples to rank similarity against")
+
+	// Post-processing flags
+	writeCmd.Flags().StringVar(&writeConvert, "convert", "", "convert generated text: "+strings.Join(postprocess.Formats, ", "))
+	writeCmd.Flags().StringVar(&writeConvert, "format", "", "convert generated text")
+	writeCmd.Flags().MarkDeprecated("format", "use --convert instead")
+	writeCmd.Flags().IntVar(&writeWrap, "wrap", 0, "hard-wrap generated text at N columns")
+	writeCmd.Flags().StringVar(&writeOutFile, "out", "", "write output to a file instead of stdout")
+	writeCmd.Flags().StringVar(&writeAppendFile, "append", "", "append output to a file instead of stdout")
+	writeCmd.MarkFlagsMutuallyExclusive("out", "append")
 }
 
 func runWrite(cmd *cobra.Command, args []string) error {
@@ -91,12 +179,32 @@ func runWrite(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("authentication required: %w", err)
 	}
 
+	applyWriteDefaults(cmd, cfg.CurrentDefaults())
+	// Profiles from the defaults are only known now; --profile was checked
+	// before the command ran
+	if writeProfile != "" && !cmd.Flags().Changed("profile") {
+		if err := checkScopes(cmd, []client.APIKeyScope{client.ScopeProfilesRead}); err != nil {
+			return err
+		}
+	}
+	if writePersona == "" && !canPick() {
+		return fmt.Errorf("no persona given: use --persona or set a default with 'toneclone config set defaults.persona <name>'")
+	}
+
 	// Create API client
-	apiClient := client.NewToneCloneClientFromConfig(
-		keyConfig.BaseURL,
-		keyConfig.Key,
-		time.Duration(writeTimeout)*time.Second,
-	)
+	apiClient, err := newAPIClient(keyConfig, time.Duration(writeTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+
+	if writeCandidates < 1 || writeCandidates > maxWriteCandidates {
+		return fmt.Errorf("--n must be between 1 and %d", maxWriteCandidates)
+	}
+
+	// Fail on a bad --convert before spending a generation
+	if _, err := postProcessWriteText(""); err != nil {
+		return err
+	}
 
 	// Get the prompt
 	prompt, err := getWritePrompt()
@@ -108,117 +216,176 @@ func runWrite(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("prompt cannot be empty")
 	}
 
-	// Validate persona exists
-	persona, err := validatePersona(cmd.Context(), apiClient, writePersona)
+	// Validate persona exists, or let the user choose one
+	persona, err := selectPersona(cmd.Context(), apiClient, keyConfig, writePersona)
 	if err != nil {
 		return fmt.Errorf("persona validation failed: %w", err)
 	}
 
 	// Validate profiles if specified
-	var profileID string
 	var validatedProfiles []*client.Profile
 	if writeProfile != "" {
 		// Support comma-separated profiles
-		profileInputs := strings.Split(writeProfile, ",")
-		for i, profileInput := range profileInputs {
-			profileInputs[i] = strings.TrimSpace(profileInput)
-		}
-
-		// Validate each profile
-		for _, profileInput := range profileInputs {
-			if profileInput == "" {
-				continue
-			}
-			profile, err := validateProfile(cmd.Context(), apiClient, profileInput)
+		for _, profileInput := range splitList(writeProfile) {
+			profile, err := validateProfile(cmd.Context(), apiClient, keyConfig, profileInput)
 			if err != nil {
 				return fmt.Errorf("profile validation failed for '%s': %w", profileInput, err)
 			}
 			validatedProfiles = append(validatedProfiles, profile)
 		}
-
-		// Set up profile information
-		if len(validatedProfiles) > 0 {
-			if len(validatedProfiles) == 1 {
-				// Single profile - use legacy field for backward compatibility
-				profileID = validatedProfiles[0].ProfileID
-			} else {
-				// Multiple profiles - use new array field
-				var profileIDs []string
-				var profileNames []string
-				for _, profile := range validatedProfiles {
-					profileIDs = append(profileIDs, profile.ProfileID)
-					profileNames = append(profileNames, profile.Name)
-				}
-				
-				// Create generation request with multiple profiles
-				request := &client.GenerateTextRequest{
-					Prompt:     prompt,
-					PersonaID:  persona.PersonaID,
-					ProfileIDs: profileIDs,
-				}
-				
-				// Show generation info if verbose
-				if writeVerbose {
-					fmt.Fprintf(os.Stderr, "Generating text with persona: %s (%s)\n", persona.Name, persona.PersonaID)
-					fmt.Fprintf(os.Stderr, "Using profiles: %s\n", strings.Join(profileNames, ", "))
-				}
-				
-				// Generate and return early for multiple profiles
-				response, err := apiClient.Generate.Text(cmd.Context(), request)
-				if err != nil {
-					// Check for rate limit error and provide helpful message
-					if rateLimitErr, ok := err.(*client.RateLimitError); ok {
-						if rateLimitErr.RetryAfterSeconds > 0 {
-							return fmt.Errorf("Rate limit exceeded. Please try again in %d seconds", rateLimitErr.RetryAfterSeconds)
-						}
-						return fmt.Errorf("Rate limit exceeded. Please wait before making another request")
-					}
-					return fmt.Errorf("text generation failed: %w", err)
-				}
-				return outputWriteText(response, persona)
-			}
-		}
 	}
 
-	// Create generation request (single or no profile)
+	// Create generation request
 	request := &client.GenerateTextRequest{
-		Prompt:    prompt,
-		PersonaID: persona.PersonaID,
-		ProfileID: profileID,
+		Prompt:       prompt,
+		PersonaID:    persona.PersonaID,
+		Formality:    writeFormality,
+		ReadingLevel: writeReadingLevel,
+		Length:       writeLength,
+		Model:        writeModel,
+	}
+
+	var profileNames []string
+	if len(validatedProfiles) == 1 {
+		// Single profile - use legacy field for backward compatibility
+		request.ProfileID = validatedProfiles[0].ProfileID
+	} else if len(validatedProfiles) > 1 {
+		// Multiple profiles - use new array field
+		for _, profile := range validatedProfiles {
+			request.ProfileIDs = append(request.ProfileIDs, profile.ProfileID)
+			profileNames = append(profileNames, profile.Name)
+		}
 	}
 
 	// Show generation info if verbose
 	if writeVerbose {
-		fmt.Fprintf(os.Stderr, "Generating text with persona: %s (%s)\n", persona.Name, persona.PersonaID)
-		if profileID != "" {
-			fmt.Fprintf(os.Stderr, "Using profile: %s\n", profileID)
+		attrs := []interface{}{"persona", persona.Name, "persona_id", persona.PersonaID, "prompt_chars", len(prompt)}
+		if request.ProfileID != "" {
+			attrs = append(attrs, "profile", request.ProfileID)
 		}
-		fmt.Fprintf(os.Stderr, "Prompt length: %d characters\n", len(prompt))
-		fmt.Fprintf(os.Stderr, "Generating...\n\n")
+		if len(profileNames) > 0 {
+			attrs = append(attrs, "profiles", strings.Join(profileNames, ", "))
+		}
+		if writeCandidates > 1 {
+			attrs = append(attrs, "candidates", writeCandidates)
+		}
+		logger.Info("Generating text", attrs...)
 	}
 
 	// Generate text
 	ctx, cancel := context.WithTimeout(cmd.Context(), time.Duration(writeTimeout)*time.Second)
 	defer cancel()
 
+	// Buffer output destined for a file so it is written in one go
+	var out io.Writer = os.Stdout
+	var buf bytes.Buffer
+	if writeOutFile != "" || writeAppendFile != "" {
+		out = &buf
+	}
+
+	if writeCandidates > 1 {
+		err := runWriteCandidates(ctx, out, apiClient, request, persona)
+		if err != nil && ExitCode(err) != ExitPartial {
+			return err
+		}
+		// Save the candidates that succeeded even when others failed
+		if saveErr := saveWriteOutput(buf.Bytes()); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+
 	response, err := apiClient.Generate.Text(ctx, request)
 	if err != nil {
-		// Check for rate limit error and provide helpful message
-		if rateLimitErr, ok := err.(*client.RateLimitError); ok {
-			if rateLimitErr.RetryAfterSeconds > 0 {
-				return fmt.Errorf("Rate limit exceeded. Please try again in %d seconds", rateLimitErr.RetryAfterSeconds)
-			}
-			return fmt.Errorf("Rate limit exceeded. Please wait before making another request")
-		}
-		return fmt.Errorf("text generation failed: %w", err)
+		return generationError(err)
+	}
+
+	response.Text, err = postProcessWriteText(response.Text)
+	if err != nil {
+		return err
 	}
 
 	// Output based on format
 	if writeJson || writeOutput == "json" {
-		return outputWriteJSON(response, persona)
+		err = outputWriteJSON(out, response, persona)
+	} else {
+		err = outputWriteText(out, response, persona)
+	}
+	if err != nil {
+		return err
 	}
 
-	return outputWriteText(response, persona)
+	return saveWriteOutput(buf.Bytes())
+}
+
+// applyWriteDefaults fills in options not given on the command line from
+// the configured defaults
+func applyWriteDefaults(cmd *cobra.Command, defaults config.Defaults) {
+	flags := cmd.Flags()
+
+	if !flags.Changed("persona") {
+		writePersona = defaults.Persona
+	}
+	if !flags.Changed("profile") && len(defaults.Profiles) > 0 {
+		writeProfile = strings.Join(defaults.Profiles, ",")
+	}
+	if !flags.Changed("formality") {
+		writeFormality = defaults.Formality
+	}
+	if !flags.Changed("reading-level") {
+		writeReadingLevel = defaults.ReadingLevel
+	}
+	if !flags.Changed("length") {
+		writeLength = defaults.Length
+	}
+	if !flags.Changed("model") {
+		writeModel = defaults.Model
+	}
+	if !flags.Changed("output") && !writeJson && defaults.Output != "" {
+		writeOutput = defaults.Output
+	}
+}
+
+// postProcessWriteText applies --convert and --wrap to generated text
+func postProcessWriteText(text string) (string, error) {
+	return postprocess.Apply(text, postprocess.Options{
+		Format: writeConvert,
+		Wrap:   writeWrap,
+	})
+}
+
+// saveWriteOutput writes buffered output to --out or --append, if set
+func saveWriteOutput(data []byte) error {
+	switch {
+	case writeOutFile != "":
+		if err := fsutil.WriteFileAtomic(writeOutFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		if writeVerbose {
+			logger.Info("Wrote output", "bytes", len(data), "file", writeOutFile)
+		}
+	case writeAppendFile != "":
+		if err := fsutil.AppendFile(writeAppendFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to append output: %w", err)
+		}
+		if writeVerbose {
+			logger.Info("Appended output", "bytes", len(data), "file", writeAppendFile)
+		}
+	}
+	return nil
+}
+
+// generationError converts a text generation error into a user-facing error
+func generationError(err error) error {
+	// Check for rate limit error and provide helpful message
+	var rateLimitErr *client.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		if rateLimitErr.RetryAfterSeconds > 0 {
+			return fmt.Errorf("Rate limit exceeded. Please try again in %d seconds", rateLimitErr.RetryAfterSeconds)
+		}
+		return fmt.Errorf("Rate limit exceeded. Please wait before making another request")
+	}
+	return fmt.Errorf("text generation failed: %w", err)
 }
 
 func getWritePrompt() (string, error) {
@@ -279,31 +446,31 @@ func readWritePromptFromStdin() (string, error) {
 }
 
 
-func outputWriteText(response *client.GenerateTextResponse, persona *client.Persona) error {
+func outputWriteText(w io.Writer, response *client.GenerateTextResponse, persona *client.Persona) error {
 	// Just output the generated text
-	fmt.Print(response.Text)
+	fmt.Fprint(w, response.Text)
 
 	// Add newline if the text doesn't end with one
 	if !strings.HasSuffix(response.Text, "\n") {
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Show metadata if verbose
 	if writeVerbose {
-		fmt.Fprintf(os.Stderr, "\n--- Generation Metadata ---\n")
-		fmt.Fprintf(os.Stderr, "Persona: %s (%s)\n", persona.Name, persona.PersonaID)
+		attrs := []interface{}{"persona", persona.Name, "persona_id", persona.PersonaID}
 		if response.Model != "" {
-			fmt.Fprintf(os.Stderr, "Model: %s\n", response.Model)
+			attrs = append(attrs, "model", response.Model)
 		}
 		if response.Tokens > 0 {
-			fmt.Fprintf(os.Stderr, "Tokens generated: %d\n", response.Tokens)
+			attrs = append(attrs, "tokens", response.Tokens)
 		}
+		logger.Info("Generation metadata", attrs...)
 	}
 
 	return nil
 }
 
-func outputWriteJSON(response *client.GenerateTextResponse, persona *client.Persona) error {
+func outputWriteJSON(w io.Writer, response *client.GenerateTextResponse, persona *client.Persona) error {
 	output := map[string]interface{}{
 		"text": response.Text,
 		"persona": map[string]string{
@@ -321,8 +488,228 @@ func outputWriteJSON(response *client.GenerateTextResponse, persona *client.Pers
 	if response.ProfileID != "" {
 		output["profile_id"] = response.ProfileID
 	}
+	if writeConvert != "" {
+		output["format"] = writeConvert
+	}
 
-	encoder := json.NewEncoder(os.Stdout)
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
-}
\ No newline at end of file
+}
+// runWriteCandidates generates several candidates concurrently, ranks them
+// locally and prints either all of them or only the best one
+func runWriteCandidates(ctx context.Context, w io.Writer, apiClient *client.ToneCloneClient, request *client.GenerateTextRequest, persona *client.Persona) error {
+	options := ranking.Options{
+		TargetWords:   writeTargetWords,
+		TargetGrade:   writeTargetGrade,
+		BannedPhrases: splitList(writeBanned),
+	}
+
+	if len(writeSamples) > 0 {
+		samples, err := readWritingSamples(writeSamples)
+		if err != nil {
+			return fmt.Errorf("failed to read samples: %w", err)
+		}
+		options.Samples = samples
+	}
+
+	candidates := generateCandidates(ctx, apiClient, request, writeCandidates)
+	failures := candidates.failures()
+	if len(candidates.texts) == 0 {
+		return failures
+	}
+
+	results := ranking.NewScorer(options).Rank(candidates.texts)
+
+	// Rank on the raw text, then convert what gets printed. Indices refer to
+	// the requests, failed ones included.
+	for i := range results {
+		results[i].Index = candidates.indices[results[i].Index]
+		text, err := postProcessWriteText(results[i].Text)
+		if err != nil {
+			return err
+		}
+		results[i].Text = text
+	}
+
+	if writeVerbose && !options.Enabled() {
+		logger.Info("No ranking criteria given, keeping generation order")
+	}
+
+	if writeJson || writeOutput == "json" {
+		if err := outputWriteCandidatesJSON(w, results, candidates.errs, persona, options); err != nil {
+			return err
+		}
+		return failures
+	}
+
+	if writeBest {
+		if err := outputWriteText(w, &client.GenerateTextResponse{
+			Text:      results[0].Text,
+			PersonaID: request.PersonaID,
+			ProfileID: request.ProfileID,
+		}, persona); err != nil {
+			return err
+		}
+		return failures
+	}
+
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "=== Candidate %d of %d (score %.2f) ===\n", result.Rank, len(results), result.Score)
+		fmt.Fprint(w, result.Text)
+		if !strings.HasSuffix(result.Text, "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+
+	return failures
+}
+
+// candidateSet holds the outcome of n generations: the texts of those that
+// succeeded with their request indices, and the error of each request
+type candidateSet struct {
+	texts   []string
+	indices []int
+	errs    []error
+}
+
+// failures returns an error if any generation failed: the first failure
+// when all did, or a partial failure
+func (c candidateSet) failures() error {
+	var failed int
+	var first error
+	for _, err := range c.errs {
+		if err != nil {
+			failed++
+			if first == nil {
+				first = generationError(err)
+			}
+		}
+	}
+	return batchError("candidates", failed, len(c.errs), first)
+}
+
+// generateCandidates runs n generations of the same request concurrently.
+// Failed generations are logged as they are collected.
+func generateCandidates(ctx context.Context, apiClient *client.ToneCloneClient, request *client.GenerateTextRequest, n int) candidateSet {
+	texts := make([]string, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Each generation gets its own copy since Text mutates the request
+			req := *request
+			response, err := apiClient.Generate.Text(ctx, &req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			texts[i] = response.Text
+		}(i)
+	}
+	wg.Wait()
+
+	set := candidateSet{errs: errs}
+	for i := range texts {
+		if errs[i] != nil {
+			logger.Warn("Candidate failed", "candidate", i+1, "error", errs[i])
+			continue
+		}
+		set.texts = append(set.texts, texts[i])
+		set.indices = append(set.indices, i)
+	}
+	return set
+}
+
+// readWritingSamples reads text samples from files and directories
+func readWritingSamples(paths []string) ([]string, error) {
+	var samples []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, string(data))
+			continue
+		}
+
+		err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(p))
+			if fi.IsDir() || (ext != ".txt" && ext != ".md") {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			samples = append(samples, string(data))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no .txt or .md samples found")
+	}
+
+	return samples, nil
+}
+
+func outputWriteCandidatesJSON(w io.Writer, results []ranking.Result, errs []error, persona *client.Persona, options ranking.Options) error {
+	criteria := map[string]interface{}{}
+	if options.TargetWords > 0 {
+		criteria["target_words"] = options.TargetWords
+	}
+	if options.TargetGrade > 0 {
+		criteria["target_grade"] = options.TargetGrade
+	}
+	if len(options.BannedPhrases) > 0 {
+		criteria["banned"] = options.BannedPhrases
+	}
+	if len(options.Samples) > 0 {
+		criteria["samples"] = len(options.Samples)
+	}
+
+	output := map[string]interface{}{
+		"persona": map[string]string{
+			"id":   persona.PersonaID,
+			"name": persona.Name,
+		},
+		"criteria":   criteria,
+		"best":       results[0],
+		"candidates": results,
+		"count":      len(results),
+	}
+
+	var failed []map[string]interface{}
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, map[string]interface{}{"index": i, "error": err.Error()})
+		}
+	}
+	if len(failed) > 0 {
+		output["failed"] = failed
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
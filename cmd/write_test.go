@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/toneclone/cli/pkg/client"
+	"github.com/toneclone/cli/pkg/clienttest"
+)
+
+func TestGenerateCandidatesKeepsFailures(t *testing.T) {
+	server, url := clienttest.NewTestServer(t)
+	apiClient := clienttest.NewClient(url)
+	persona := server.AddPersona("Blogger")
+	request := &client.GenerateTextRequest{Prompt: "Hello", PersonaID: persona.PersonaID}
+
+	server.FailNext(http.StatusBadRequest, 1)
+	candidates := generateCandidates(context.Background(), apiClient, request, 3)
+	if len(candidates.texts) != 2 || len(candidates.errs) != 3 {
+		t.Fatalf("Expected 2 of 3 candidates, got %d texts and %d results", len(candidates.texts), len(candidates.errs))
+	}
+	for _, index := range candidates.indices {
+		if candidates.errs[index] != nil {
+			t.Errorf("Index %d refers to a failed request", index)
+		}
+	}
+	if code := ExitCode(candidates.failures()); code != ExitPartial {
+		t.Errorf("Expected exit code %d, got %d", ExitPartial, code)
+	}
+
+	server.FailNext(http.StatusNotFound, 2)
+	candidates = generateCandidates(context.Background(), apiClient, request, 2)
+	if code := ExitCode(candidates.failures()); len(candidates.texts) != 0 || code != ExitNotFound {
+		t.Errorf("Expected every candidate to fail with exit code %d, got %d", ExitNotFound, code)
+	}
+}
+
+func TestWriteCandidatesSavesSurvivors(t *testing.T) {
+	// Fail the first generation only, not the persona lookup before it
+	server := clienttest.New()
+	server.AddPersona("Blogger")
+	var failed atomic.Bool
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/query" && failed.CompareAndSwap(false, true) {
+			http.Error(w, `{"error":"bad_request"}`, http.StatusBadRequest)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(api.Close)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TONECLONE_SYSTEM_CONFIG", filepath.Join(home, "none.yaml"))
+	t.Setenv("TONECLONE_CACHE_DIR", t.TempDir())
+	t.Setenv("TONECLONE_RECORD", "")
+	t.Setenv("TONECLONE_REPLAY", "")
+	t.Setenv("TONECLONE_API_KEY", clienttest.DefaultAPIKey)
+	t.Setenv("TONECLONE_BASE_URL", api.URL)
+
+	resetFlags(t, writeCmd.Flags())
+	outFile := filepath.Join(t.TempDir(), "out.txt")
+	if err := writeCmd.ParseFlags([]string{"--persona", "Blogger", "--prompt", "Hello", "--n", "3", "--out", outFile}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	writeCmd.SetContext(context.Background())
+
+	err := runWrite(writeCmd, nil)
+	if code := ExitCode(err); code != ExitPartial {
+		t.Errorf("Expected exit code %d, got %d (%v)", ExitPartial, code, err)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Expected the surviving candidates to be saved: %v", err)
+	}
+	if count := strings.Count(string(data), "=== Candidate "); count != 2 {
+		t.Errorf("Expected 2 candidates in the file, got %d:\n%s", count, data)
+	}
+}
@@ -0,0 +1,118 @@
+// Package cache stores small JSON values on disk with a time-to-live.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/toneclone/cli/internal/fsutil"
+)
+
+// Cache is a directory of JSON entries grouped by namespace
+type Cache struct {
+	dir string
+}
+
+// entry is the on-disk format of a cached value
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// New returns a cache rooted at dir
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Default returns the cache in TONECLONE_CACHE_DIR, or the user cache directory
+func Default() (*Cache, error) {
+	if dir := os.Getenv("TONECLONE_CACHE_DIR"); dir != "" {
+		return New(dir), nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	return New(filepath.Join(dir, "toneclone")), nil
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get loads the value stored under key into v. It returns false if there is
+// no entry or the entry is older than ttl.
+func (c *Cache) Get(namespace, key string, ttl time.Duration, v interface{}) (bool, error) {
+	data, err := os.ReadFile(c.path(namespace, key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		// A corrupt entry is a miss
+		return false, nil
+	}
+
+	if ttl > 0 && time.Since(e.StoredAt) > ttl {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Set stores v under key
+func (c *Cache) Set(namespace, key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	data, err := json.Marshal(entry{StoredAt: time.Now().UTC(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	path := c.path(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return fsutil.WriteFileAtomic(path, data, 0600)
+}
+
+// Delete removes the entry stored under key
+func (c *Cache) Delete(namespace, key string) error {
+	if err := os.Remove(c.path(namespace, key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete cache entry: %w", err)
+	}
+	return nil
+}
+
+// Clear removes every entry in a namespace
+func (c *Cache) Clear(namespace string) error {
+	if err := os.RemoveAll(filepath.Join(c.dir, namespace)); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// path hashes the key so secrets such as API keys never appear in file names
+func (c *Cache) path(namespace, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, namespace, hex.EncodeToString(sum[:16])+".json")
+}
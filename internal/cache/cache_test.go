@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetGet(t *testing.T) {
+	c := New(t.TempDir())
+
+	if err := c.Set("scopes", "tc_live_secret", []string{"personas:read"}); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+
+	var got []string
+	ok, err := c.Get("scopes", "tc_live_secret", time.Hour, &got)
+	if err != nil || !ok {
+		t.Fatalf("Expected a hit, got ok=%v err=%v", ok, err)
+	}
+	if len(got) != 1 || got[0] != "personas:read" {
+		t.Errorf("Unexpected value: %v", got)
+	}
+
+	// Keys are hashed so secrets never reach the file system
+	entries, _ := os.ReadDir(filepath.Join(c.Dir(), "scopes"))
+	for _, e := range entries {
+		if strings.Contains(e.Name(), "secret") {
+			t.Errorf("Cache file name leaks the key: %s", e.Name())
+		}
+	}
+}
+
+func TestExpiryAndDelete(t *testing.T) {
+	c := New(t.TempDir())
+	c.Set("ns", "k", 42)
+
+	var v int
+	if ok, _ := c.Get("ns", "k", time.Nanosecond, &v); ok {
+		t.Error("Expected expired entry to miss")
+	}
+
+	if err := c.Delete("ns", "k"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if ok, _ := c.Get("ns", "k", 0, &v); ok {
+		t.Error("Expected deleted entry to miss")
+	}
+
+	c.Set("ns", "a", 1)
+	if err := c.Clear("ns"); err != nil {
+		t.Fatalf("Failed to clear: %v", err)
+	}
+	if ok, _ := c.Get("ns", "a", 0, &v); ok {
+		t.Error("Expected cleared entry to miss")
+	}
+}
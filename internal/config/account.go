@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// Sources an account (API key profile) can be selected from, highest
+// precedence first
+const (
+	AccountSourceFlag        = "--account"
+	AccountSourceEnv         = "TONECLONE_ACCOUNT"
+	AccountSourceProfileFlag = "--profile"
+	AccountSourceProfileEnv  = "TONECLONE_PROFILE"
+	AccountSourceDefault     = "default_key"
+	AccountSourceAPIKeyEnv   = "TONECLONE_API_KEY"
+)
+
+// AccountFlag and ProfileFlag hold the account selected with the global
+// --account flag and its deprecated predecessor, the global --profile flag.
+// The CLI fills them in before loading the configuration.
+var (
+	AccountFlag string
+	ProfileFlag string
+)
+
+// AccountSelection is the account chosen for a run and what chose it
+type AccountSelection struct {
+	Name   string
+	Source string
+}
+
+// Deprecated reports whether the account was chosen with an old-style
+// --profile flag or TONECLONE_PROFILE
+func (s AccountSelection) Deprecated() bool {
+	return s.Source == AccountSourceProfileFlag || s.Source == AccountSourceProfileEnv
+}
+
+// DeprecationWarning describes how to move off a deprecated selection, or
+// returns "" if the selection is current
+func (s AccountSelection) DeprecationWarning() string {
+	switch s.Source {
+	case AccountSourceProfileFlag:
+		return "the global --profile flag is deprecated for choosing an account; use --account"
+	case AccountSourceProfileEnv:
+		return "TONECLONE_PROFILE is deprecated; use TONECLONE_ACCOUNT"
+	}
+	return ""
+}
+
+// ResolveAccount returns the account to use. --account wins over
+// TONECLONE_ACCOUNT, which wins over the deprecated --profile flag and
+// TONECLONE_PROFILE. Without any of them the configured default_key is used,
+// and finally a key from TONECLONE_API_KEY.
+func (c *Config) ResolveAccount() AccountSelection {
+	candidates := []AccountSelection{
+		{AccountFlag, AccountSourceFlag},
+		{os.Getenv("TONECLONE_ACCOUNT"), AccountSourceEnv},
+		{ProfileFlag, AccountSourceProfileFlag},
+		{os.Getenv("TONECLONE_PROFILE"), AccountSourceProfileEnv},
+		{c.DefaultKey, AccountSourceDefault},
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Name != "" {
+			return candidate
+		}
+	}
+
+	if os.Getenv("TONECLONE_API_KEY") != "" {
+		return AccountSelection{Name: "environment", Source: AccountSourceAPIKeyEnv}
+	}
+	return AccountSelection{}
+}
+
+// accountNotFound explains a selected account that is not configured
+func accountNotFound(selection AccountSelection) error {
+	if selection.Source == AccountSourceDefault {
+		return fmt.Errorf("API key profile '%s' not found", selection.Name)
+	}
+	return fmt.Errorf("API key profile '%s' (from %s) not found", selection.Name, selection.Source)
+}
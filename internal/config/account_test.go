@@ -0,0 +1,92 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// resetAccountSelection clears every source of account selection
+func resetAccountSelection(t *testing.T) {
+	t.Helper()
+
+	AccountFlag, ProfileFlag = "", ""
+	t.Cleanup(func() { AccountFlag, ProfileFlag = "", "" })
+
+	for _, env := range []string{"TONECLONE_ACCOUNT", "TONECLONE_PROFILE", "TONECLONE_API_KEY"} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestResolveAccountPrecedence(t *testing.T) {
+	cfg := NewConfig()
+	cfg.DefaultKey = "default"
+
+	tests := []struct {
+		name        string
+		account     string
+		accountEnv  string
+		profile     string
+		profileEnv  string
+		want        AccountSelection
+		wantWarning bool
+	}{
+		{name: "config default", want: AccountSelection{"default", AccountSourceDefault}},
+		{name: "account flag", account: "a", accountEnv: "b", profile: "c", profileEnv: "d", want: AccountSelection{"a", AccountSourceFlag}},
+		{name: "account env", accountEnv: "b", profile: "c", profileEnv: "d", want: AccountSelection{"b", AccountSourceEnv}},
+		{name: "deprecated profile flag", profile: "c", profileEnv: "d", want: AccountSelection{"c", AccountSourceProfileFlag}, wantWarning: true},
+		{name: "deprecated profile env", profileEnv: "d", want: AccountSelection{"d", AccountSourceProfileEnv}, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetAccountSelection(t)
+			AccountFlag, ProfileFlag = tt.account, tt.profile
+			t.Setenv("TONECLONE_ACCOUNT", tt.accountEnv)
+			t.Setenv("TONECLONE_PROFILE", tt.profileEnv)
+
+			got := cfg.ResolveAccount()
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+			if got.Deprecated() != tt.wantWarning || (got.DeprecationWarning() != "") != tt.wantWarning {
+				t.Errorf("Unexpected deprecation state for %+v", got)
+			}
+		})
+	}
+}
+
+func TestResolveAccountFallsBackToEnvironmentKey(t *testing.T) {
+	resetAccountSelection(t)
+
+	cfg := NewConfig()
+	if got := cfg.ResolveAccount(); got.Name != "" {
+		t.Errorf("Expected no account, got %+v", got)
+	}
+
+	t.Setenv("TONECLONE_API_KEY", "tc_test_envkey1234")
+	if got := cfg.ResolveAccount(); got.Name != "environment" || got.Source != AccountSourceAPIKeyEnv {
+		t.Errorf("Expected the environment key, got %+v", got)
+	}
+}
+
+func TestGetCurrentKeyUsesAccount(t *testing.T) {
+	resetAccountSelection(t)
+
+	cfg := NewConfig()
+	cfg.AddKey("test", "tc_test_abc123", "https://test.api.com")
+	cfg.AddKey("prod", "tc_live_xyz789", "https://api.toneclone.ai")
+
+	AccountFlag = "prod"
+	key, err := cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.Key != "tc_live_xyz789" || cfg.GetCurrentKeyName() != "prod" {
+		t.Errorf("Expected the prod key, got %s", key.Key)
+	}
+
+	AccountFlag = "missing"
+	if _, err := cfg.GetCurrentKey(); err == nil || !strings.Contains(err.Error(), "from --account") {
+		t.Errorf("Expected error naming --account, got %v", err)
+	}
+}
@@ -1,23 +1,55 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
+// Values accepted for log_level and log_format
+var (
+	LogLevels  = []string{"debug", "info", "warn", "error"}
+	LogFormats = []string{"text", "json"}
+)
+
+// ErrNoAPIKey is returned by GetCurrentKey when no account is configured
+var ErrNoAPIKey = errors.New("no API key configured. Run 'toneclone auth login' or set TONECLONE_API_KEY environment variable")
+
 // APIKeyConfig represents configuration for a named API key
 type APIKeyConfig struct {
-	Key     string `yaml:"key" json:"key"`
-	BaseURL string `yaml:"base_url" json:"base_url"`
+	Key     string `yaml:"key,omitempty" json:"key,omitempty"`
+	Env     string `yaml:"env,omitempty" json:"env,omitempty"` // named environment, replacing base_url and timeout
+	BaseURL string `yaml:"base_url,omitempty" json:"base_url,omitempty"`
 	Timeout int    `yaml:"timeout,omitempty" json:"timeout,omitempty"` // seconds
+
+	// Credential store holding the key instead of the config file
+	Store      string `yaml:"store,omitempty" json:"store,omitempty"`
+	KeyRef     string `yaml:"key_ref,omitempty" json:"key_ref,omitempty"`
+	KeyCommand string `yaml:"key_command,omitempty" json:"key_command,omitempty"`
+
+	// Defaults for this key profile, overriding the global defaults
+	Defaults Defaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+
+	// OAuth tokens from 'auth login --device'. Key then holds the access token.
+	Auth         string    `yaml:"auth,omitempty" json:"auth,omitempty"` // "oauth", or empty for an API key
+	RefreshToken string    `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+	TokenExpiry  time.Time `yaml:"token_expiry,omitempty" json:"token_expiry,omitempty"`
+
+	// Profile name and network settings in effect for the key, filled in by
+	// GetCurrentKey
+	Profile string        `yaml:"-" json:"-"`
+	Network NetworkConfig `yaml:"-" json:"-"`
 }
 
 // Config represents the complete CLI configuration
 type Config struct {
+	Version    int                     `yaml:"version,omitempty" json:"version,omitempty"`
 	DefaultKey string                  `yaml:"default_key,omitempty" json:"default_key,omitempty"`
 	Keys       map[string]APIKeyConfig `yaml:"keys" json:"keys"`
 
@@ -25,57 +57,63 @@ type Config struct {
 	Verbose bool `yaml:"verbose,omitempty" json:"verbose,omitempty"`
 	Debug   bool `yaml:"debug,omitempty" json:"debug,omitempty"`
 
+	// Diagnostics written to stderr (empty log_level follows verbose and debug)
+	LogLevel  string `yaml:"log_level,omitempty" json:"log_level,omitempty"`
+	LogFormat string `yaml:"log_format,omitempty" json:"log_format,omitempty"`
+
+	// Print plain text instead of emoji status symbols
+	NoEmoji bool `yaml:"no_emoji,omitempty" json:"no_emoji,omitempty"`
+
 	// Default values
 	DefaultTimeout int    `yaml:"default_timeout,omitempty" json:"default_timeout,omitempty"`
 	DefaultBaseURL string `yaml:"default_base_url,omitempty" json:"default_base_url,omitempty"`
+
+	// Named environments and the one used by key profiles without their own
+	Environments map[string]EnvironmentConfig `yaml:"environments,omitempty" json:"environments,omitempty"`
+	DefaultEnv   string                       `yaml:"default_env,omitempty" json:"default_env,omitempty"`
+
+	// TLS and proxy settings for every environment
+	Network NetworkConfig `yaml:"network,omitempty" json:"network,omitempty"`
+
+	// Authorization server for device logins
+	OAuth OAuthConfig `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+
+	// Credential store used for newly saved keys (empty keeps them in this file)
+	CredentialStore string `yaml:"credential_store,omitempty" json:"credential_store,omitempty"`
+
+	// Defaults for write commands, which project files may also set
+	Defaults Defaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+
+	layers *layerState
+}
+
+// Defaults holds default values for the options of write commands
+type Defaults struct {
+	Persona      string   `yaml:"persona,omitempty" json:"persona,omitempty"`
+	Profiles     []string `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	Formality    int      `yaml:"formality,omitempty" json:"formality,omitempty"`
+	ReadingLevel int      `yaml:"reading_level,omitempty" json:"reading_level,omitempty"`
+	Length       int      `yaml:"length,omitempty" json:"length,omitempty"`
+	Model        string   `yaml:"model,omitempty" json:"model,omitempty"`
+	Output       string   `yaml:"output,omitempty" json:"output,omitempty"` // text or json
 }
 
 // NewConfig creates a new configuration with defaults
 func NewConfig() *Config {
 	return &Config{
+		Version:        CurrentVersion,
 		Keys:           make(map[string]APIKeyConfig),
 		DefaultTimeout: 30,
 		DefaultBaseURL: "https://api.toneclone.ai",
 	}
 }
 
-// LoadConfig loads configuration from file and environment variables
+// LoadConfig loads configuration from the system, user and project config
+// files, environment variables and flags, in increasing order of precedence
 func LoadConfig() (*Config, error) {
-	config := NewConfig()
-
-	// Get config file path
-	configPath := viper.ConfigFileUsed()
-	if configPath == "" {
-		configPath, _ = GetConfigPath()
-	}
-
-	// Try to read config file directly
-	if _, err := os.Stat(configPath); err == nil {
-		data, err := os.ReadFile(configPath)
-		if err == nil {
-			if err := yaml.Unmarshal(data, config); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-			}
-		}
-	}
-
-	// Override with environment variables if present
-	if apiKey := os.Getenv("TONECLONE_API_KEY"); apiKey != "" {
-		// Create or update default key from environment
-		if config.Keys == nil {
-			config.Keys = make(map[string]APIKeyConfig)
-		}
-
-		envKeyName := "environment"
-		config.Keys[envKeyName] = APIKeyConfig{
-			Key:     apiKey,
-			BaseURL: getEnvOrDefault("TONECLONE_BASE_URL", config.DefaultBaseURL),
-		}
-
-		// Set as default if no default is configured
-		if config.DefaultKey == "" {
-			config.DefaultKey = envKeyName
-		}
+	config, err := loadLayered(userConfigPath())
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate configuration
@@ -93,8 +131,14 @@ func (c *Config) SaveConfig(configPath string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// Only values from the user's own file are written back
+	values, err := c.userValues()
+	if err != nil {
+		return err
+	}
+
 	// Marshal to YAML
-	data, err := yaml.Marshal(c)
+	data, err := yaml.Marshal(values)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config to YAML: %w", err)
 	}
@@ -109,28 +153,24 @@ func (c *Config) SaveConfig(configPath string) error {
 
 // GetCurrentKey returns the configuration for the currently selected API key
 func (c *Config) GetCurrentKey() (APIKeyConfig, error) {
-	profile := viper.GetString("profile")
-	keyName := profile
+	selection := c.ResolveAccount()
+	keyName := selection.Name
 
-	// If no profile specified, use default
 	if keyName == "" {
-		keyName = c.DefaultKey
-	}
-
-	// If still no key name, check for environment variable
-	if keyName == "" && os.Getenv("TONECLONE_API_KEY") != "" {
-		keyName = "environment"
-	}
-
-	if keyName == "" {
-		return APIKeyConfig{}, fmt.Errorf("no API key configured. Run 'toneclone auth login' or set TONECLONE_API_KEY environment variable")
+		return APIKeyConfig{}, ErrNoAPIKey
 	}
 
 	keyConfig, exists := c.Keys[keyName]
 	if !exists {
-		return APIKeyConfig{}, fmt.Errorf("API key profile '%s' not found", keyName)
+		return APIKeyConfig{}, accountNotFound(selection)
 	}
 
+	keyConfig, err := c.ApplyEnvironment(keyConfig)
+	if err != nil {
+		return APIKeyConfig{}, err
+	}
+	keyConfig.Profile = keyName
+
 	// Apply defaults
 	if keyConfig.BaseURL == "" {
 		keyConfig.BaseURL = c.DefaultBaseURL
@@ -139,25 +179,19 @@ func (c *Config) GetCurrentKey() (APIKeyConfig, error) {
 		keyConfig.Timeout = c.DefaultTimeout
 	}
 
+	// Fetch the key from its credential store
+	key, err := keyConfig.ResolveKey(keyName)
+	if err != nil {
+		return APIKeyConfig{}, err
+	}
+	keyConfig.Key = key
+
 	return keyConfig, nil
 }
 
 // GetCurrentKeyName returns the name of the currently selected API key
 func (c *Config) GetCurrentKeyName() string {
-	profile := viper.GetString("profile")
-	if profile != "" {
-		return profile
-	}
-
-	if c.DefaultKey != "" {
-		return c.DefaultKey
-	}
-
-	if os.Getenv("TONECLONE_API_KEY") != "" {
-		return "environment"
-	}
-
-	return ""
+	return c.ResolveAccount().Name
 }
 
 // AddKey adds a new API key configuration
@@ -184,10 +218,15 @@ func (c *Config) AddKey(name string, key string, baseURL string) {
 
 // RemoveKey removes an API key configuration
 func (c *Config) RemoveKey(name string) error {
-	if _, exists := c.Keys[name]; !exists {
+	keyConfig, exists := c.Keys[name]
+	if !exists {
 		return fmt.Errorf("API key profile '%s' not found", name)
 	}
 
+	if err := deleteStoredKey(name, keyConfig); err != nil {
+		return err
+	}
+
 	delete(c.Keys, name)
 
 	// If this was the default key, clear the default
@@ -231,24 +270,93 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// key_command is set per profile, so it cannot be the default store
+	if c.CredentialStore != "" && (c.CredentialStore == StoreCommand || !containsString(CredentialStores, c.CredentialStore)) {
+		return fmt.Errorf("invalid credential_store '%s' (use %s, %s or %s)", c.CredentialStore, StoreKeyring, StoreEncryptedFile, StoreFake)
+	}
+
+	if c.LogLevel != "" && !containsString(LogLevels, strings.ToLower(c.LogLevel)) {
+		return fmt.Errorf("invalid log_level '%s' (use %s)", c.LogLevel, strings.Join(LogLevels, ", "))
+	}
+	if c.LogFormat != "" && !containsString(LogFormats, c.LogFormat) {
+		return fmt.Errorf("invalid log_format '%s' (use %s)", c.LogFormat, strings.Join(LogFormats, " or "))
+	}
+
+	if err := c.Defaults.Validate(); err != nil {
+		return fmt.Errorf("invalid defaults: %w", err)
+	}
+
+	if err := c.Network.Validate(); err != nil {
+		return fmt.Errorf("invalid network settings: %w", err)
+	}
+
+	// Validate environments
+	if c.DefaultEnv != "" {
+		if _, exists := c.Environments[c.DefaultEnv]; !exists {
+			return fmt.Errorf("default environment '%s' not found in configured environments", c.DefaultEnv)
+		}
+	}
+	for name, env := range c.Environments {
+		if env.BaseURL == "" {
+			return fmt.Errorf("base URL for environment '%s' is empty", name)
+		}
+		if env.Timeout < 0 {
+			return fmt.Errorf("timeout for environment '%s' cannot be negative", name)
+		}
+		if err := env.Network.Validate(); err != nil {
+			return fmt.Errorf("invalid network settings for environment '%s': %w", name, err)
+		}
+	}
+
 	// Validate each key configuration
 	for name, keyConfig := range c.Keys {
-		if keyConfig.Key == "" {
-			return fmt.Errorf("API key for profile '%s' is empty", name)
+		switch keyConfig.Store {
+		case "":
+		case StoreCommand:
+			if keyConfig.KeyCommand == "" {
+				return fmt.Errorf("key_command for profile '%s' is empty", name)
+			}
+		case StoreKeyring, StoreEncryptedFile, StoreFake:
+		default:
+			return fmt.Errorf("unknown credential store '%s' for profile '%s'", keyConfig.Store, name)
 		}
 
-		// Validate key format
-		if !isValidAPIKey(keyConfig.Key) {
-			return fmt.Errorf("API key for profile '%s' has invalid format", name)
+		// Stored keys are checked when they are read
+		if !keyConfig.IsStored() {
+			if keyConfig.Key == "" {
+				return fmt.Errorf("API key for profile '%s' is empty", name)
+			}
+
+			// Validate key format; OAuth access tokens are opaque
+			if !keyConfig.IsOAuth() && !isValidAPIKey(keyConfig.Key) {
+				return fmt.Errorf("API key for profile '%s' has invalid format", name)
+			}
 		}
 
-		if keyConfig.BaseURL == "" {
+		switch keyConfig.Auth {
+		case "", AuthOAuth:
+		default:
+			return fmt.Errorf("unknown auth '%s' for profile '%s'", keyConfig.Auth, name)
+		}
+		if keyConfig.IsOAuth() && keyConfig.Store == StoreCommand {
+			return fmt.Errorf("profile '%s' uses device login and cannot read its key from key_command", name)
+		}
+
+		if keyConfig.Env != "" {
+			if _, exists := c.Environments[keyConfig.Env]; !exists {
+				return fmt.Errorf("environment '%s' for profile '%s' not found", keyConfig.Env, name)
+			}
+		} else if keyConfig.BaseURL == "" && c.DefaultEnv == "" {
 			return fmt.Errorf("base URL for profile '%s' is empty", name)
 		}
 
 		if keyConfig.Timeout < 0 {
 			return fmt.Errorf("timeout for profile '%s' cannot be negative", name)
 		}
+
+		if err := keyConfig.Defaults.Validate(); err != nil {
+			return fmt.Errorf("invalid defaults for profile '%s': %w", name, err)
+		}
 	}
 
 	return nil
@@ -301,3 +409,12 @@ func isValidAPIKey(key string) bool {
 		(key[:8] == "tc_live_" || key[:8] == "tc_test_")) ||
 		key == "test_key" // Allow test key for development
 }
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
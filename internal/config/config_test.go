@@ -198,6 +198,27 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateLogSettings(t *testing.T) {
+	cfg := NewConfig()
+
+	cfg.LogLevel = "WARN"
+	cfg.LogFormat = "json"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid log settings, got error: %v", err)
+	}
+
+	cfg.LogLevel = "loud"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for invalid log level")
+	}
+
+	cfg.LogLevel = ""
+	cfg.LogFormat = "xml"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for invalid log format")
+	}
+}
+
 func TestIsValidAPIKey(t *testing.T) {
 	tests := []struct {
 		key   string
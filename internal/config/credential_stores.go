@@ -0,0 +1,368 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/toneclone/cli/internal/fsutil"
+)
+
+// keyringService is the service name API keys are stored under in the OS keyring
+const keyringService = "toneclone-cli"
+
+// keyringStore keeps keys in the OS keyring using the platform's command
+// line tool: secret-tool (Secret Service) on Linux, security on macOS
+type keyringStore struct {
+	goos string
+}
+
+func newKeyringStore() (CredentialStore, error) {
+	tool := ""
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		tool = "secret-tool"
+	case "darwin":
+		tool = "security"
+	default:
+		return nil, fmt.Errorf("the %s store is not supported on %s; use %s or %s", StoreKeyring, runtime.GOOS, StoreEncryptedFile, StoreCommand)
+	}
+
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil, fmt.Errorf("the %s store requires '%s' to be installed", StoreKeyring, tool)
+	}
+
+	return &keyringStore{goos: runtime.GOOS}, nil
+}
+
+func (s *keyringStore) Get(ref string) (string, error) {
+	var cmd *exec.Cmd
+	if s.goos == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", ref, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", ref)
+	}
+
+	out, err := cmd.Output()
+	secret := strings.TrimSpace(string(out))
+	if err != nil || secret == "" {
+		// Both tools exit non-zero when nothing matches
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (s *keyringStore) Set(ref, secret string) error {
+	var cmd *exec.Cmd
+	if s.goos == "darwin" {
+		// security takes the password only as an argument, which any local
+		// user could read with ps. In interactive mode it reads the command,
+		// password included, from stdin instead.
+		command, err := securityAddCommand(ref, secret)
+		if err != nil {
+			return err
+		}
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(command)
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label=ToneClone CLI ("+ref+")", "service", keyringService, "account", ref)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("keyring: %s", commandError(err, out))
+	}
+	// Interactive mode reports failures on its output but still exits 0
+	if s.goos == "darwin" && len(bytes.TrimSpace(out)) > 0 {
+		return fmt.Errorf("keyring: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// securityAddCommand returns the line that stores secret for ref when fed to
+// "security -i". Values are double-quoted, so they may not contain quotes,
+// backslashes or line breaks, which API keys never do.
+func securityAddCommand(ref, secret string) (string, error) {
+	for _, value := range []string{ref, secret} {
+		if strings.ContainsAny(value, "\"\\\r\n") {
+			return "", errors.New("keyring: cannot store a value containing quotes, backslashes or line breaks")
+		}
+	}
+	return fmt.Sprintf("add-generic-password -U -s \"%s\" -a \"%s\" -w \"%s\"\n", keyringService, ref, secret), nil
+}
+
+func (s *keyringStore) Delete(ref string) error {
+	var cmd *exec.Cmd
+	if s.goos == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", ref)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keyringService, "account", ref)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return ErrCredentialNotFound
+	}
+	return nil
+}
+
+// encryptedFileStore keeps keys in a file encrypted with a passphrase
+// (scrypt key derivation, AES-256-GCM)
+type encryptedFileStore struct {
+	path       string
+	passphrase func() (string, error)
+}
+
+// encryptedFile is the on-disk format of the encrypted file store
+type encryptedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+func (s *encryptedFileStore) Get(ref string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := secrets[ref]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (s *encryptedFileStore) Set(ref, secret string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	secrets[ref] = secret
+	return s.save(secrets)
+}
+
+func (s *encryptedFileStore) Delete(ref string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[ref]; !ok {
+		return ErrCredentialNotFound
+	}
+
+	delete(secrets, ref)
+	return s.save(secrets)
+}
+
+func (s *encryptedFileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	if file.Version != 1 || file.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported credentials file format in %s", s.path)
+	}
+
+	aead, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials file (wrong passphrase?)")
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted credentials: %w", err)
+	}
+	return secrets, nil
+}
+
+func (s *encryptedFileStore) save(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	file := encryptedFile{Version: 1, KDF: "scrypt", Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := s.cipher(file.Salt)
+	if err != nil {
+		return err
+	}
+
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file.Data = aead.Seal(nil, file.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials file: %w", err)
+	}
+
+	return writeSecretFile(s.path, data)
+}
+
+func (s *encryptedFileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// fakeStore keeps keys unencrypted in a JSON file. It stands in for the OS
+// keyring in tests and headless environments and must not be used for real keys.
+type fakeStore struct {
+	path string
+}
+
+func (s *fakeStore) Get(ref string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := secrets[ref]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (s *fakeStore) Set(ref, secret string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	secrets[ref] = secret
+	return s.save(secrets)
+}
+
+func (s *fakeStore) Delete(ref string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[ref]; !ok {
+		return ErrCredentialNotFound
+	}
+
+	delete(secrets, ref)
+	return s.save(secrets)
+}
+
+func (s *fakeStore) load() (map[string]string, error) {
+	secrets := make(map[string]string)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake keyring: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse fake keyring %s: %w", s.path, err)
+	}
+	return secrets, nil
+}
+
+func (s *fakeStore) save(secrets map[string]string) error {
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fake keyring: %w", err)
+	}
+	return writeSecretFile(s.path, data)
+}
+
+// runKeyCommand runs a profile's key_command (e.g. "op read op://vault/toneclone/key")
+// through the shell and returns its trimmed output as the API key
+func runKeyCommand(command string) (string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", fmt.Errorf("key_command is empty")
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("key_command failed: %s", commandError(err, stderr.Bytes()))
+	}
+
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", fmt.Errorf("key_command produced no output")
+	}
+	return key, nil
+}
+
+func commandError(err error, output []byte) string {
+	var exitErr *exec.ExitError
+	if msg := strings.TrimSpace(string(output)); msg != "" && errors.As(err, &exitErr) {
+		return msg
+	}
+	return err.Error()
+}
+
+func writeSecretFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	return fsutil.WriteFileAtomic(path, data, 0600)
+}
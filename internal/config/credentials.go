@@ -0,0 +1,222 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Credential store backends. A key profile with no store keeps its key in
+// the config file in plaintext.
+const (
+	StoreKeyring       = "keyring"
+	StoreEncryptedFile = "encrypted-file"
+	StoreCommand       = "command"
+	StoreFake          = "fake"
+)
+
+// CredentialStores lists the backends that can hold API keys
+var CredentialStores = []string{StoreKeyring, StoreEncryptedFile, StoreCommand, StoreFake}
+
+// ErrCredentialNotFound is returned when a store has no secret for a reference
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore keeps API keys outside the config file
+type CredentialStore interface {
+	// Get returns the secret stored under ref
+	Get(ref string) (string, error)
+	// Set stores secret under ref, replacing any existing value
+	Set(ref, secret string) error
+	// Delete removes the secret stored under ref
+	Delete(ref string) error
+}
+
+// PassphraseFunc supplies the passphrase for the encrypted file store when
+// TONECLONE_PASSPHRASE is not set. The CLI sets it to an interactive prompt.
+var PassphraseFunc func() (string, error)
+
+// OpenCredentialStore returns the named credential store backend
+func OpenCredentialStore(name string) (CredentialStore, error) {
+	switch name {
+	case StoreKeyring:
+		return newKeyringStore()
+	case StoreEncryptedFile:
+		path, err := credentialsFilePath("TONECLONE_CREDENTIALS_FILE", "credentials.enc")
+		if err != nil {
+			return nil, err
+		}
+		return &encryptedFileStore{path: path, passphrase: getPassphrase}, nil
+	case StoreFake:
+		path, err := credentialsFilePath("TONECLONE_FAKE_KEYRING", "fake-keyring.json")
+		if err != nil {
+			return nil, err
+		}
+		return &fakeStore{path: path}, nil
+	case StoreCommand:
+		return nil, fmt.Errorf("the %s store is configured per profile with key_command", StoreCommand)
+	default:
+		return nil, fmt.Errorf("unknown credential store '%s' (use %s)", name, strings.Join(CredentialStores, ", "))
+	}
+}
+
+// ResolveKey returns the API key for a profile, reading it from the
+// profile's credential store if it is not stored inline
+func (k APIKeyConfig) ResolveKey(profile string) (string, error) {
+	switch k.Store {
+	case "":
+		return k.Key, nil
+	case StoreCommand:
+		return runKeyCommand(k.KeyCommand)
+	}
+
+	store, err := OpenCredentialStore(k.Store)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := store.Get(k.ref(profile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read API key for profile '%s' from %s: %w", profile, k.Store, err)
+	}
+	return key, nil
+}
+
+// IsStored reports whether the key lives outside the config file
+func (k APIKeyConfig) IsStored() bool {
+	return k.Store != ""
+}
+
+func (k APIKeyConfig) ref(profile string) string {
+	if k.KeyRef != "" {
+		return k.KeyRef
+	}
+	return profile
+}
+
+// MigrateKey moves a profile's plaintext key into the named credential
+// store, leaving only a reference in the config
+func (c *Config) MigrateKey(name, storeName string) error {
+	keyConfig, exists := c.Keys[name]
+	if !exists {
+		return fmt.Errorf("API key profile '%s' not found", name)
+	}
+	if keyConfig.IsStored() {
+		return fmt.Errorf("API key for profile '%s' is already stored in %s", name, keyConfig.Store)
+	}
+
+	store, err := OpenCredentialStore(storeName)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Set(name, keyConfig.Key); err != nil {
+		return fmt.Errorf("failed to store API key for profile '%s': %w", name, err)
+	}
+	if keyConfig, err = migrateRefreshToken(name, keyConfig, store); err != nil {
+		return err
+	}
+
+	keyConfig.Key = ""
+	keyConfig.Store = storeName
+	keyConfig.KeyRef = name
+	c.Keys[name] = keyConfig
+
+	return nil
+}
+
+// UpdateKey replaces the API key of an existing profile, writing it to the
+// profile's credential store if it has one
+func (c *Config) UpdateKey(name, key string) error {
+	keyConfig, exists := c.Keys[name]
+	if !exists {
+		return fmt.Errorf("API key profile '%s' not found", name)
+	}
+
+	switch keyConfig.Store {
+	case "":
+		keyConfig.Key = key
+		c.Keys[name] = keyConfig
+		return nil
+	case StoreCommand:
+		return fmt.Errorf("profile '%s' reads its key from key_command; update the key in your password manager", name)
+	}
+
+	store, err := OpenCredentialStore(keyConfig.Store)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Set(keyConfig.ref(name), key); err != nil {
+		return fmt.Errorf("failed to store API key for profile '%s': %w", name, err)
+	}
+	return nil
+}
+
+// PlaintextKeys returns the names of saved profiles whose key is stored in
+// the config file
+func (c *Config) PlaintextKeys() []string {
+	var names []string
+	for name, keyConfig := range c.Keys {
+		if name != "environment" && !keyConfig.IsStored() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func deleteStoredKey(name string, keyConfig APIKeyConfig) error {
+	// Secrets behind key_command belong to the password manager
+	if !keyConfig.IsStored() || keyConfig.Store == StoreCommand {
+		return nil
+	}
+
+	store, err := OpenCredentialStore(keyConfig.Store)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Delete(keyConfig.ref(name)); err != nil && !errors.Is(err, ErrCredentialNotFound) {
+		return fmt.Errorf("failed to delete API key for profile '%s' from %s: %w", name, keyConfig.Store, err)
+	}
+	return deleteStoredRefreshToken(name, keyConfig, store)
+}
+
+// promptedPassphrase caches the passphrase so it is asked for once per run
+var promptedPassphrase string
+
+func getPassphrase() (string, error) {
+	if passphrase := os.Getenv("TONECLONE_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	if promptedPassphrase != "" {
+		return promptedPassphrase, nil
+	}
+	if PassphraseFunc == nil {
+		return "", fmt.Errorf("passphrase required: set TONECLONE_PASSPHRASE")
+	}
+
+	passphrase, err := PassphraseFunc()
+	if err != nil {
+		return "", err
+	}
+	promptedPassphrase = passphrase
+	return passphrase, nil
+}
+
+// credentialsFilePath returns the path from envKey, or a file under ~/.toneclone
+func credentialsFilePath(envKey, name string) (string, error) {
+	if path := os.Getenv(envKey); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".toneclone", name), nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateKeyToFakeStore(t *testing.T) {
+	keyring := filepath.Join(t.TempDir(), "keyring.json")
+	t.Setenv("TONECLONE_FAKE_KEYRING", keyring)
+	resetAccountSelection(t)
+
+	cfg := NewConfig()
+	cfg.AddKey("prod", "tc_live_abcdef123456", "https://api.toneclone.ai")
+
+	if got := cfg.PlaintextKeys(); len(got) != 1 || got[0] != "prod" {
+		t.Fatalf("Expected prod to be a plaintext key, got %v", got)
+	}
+
+	if err := cfg.MigrateKey("prod", StoreFake); err != nil {
+		t.Fatalf("Failed to migrate key: %v", err)
+	}
+
+	stored := cfg.Keys["prod"]
+	if stored.Key != "" || stored.Store != StoreFake || stored.KeyRef != "prod" {
+		t.Errorf("Expected only a reference in config, got %+v", stored)
+	}
+	if len(cfg.PlaintextKeys()) != 0 {
+		t.Error("Expected no plaintext keys after migration")
+	}
+
+	// The saved config must not contain the secret
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := cfg.SaveConfig(configPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "tc_live_abcdef123456") {
+		t.Error("Saved config contains the plaintext key")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected config with stored key to be valid: %v", err)
+	}
+
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if keyConfig.Key != "tc_live_abcdef123456" {
+		t.Errorf("Expected key to be resolved from store, got %q", keyConfig.Key)
+	}
+
+	// Updating a stored key writes to the store, not the config
+	if err := cfg.UpdateKey("prod", "tc_live_rotated98765"); err != nil {
+		t.Fatalf("Failed to update key: %v", err)
+	}
+	if cfg.Keys["prod"].Key != "" {
+		t.Error("Expected updated key to stay out of the config")
+	}
+	if keyConfig, _ := cfg.GetCurrentKey(); keyConfig.Key != "tc_live_rotated98765" {
+		t.Errorf("Expected rotated key, got %q", keyConfig.Key)
+	}
+
+	// Removing the profile removes the stored secret
+	if err := cfg.RemoveKey("prod"); err != nil {
+		t.Fatalf("Failed to remove key: %v", err)
+	}
+	store, _ := OpenCredentialStore(StoreFake)
+	if _, err := store.Get("prod"); err != ErrCredentialNotFound {
+		t.Errorf("Expected stored key to be deleted, got %v", err)
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	passphrase := "correct horse"
+	store := &encryptedFileStore{path: path, passphrase: func() (string, error) { return passphrase, nil }}
+
+	if err := store.Set("prod", "tc_live_secret12345"); err != nil {
+		t.Fatalf("Failed to set secret: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "tc_live_secret12345") {
+		t.Error("Credentials file contains the plaintext key")
+	}
+
+	secret, err := store.Get("prod")
+	if err != nil || secret != "tc_live_secret12345" {
+		t.Errorf("Expected stored secret, got %q (%v)", secret, err)
+	}
+
+	if _, err := store.Get("missing"); err != ErrCredentialNotFound {
+		t.Errorf("Expected ErrCredentialNotFound, got %v", err)
+	}
+
+	passphrase = "wrong"
+	if _, err := store.Get("prod"); err == nil {
+		t.Error("Expected wrong passphrase to fail")
+	}
+}
+
+func TestKeyCommand(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires a POSIX shell")
+	}
+
+	keyConfig := APIKeyConfig{Store: StoreCommand, KeyCommand: "echo tc_test_fromcommand", BaseURL: "https://api.toneclone.ai"}
+	key, err := keyConfig.ResolveKey("ci")
+	if err != nil || key != "tc_test_fromcommand" {
+		t.Errorf("Expected key from command, got %q (%v)", key, err)
+	}
+
+	keyConfig.KeyCommand = "exit 3"
+	if _, err := keyConfig.ResolveKey("ci"); err == nil {
+		t.Error("Expected failing key_command to return an error")
+	}
+
+	cfg := NewConfig()
+	cfg.Keys["ci"] = APIKeyConfig{Store: StoreCommand, BaseURL: "https://api.toneclone.ai"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for empty key_command")
+	}
+}
+
+func TestSecurityAddCommand(t *testing.T) {
+	command, err := securityAddCommand("prod", "tc_secret123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if command != "add-generic-password -U -s \"toneclone-cli\" -a \"prod\" -w \"tc_secret123\"\n" {
+		t.Errorf("Unexpected command %q", command)
+	}
+
+	for _, secret := range []string{`tc_"quoted`, `tc_back\slash`, "tc_two\nlines"} {
+		if _, err := securityAddCommand("prod", secret); err == nil {
+			t.Errorf("Expected %q to be rejected", secret)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultOutputs lists the output formats accepted in defaults
+var DefaultOutputs = []string{"text", "json"}
+
+// managedKeyFields are key profile settings owned by the auth commands
+var managedKeyFields = map[string]bool{
+	"key":         true,
+	"store":       true,
+	"key_ref":     true,
+	"key_command": true,
+}
+
+// Validate checks default values
+func (d Defaults) Validate() error {
+	for name, value := range map[string]int{
+		"formality":     d.Formality,
+		"reading_level": d.ReadingLevel,
+		"length":        d.Length,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+
+	if d.Output != "" && !containsString(DefaultOutputs, d.Output) {
+		return fmt.Errorf("invalid output '%s' (use %s)", d.Output, strings.Join(DefaultOutputs, " or "))
+	}
+
+	return nil
+}
+
+// Merge returns d with every value set in override replacing its own
+func (d Defaults) Merge(override Defaults) Defaults {
+	if override.Persona != "" {
+		d.Persona = override.Persona
+	}
+	if len(override.Profiles) > 0 {
+		d.Profiles = override.Profiles
+	}
+	if override.Formality != 0 {
+		d.Formality = override.Formality
+	}
+	if override.ReadingLevel != 0 {
+		d.ReadingLevel = override.ReadingLevel
+	}
+	if override.Length != 0 {
+		d.Length = override.Length
+	}
+	if override.Model != "" {
+		d.Model = override.Model
+	}
+	if override.Output != "" {
+		d.Output = override.Output
+	}
+	return d
+}
+
+// CurrentDefaults returns the global defaults overridden by those of the
+// current key profile. Key profiles live in the user file, so defaults set
+// by a project file, the environment or a flag still win over them.
+func (c *Config) CurrentDefaults() Defaults {
+	defaults := c.Defaults
+	if keyConfig, exists := c.Keys[c.GetCurrentKeyName()]; exists {
+		defaults = defaults.Merge(keyConfig.Defaults).Merge(c.layeredDefaults())
+	}
+	return defaults
+}
+
+// layeredDefaults returns the global defaults set by layers above the user file
+func (c *Config) layeredDefaults() Defaults {
+	var layered Defaults
+	global := reflect.ValueOf(c.Defaults)
+	result := reflect.ValueOf(&layered).Elem()
+	for i := 0; i < global.NumField(); i++ {
+		name := strings.Split(global.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		switch c.Origin("defaults." + name).Layer {
+		case LayerProject, LayerEnv, LayerFlag:
+			result.Field(i).Set(global.Field(i))
+		}
+	}
+	return layered
+}
+
+// GetValue returns the value of a setting by its dotted path, such as
+// "defaults.persona" or "keys.work.defaults.formality"
+func (c *Config) GetValue(path string) (interface{}, error) {
+	if _, err := settingType(path); err != nil {
+		return nil, err
+	}
+
+	values, err := toMap(c)
+	if err != nil {
+		return nil, err
+	}
+
+	value := lookupPath(values, path)
+	if value == nil {
+		return nil, fmt.Errorf("'%s' is not set", path)
+	}
+	return value, nil
+}
+
+// SetValue parses and sets a setting by its dotted path. Lists are given
+// comma-separated.
+func (c *Config) SetValue(path, raw string) error {
+	if err := c.checkEditable(path); err != nil {
+		return err
+	}
+
+	fieldType, err := settingType(path)
+	if err != nil {
+		return err
+	}
+
+	value, err := parseSettingValue(fieldType, raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for '%s': %w", path, err)
+	}
+
+	return c.editValues(path, func(values map[string]interface{}) {
+		setPath(values, path, value)
+	})
+}
+
+// UnsetValue removes a setting, or a whole section, by its dotted path
+func (c *Config) UnsetValue(path string) error {
+	if err := c.checkEditable(path); err != nil {
+		return err
+	}
+
+	if _, err := resolveSetting(path); err != nil {
+		return err
+	}
+
+	return c.editValues(path, func(values map[string]interface{}) {
+		deletePath(values, path)
+	})
+}
+
+// Origin returns the layer that set a setting
+func (c *Config) Origin(path string) Origin {
+	if c.layers != nil {
+		if origin, ok := c.layers.origins[path]; ok {
+			return origin
+		}
+	}
+	return Origin{Layer: LayerDefault}
+}
+
+// checkEditable rejects settings that config set and unset must not touch
+func (c *Config) checkEditable(path string) error {
+	parts := strings.Split(path, ".")
+	if parts[0] != "keys" {
+		return nil
+	}
+	if len(parts) < 3 {
+		return fmt.Errorf("key profiles are managed by 'toneclone auth'")
+	}
+
+	if _, exists := c.Keys[parts[1]]; !exists {
+		return fmt.Errorf("API key profile '%s' not found", parts[1])
+	}
+	if managedKeyFields[parts[2]] {
+		return fmt.Errorf("'%s' is managed by 'toneclone auth'", path)
+	}
+	return nil
+}
+
+// editValues applies an edit to the generic form of the config and decodes
+// the result back into c
+func (c *Config) editValues(path string, edit func(values map[string]interface{})) error {
+	values, err := toMap(c)
+	if err != nil {
+		return err
+	}
+
+	edit(values)
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	updated := Config{}
+	if err := yaml.Unmarshal(data, &updated); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if updated.Keys == nil {
+		updated.Keys = make(map[string]APIKeyConfig)
+	}
+
+	// The edited value now belongs to the user file
+	updated.layers = c.layers
+	if updated.layers != nil {
+		clearInherited(updated.layers, path)
+	}
+
+	*c = updated
+	return nil
+}
+
+// clearInherited forgets values from other layers at or below path
+func clearInherited(state *layerState, path string) {
+	for p := range state.inherited {
+		if p == path || strings.HasPrefix(p, path+".") {
+			delete(state.inherited, p)
+		}
+	}
+}
+
+// settingType returns the type of the setting at a dotted path
+func settingType(path string) (reflect.Type, error) {
+	t, err := resolveSetting(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Kind() == reflect.Struct || t.Kind() == reflect.Map {
+		return nil, fmt.Errorf("'%s' is a section, not a setting", path)
+	}
+	return t, nil
+}
+
+// resolveSetting returns the type at a dotted path, following the yaml
+// names of Config fields
+func resolveSetting(path string) (reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+
+	for _, part := range strings.Split(path, ".") {
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := yamlField(t, part)
+			if !ok {
+				return nil, fmt.Errorf("unknown setting '%s'", path)
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("unknown setting '%s'", path)
+		}
+	}
+	return t, nil
+}
+
+// yamlField finds the exported struct field with the given yaml name
+func yamlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if tag := strings.Split(field.Tag.Get("yaml"), ",")[0]; tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// parseSettingValue converts a command line value to the setting's type
+func parseSettingValue(t reflect.Type, raw string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Int:
+		return strconv.Atoi(strings.TrimSpace(raw))
+	case reflect.Bool:
+		return strconv.ParseBool(strings.TrimSpace(raw))
+	case reflect.Slice:
+		var items []interface{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unsupported setting type %s", t)
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCurrentDefaultsPerKeyProfile(t *testing.T) {
+	resetAccountSelection(t)
+
+	cfg := NewConfig()
+	cfg.AddKey("work", "tc_test_workkey123", "")
+	cfg.AddKey("home", "tc_test_homekey123", "")
+	cfg.Defaults = Defaults{Persona: "Casual", Formality: 3, Length: 5}
+
+	work := cfg.Keys["work"]
+	work.Defaults = Defaults{Persona: "Professional", Formality: 8}
+	cfg.Keys["work"] = work
+
+	cfg.DefaultKey = "work"
+	defaults := cfg.CurrentDefaults()
+	if defaults.Persona != "Professional" || defaults.Formality != 8 || defaults.Length != 5 {
+		t.Errorf("Expected key profile defaults over global ones, got %+v", defaults)
+	}
+
+	cfg.DefaultKey = "home"
+	if defaults := cfg.CurrentDefaults(); defaults.Persona != "Casual" || defaults.Formality != 3 {
+		t.Errorf("Expected global defaults, got %+v", defaults)
+	}
+}
+
+func TestCurrentDefaultsProjectOverKeyProfile(t *testing.T) {
+	resetAccountSelection(t)
+	user := `default_key: main
+keys:
+  main:
+    key: tc_test_mainkey123
+    base_url: https://api.toneclone.ai
+    defaults:
+      persona: Casual
+      formality: 3
+defaults:
+  length: 4
+`
+	userPath := setupLayers(t, "", user, "defaults:\n  persona: Project\n")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	defaults := cfg.CurrentDefaults()
+	if defaults.Persona != "Project" {
+		t.Errorf("Expected the project persona over the key profile one, got %q", defaults.Persona)
+	}
+	if defaults.Formality != 3 || defaults.Length != 4 {
+		t.Errorf("Expected key profile and user defaults to fill the rest, got %+v", defaults)
+	}
+}
+
+func TestSetGetUnsetValue(t *testing.T) {
+	cfg := NewConfig()
+	cfg.AddKey("work", "tc_test_workkey123", "")
+
+	if err := cfg.SetValue("defaults.profiles", "Email, Concise"); err != nil {
+		t.Fatalf("Failed to set profiles: %v", err)
+	}
+	if err := cfg.SetValue("keys.work.defaults.formality", "7"); err != nil {
+		t.Fatalf("Failed to set formality: %v", err)
+	}
+
+	if len(cfg.Defaults.Profiles) != 2 || cfg.Defaults.Profiles[1] != "Concise" {
+		t.Errorf("Expected two profiles, got %v", cfg.Defaults.Profiles)
+	}
+	if cfg.Keys["work"].Defaults.Formality != 7 || cfg.Keys["work"].Key != "tc_test_workkey123" {
+		t.Errorf("Unexpected work key after set: %+v", cfg.Keys["work"])
+	}
+
+	value, err := cfg.GetValue("keys.work.defaults.formality")
+	if err != nil || value != 7 {
+		t.Errorf("Expected 7, got %v (%v)", value, err)
+	}
+
+	if err := cfg.UnsetValue("keys.work.defaults"); err != nil {
+		t.Fatalf("Failed to unset: %v", err)
+	}
+	if _, err := cfg.GetValue("keys.work.defaults.formality"); err == nil {
+		t.Error("Expected formality to be unset")
+	}
+}
+
+func TestSetValueRejectsInvalidPaths(t *testing.T) {
+	cfg := NewConfig()
+	cfg.AddKey("work", "tc_test_workkey123", "")
+
+	tests := []struct {
+		path  string
+		value string
+		want  string
+	}{
+		{"defaults.tone", "warm", "unknown setting"},
+		{"defaults", "x", "is a section"},
+		{"defaults.formality", "high", "invalid value"},
+		{"keys.work.key", "tc_test_other12345", "managed by 'toneclone auth'"},
+		{"keys.missing.base_url", "https://example.com", "not found"},
+	}
+
+	for _, tt := range tests {
+		err := cfg.SetValue(tt.path, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("SetValue(%s): expected error containing %q, got %v", tt.path, tt.want, err)
+		}
+	}
+
+	if err := cfg.UnsetValue("keys.work"); err == nil {
+		t.Error("Expected unsetting a key profile to fail")
+	}
+}
+
+func TestSetValueOverridesProjectValue(t *testing.T) {
+	userPath := setupLayers(t, "", layersUserConfig, "defaults:\n  persona: Casual\n")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Setting the same value as the project file still writes it to the user file
+	if err := cfg.SetValue("defaults.persona", "Casual"); err != nil {
+		t.Fatalf("Failed to set persona: %v", err)
+	}
+	if err := cfg.SaveConfig(userPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	data, err := os.ReadFile(userPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "persona: Casual") {
+		t.Errorf("Expected persona in user file:\n%s", data)
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Defaults.Output = "yaml"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected invalid output to fail validation")
+	}
+
+	cfg.Defaults = Defaults{Formality: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected negative formality to fail validation")
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// Sources an environment can be selected from, highest precedence first
+const (
+	EnvSourceFlag    = "--env"
+	EnvSourceEnv     = "TONECLONE_ENV"
+	EnvSourceKey     = "key profile"
+	EnvSourceDefault = "default_env"
+)
+
+// EnvFlag holds the environment selected with the global --env flag. The
+// CLI fills it in before loading the configuration.
+var EnvFlag string
+
+// EnvironmentConfig is a named API deployment, such as prod, staging or a
+// local mock server, that key profiles can point at
+type EnvironmentConfig struct {
+	BaseURL string        `yaml:"base_url" json:"base_url"`
+	Timeout int           `yaml:"timeout,omitempty" json:"timeout,omitempty"` // seconds
+	Network NetworkConfig `yaml:"network,omitempty" json:"network,omitempty"` // overrides the global network settings
+}
+
+// EnvironmentSelection is the environment chosen for a key and what chose it
+type EnvironmentSelection struct {
+	Name   string
+	Source string
+}
+
+// ResolveEnvironment returns the environment to use with a key profile.
+// --env wins over TONECLONE_ENV, then the key profile's own env, then
+// default_env, which only applies to keys with no base URL of their own.
+// An empty name means the key's base URL is used as is.
+func (c *Config) ResolveEnvironment(keyConfig APIKeyConfig) EnvironmentSelection {
+	candidates := []EnvironmentSelection{
+		{EnvFlag, EnvSourceFlag},
+		{os.Getenv("TONECLONE_ENV"), EnvSourceEnv},
+		{keyConfig.Env, EnvSourceKey},
+	}
+	if keyConfig.BaseURL == "" {
+		candidates = append(candidates, EnvironmentSelection{c.DefaultEnv, EnvSourceDefault})
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Name != "" {
+			return candidate
+		}
+	}
+	return EnvironmentSelection{}
+}
+
+// AddEnvironment adds or replaces a named environment
+func (c *Config) AddEnvironment(name string, env EnvironmentConfig) {
+	if c.Environments == nil {
+		c.Environments = make(map[string]EnvironmentConfig)
+	}
+	c.Environments[name] = env
+}
+
+// SetDefaultEnvironment sets the environment used by key profiles that
+// name neither an environment nor a base URL
+func (c *Config) SetDefaultEnvironment(name string) error {
+	if _, exists := c.Environments[name]; !exists {
+		return fmt.Errorf("environment '%s' not found", name)
+	}
+	c.DefaultEnv = name
+	return nil
+}
+
+// BindEnvironment makes a key profile use a named environment in place of
+// its own base URL
+func (c *Config) BindEnvironment(keyName, envName string) error {
+	keyConfig, exists := c.Keys[keyName]
+	if !exists {
+		return fmt.Errorf("API key profile '%s' not found", keyName)
+	}
+	if _, exists := c.Environments[envName]; !exists {
+		return fmt.Errorf("environment '%s' not found", envName)
+	}
+
+	keyConfig.Env = envName
+	keyConfig.BaseURL = ""
+	c.Keys[keyName] = keyConfig
+	return nil
+}
+
+// ApplyEnvironment points a key profile at its selected environment and
+// fills in the network settings that apply to it
+func (c *Config) ApplyEnvironment(keyConfig APIKeyConfig) (APIKeyConfig, error) {
+	selection := c.ResolveEnvironment(keyConfig)
+	keyConfig.Network = c.NetworkFor(selection.Name)
+	if selection.Name == "" {
+		return keyConfig, nil
+	}
+
+	env, exists := c.Environments[selection.Name]
+	if !exists {
+		if selection.Source == EnvSourceDefault {
+			return keyConfig, fmt.Errorf("environment '%s' not found", selection.Name)
+		}
+		return keyConfig, fmt.Errorf("environment '%s' (from %s) not found", selection.Name, selection.Source)
+	}
+
+	keyConfig.Env = selection.Name
+	keyConfig.BaseURL = env.BaseURL
+	if env.Timeout > 0 {
+		keyConfig.Timeout = env.Timeout
+	}
+	return keyConfig, nil
+}
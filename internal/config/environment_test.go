@@ -0,0 +1,195 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// resetEnvironmentSelection clears the --env flag and TONECLONE_ENV
+func resetEnvironmentSelection(t *testing.T) {
+	t.Helper()
+
+	EnvFlag = ""
+	t.Cleanup(func() { EnvFlag = "" })
+	t.Setenv("TONECLONE_ENV", "")
+}
+
+func environmentConfig() *Config {
+	cfg := NewConfig()
+	cfg.AddEnvironment("prod", EnvironmentConfig{BaseURL: "https://api.toneclone.ai"})
+	cfg.AddEnvironment("staging", EnvironmentConfig{BaseURL: "https://staging.api.toneclone.ai", Timeout: 60})
+	cfg.AddEnvironment("local", EnvironmentConfig{BaseURL: "http://localhost:8080", Timeout: 5})
+	cfg.DefaultEnv = "prod"
+	cfg.Keys["main"] = APIKeyConfig{Key: "tc_test_mainkey123", Env: "staging"}
+	cfg.DefaultKey = "main"
+	return cfg
+}
+
+func TestResolveEnvironmentPrecedence(t *testing.T) {
+	cfg := environmentConfig()
+
+	tests := []struct {
+		name   string
+		flag   string
+		envVar string
+		keyEnv string
+		want   EnvironmentSelection
+	}{
+		{name: "default env", want: EnvironmentSelection{"prod", EnvSourceDefault}},
+		{name: "key env", keyEnv: "staging", want: EnvironmentSelection{"staging", EnvSourceKey}},
+		{name: "env var", envVar: "local", keyEnv: "staging", want: EnvironmentSelection{"local", EnvSourceEnv}},
+		{name: "flag", flag: "prod", envVar: "local", keyEnv: "staging", want: EnvironmentSelection{"prod", EnvSourceFlag}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetEnvironmentSelection(t)
+			EnvFlag = tt.flag
+			t.Setenv("TONECLONE_ENV", tt.envVar)
+
+			got := cfg.ResolveEnvironment(APIKeyConfig{Env: tt.keyEnv})
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetCurrentKeyAppliesEnvironment(t *testing.T) {
+	resetAccountSelection(t)
+	resetEnvironmentSelection(t)
+	cfg := environmentConfig()
+
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if keyConfig.BaseURL != "https://staging.api.toneclone.ai" || keyConfig.Timeout != 60 {
+		t.Errorf("Expected staging settings, got %+v", keyConfig)
+	}
+
+	// --env overrides the key's own environment
+	EnvFlag = "local"
+	keyConfig, err = cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if keyConfig.BaseURL != "http://localhost:8080" || keyConfig.Env != "local" || keyConfig.Timeout != 5 {
+		t.Errorf("Expected local settings, got %+v", keyConfig)
+	}
+
+	// An unknown environment names where it came from
+	EnvFlag = "qa"
+	if _, err := cfg.GetCurrentKey(); err == nil || !strings.Contains(err.Error(), "'qa' (from --env)") {
+		t.Errorf("Expected unknown environment error, got %v", err)
+	}
+}
+
+func TestDefaultEnvironmentKeepsExplicitBaseURL(t *testing.T) {
+	resetAccountSelection(t)
+	resetEnvironmentSelection(t)
+	user := layersUserConfig + `environments:
+  local:
+    base_url: http://localhost:8080
+`
+	userPath := setupLayers(t, "", user, "default_env: local\n")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.DefaultEnv != "" || len(cfg.Warnings()) != 1 {
+		t.Errorf("Expected project default_env to be ignored with a warning, got %q %v", cfg.DefaultEnv, cfg.Warnings())
+	}
+
+	// Set in the user file, the default still leaves explicit base URLs alone
+	cfg.DefaultEnv = "local"
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if keyConfig.BaseURL != "https://api.toneclone.ai" || keyConfig.Env != "" {
+		t.Errorf("Expected the key's own base URL, got %+v", keyConfig)
+	}
+
+	// Keys with neither an env nor a base URL use it
+	keyConfig, err = cfg.ApplyEnvironment(APIKeyConfig{Key: "tc_test_mainkey123"})
+	if err != nil || keyConfig.BaseURL != "http://localhost:8080" {
+		t.Errorf("Expected the default environment, got %+v (%v)", keyConfig, err)
+	}
+}
+
+func TestApplyEnvironmentKeepsKeyTimeout(t *testing.T) {
+	resetEnvironmentSelection(t)
+	cfg := environmentConfig()
+
+	keyConfig, err := cfg.ApplyEnvironment(APIKeyConfig{Key: "tc_test_mainkey123", Timeout: 90})
+	if err != nil {
+		t.Fatalf("Failed to apply environment: %v", err)
+	}
+	if keyConfig.BaseURL != "https://api.toneclone.ai" || keyConfig.Timeout != 90 {
+		t.Errorf("Expected prod base URL with the key's timeout, got %+v", keyConfig)
+	}
+}
+
+func TestBindEnvironment(t *testing.T) {
+	cfg := environmentConfig()
+	cfg.Keys["other"] = APIKeyConfig{Key: "tc_test_otherkey12", BaseURL: "https://example.com"}
+
+	if err := cfg.BindEnvironment("other", "local"); err != nil {
+		t.Fatalf("Failed to bind environment: %v", err)
+	}
+	if got := cfg.Keys["other"]; got.Env != "local" || got.BaseURL != "" {
+		t.Errorf("Expected key bound to local without its own base URL, got %+v", got)
+	}
+
+	if err := cfg.BindEnvironment("other", "qa"); err == nil {
+		t.Error("Expected error binding unknown environment")
+	}
+	if err := cfg.BindEnvironment("missing", "local"); err == nil {
+		t.Error("Expected error binding unknown key")
+	}
+}
+
+func TestValidateEnvironments(t *testing.T) {
+	if err := environmentConfig().Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{name: "missing default env", modify: func(c *Config) { c.DefaultEnv = "qa" }},
+		{name: "missing key env", modify: func(c *Config) { c.Keys["main"] = APIKeyConfig{Key: "tc_test_mainkey123", Env: "qa"} }},
+		{name: "env without base url", modify: func(c *Config) { c.AddEnvironment("qa", EnvironmentConfig{}) }},
+		{name: "negative timeout", modify: func(c *Config) {
+			c.AddEnvironment("qa", EnvironmentConfig{BaseURL: "https://qa", Timeout: -1})
+		}},
+		{name: "key without env or base url", modify: func(c *Config) {
+			c.DefaultEnv = ""
+			c.Keys["main"] = APIKeyConfig{Key: "tc_test_mainkey123"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := environmentConfig()
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestSetDefaultEnvironment(t *testing.T) {
+	cfg := environmentConfig()
+
+	if err := cfg.SetDefaultEnvironment("local"); err != nil || cfg.DefaultEnv != "local" {
+		t.Errorf("Expected default env local, got %q (%v)", cfg.DefaultEnv, err)
+	}
+	if err := cfg.SetDefaultEnvironment("qa"); err == nil {
+		t.Error("Expected error for unknown environment")
+	}
+}
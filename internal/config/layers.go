@@ -0,0 +1,499 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration layers, lowest precedence first
+const (
+	LayerDefault = "default"
+	LayerSystem  = "system"
+	LayerUser    = "user"
+	LayerProject = "project"
+	LayerEnv     = "env"
+	LayerFlag    = "flag"
+)
+
+// ProjectConfigFile is the name of per-directory config files
+const ProjectConfigFile = ".toneclone.yaml"
+
+// projectKeys are the only top-level keys a project file may set. Project
+// files are often committed to repositories, so they must never carry
+// secrets or anything that decides where a key is sent.
+var projectKeys = map[string]bool{
+	"version":     true,
+	"default_key": true,
+	"defaults":    true,
+}
+
+// FlagOverrides holds config values set by command line flags. The CLI
+// fills it in before loading the configuration.
+var FlagOverrides = map[string]interface{}{}
+
+// Origin identifies the layer that set a configuration value
+type Origin struct {
+	Layer  string `json:"layer"`
+	Source string `json:"source,omitempty"` // file path, env var or flag
+}
+
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer
+	}
+	return fmt.Sprintf("%s (%s)", o.Layer, o.Source)
+}
+
+// Setting is a single configuration value and where it came from
+type Setting struct {
+	Path   string      `json:"path"`
+	Value  interface{} `json:"value"`
+	Origin Origin      `json:"origin"`
+}
+
+// configLayer is one source of configuration values
+type configLayer struct {
+	origin Origin
+	values map[string]interface{}
+}
+
+// layerState remembers how a loaded config was assembled so that saving
+// writes back only the user's own file
+type layerState struct {
+	origins   map[string]Origin
+	inherited map[string]interface{} // winning values from layers other than user and default
+	user      map[string]interface{}
+	files     []Origin
+	warnings  []string
+}
+
+// SystemConfigPath returns the path of the machine-wide config file
+func SystemConfigPath() string {
+	if path := os.Getenv("TONECLONE_SYSTEM_CONFIG"); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "toneclone", "config.yaml")
+	}
+	return "/etc/toneclone/config.yaml"
+}
+
+// FindProjectConfig returns the nearest project config file at or above
+// dir, skipping the user's own config file
+func FindProjectConfig(dir, userPath string) string {
+	for {
+		candidate := filepath.Join(dir, ProjectConfigFile)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && !samePath(candidate, userPath) {
+			return candidate
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadLayers reads every configuration source in precedence order
+func loadLayers(userPath string) ([]configLayer, []string, error) {
+	var layers []configLayer
+	var warnings []string
+
+	defaults, err := toMap(NewConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	layers = append(layers, configLayer{origin: Origin{Layer: LayerDefault}, values: defaults})
+
+	// System file
+	systemPath := SystemConfigPath()
+	if layer, layerWarnings, err := readLayer(Origin{Layer: LayerSystem, Source: systemPath}); err != nil {
+		return nil, nil, err
+	} else if layer != nil {
+		layers = append(layers, *layer)
+		warnings = append(warnings, layerWarnings...)
+	}
+
+	// User file
+	if layer, layerWarnings, err := readLayer(Origin{Layer: LayerUser, Source: userPath}); err != nil {
+		return nil, nil, err
+	} else if layer != nil {
+		layers = append(layers, *layer)
+		warnings = append(warnings, layerWarnings...)
+	}
+
+	// Nearest project file
+	if cwd, err := os.Getwd(); err == nil {
+		if projectPath := FindProjectConfig(cwd, userPath); projectPath != "" {
+			layer, layerWarnings, err := readLayer(Origin{Layer: LayerProject, Source: projectPath})
+			if err != nil {
+				return nil, nil, err
+			}
+			if layer != nil {
+				layers = append(layers, *layer)
+				warnings = append(warnings, layerWarnings...)
+			}
+		}
+	}
+
+	return layers, warnings, nil
+}
+
+// readLayer reads a config file, upgrading it to the current schema. The
+// user's own file is migrated in place unless only its version key is
+// behind; others only in memory. Project files are restricted to
+// projectKeys. A missing file yields no layer.
+func readLayer(origin Origin) (*configLayer, []string, error) {
+	values, err := readLayerFile(origin.Source)
+	if err != nil || values == nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	if origin.Layer == LayerProject {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if !projectKeys[key] {
+				warnings = append(warnings, fmt.Sprintf("ignoring '%s' in project config %s: project files may only set default_key and defaults", key, origin.Source))
+				delete(values, key)
+			}
+		}
+	}
+
+	for _, unknown := range UnknownKeys(values) {
+		warnings = append(warnings, fmt.Sprintf("%s in %s", unknown, origin.Source))
+	}
+
+	result, err := migrateValues(values)
+	if err != nil {
+		if origin.Layer == LayerUser {
+			return nil, nil, fmt.Errorf("failed to load %s: %w", origin.Source, err)
+		}
+		// Read what we can from files shared with newer CLIs
+		warnings = append(warnings, fmt.Sprintf("%s: %v", origin.Source, err))
+	} else if result.Changed() && !result.VersionOnly && origin.Layer == LayerUser {
+		if _, err := MigrateFile(origin.Source, false); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not upgrade %s to config version %d: %v", origin.Source, CurrentVersion, err))
+		}
+	}
+
+	return &configLayer{origin: origin, values: values}, warnings, nil
+}
+
+// envLayer builds the layer for environment variables. It needs the values
+// merged so far to fill in defaults.
+func envLayer(merged map[string]interface{}) *configLayer {
+	apiKey := os.Getenv("TONECLONE_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+
+	defaultBaseURL, _ := merged["default_base_url"].(string)
+	baseURL := getEnvOrDefault("TONECLONE_BASE_URL", defaultBaseURL)
+
+	values := map[string]interface{}{
+		"keys": map[string]interface{}{
+			"environment": map[string]interface{}{
+				"key":      apiKey,
+				"base_url": baseURL,
+			},
+		},
+	}
+
+	// Use the environment key if no default is configured
+	if name, _ := merged["default_key"].(string); name == "" {
+		values["default_key"] = "environment"
+	}
+
+	return &configLayer{origin: Origin{Layer: LayerEnv, Source: "TONECLONE_API_KEY"}, values: values}
+}
+
+// loadLayered merges all layers into a config and records where each value came from
+func loadLayered(userPath string) (*Config, error) {
+	layers, warnings, err := loadLayers(userPath)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]interface{})
+	state := &layerState{
+		origins:  make(map[string]Origin),
+		warnings: warnings,
+		user:     make(map[string]interface{}),
+	}
+
+	for _, layer := range layers {
+		mergeValues(merged, layer.values, "", layer.origin, state.origins)
+		if layer.origin.Layer == LayerUser {
+			state.user = layer.values
+		}
+		if layer.origin.Source != "" {
+			state.files = append(state.files, layer.origin)
+		}
+	}
+
+	if env := envLayer(merged); env != nil {
+		mergeValues(merged, env.values, "", env.origin, state.origins)
+	}
+
+	if len(FlagOverrides) > 0 {
+		mergeValues(merged, FlagOverrides, "", Origin{Layer: LayerFlag}, state.origins)
+	}
+
+	// Remember values inherited from other layers so SaveConfig can leave them out
+	state.inherited = make(map[string]interface{})
+	for path, origin := range state.origins {
+		if origin.Layer != LayerUser && origin.Layer != LayerDefault {
+			state.inherited[path] = lookupPath(merged, path)
+		}
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if config.Keys == nil {
+		config.Keys = make(map[string]APIKeyConfig)
+	}
+	config.layers = state
+
+	return config, nil
+}
+
+// Settings returns every configuration value with its origin, sorted by path
+func (c *Config) Settings() ([]Setting, error) {
+	values, err := toMap(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []Setting
+	walkValues(values, "", func(path string, value interface{}) {
+		origin := Origin{Layer: LayerDefault}
+		if c.layers != nil {
+			if o, ok := c.layers.origins[path]; ok {
+				origin = o
+			}
+		}
+		settings = append(settings, Setting{Path: path, Value: value, Origin: origin})
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+	return settings, nil
+}
+
+// Files returns the config files that were loaded, lowest precedence first
+func (c *Config) Files() []Origin {
+	if c.layers == nil {
+		return nil
+	}
+	return c.layers.files
+}
+
+// Warnings returns problems found while loading the configuration
+func (c *Config) Warnings() []string {
+	if c.layers == nil {
+		return nil
+	}
+	return c.layers.warnings
+}
+
+// userValues returns the config as it should be written to the user file:
+// values inherited from other layers are dropped unless they were changed
+func (c *Config) userValues() (map[string]interface{}, error) {
+	values, err := toMap(c)
+	if err != nil {
+		return nil, err
+	}
+	if c.layers == nil {
+		return values, nil
+	}
+
+	for path, inherited := range c.layers.inherited {
+		current := lookupPath(values, path)
+		if !reflect.DeepEqual(current, inherited) {
+			continue // changed since loading, so it belongs to the user now
+		}
+		if userValue := lookupPath(c.layers.user, path); userValue != nil {
+			setPath(values, path, userValue)
+		} else {
+			deletePath(values, path)
+		}
+	}
+
+	return values, nil
+}
+
+// mergeValues deep-merges src into dst, recording the origin of each leaf
+func mergeValues(dst, src map[string]interface{}, prefix string, origin Origin, origins map[string]Origin) {
+	for key, value := range src {
+		path := joinPath(prefix, key)
+
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			dstMap, ok := dst[key].(map[string]interface{})
+			if !ok {
+				clearOrigins(origins, path)
+				dstMap = make(map[string]interface{})
+				dst[key] = dstMap
+			}
+			mergeValues(dstMap, srcMap, path, origin, origins)
+			continue
+		}
+
+		// A leaf replaces anything nested below it
+		clearOrigins(origins, path)
+		dst[key] = value
+		origins[path] = origin
+	}
+}
+
+func clearOrigins(origins map[string]Origin, path string) {
+	delete(origins, path)
+	for p := range origins {
+		if strings.HasPrefix(p, path+".") {
+			delete(origins, p)
+		}
+	}
+}
+
+func walkValues(values map[string]interface{}, prefix string, fn func(path string, value interface{})) {
+	for key, value := range values {
+		path := joinPath(prefix, key)
+		if nested, ok := value.(map[string]interface{}); ok {
+			walkValues(nested, path, fn)
+			continue
+		}
+		fn(path, value)
+	}
+}
+
+func lookupPath(values map[string]interface{}, path string) interface{} {
+	var current interface{} = values
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+func setPath(values map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	current := values
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+// deletePath removes a value and any maps left empty by its removal
+func deletePath(values map[string]interface{}, path string) {
+	parts := strings.Split(path, ".")
+	if len(parts) == 1 {
+		delete(values, path)
+		return
+	}
+
+	child, ok := values[parts[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deletePath(child, strings.Join(parts[1:], "."))
+	if len(child) == 0 {
+		delete(values, parts[0])
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// readLayerFile reads a YAML file into a map. A missing file yields nil.
+func readLayerFile(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Unreadable files are skipped, as before layering
+		return nil, nil
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// toMap converts a value to the generic map form used for merging
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return values, nil
+}
+
+func samePath(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	if absA == absB {
+		return true
+	}
+
+	infoA, errA := os.Stat(absA)
+	infoB, errB := os.Stat(absB)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// userConfigPath returns the user config file path in use
+func userConfigPath() string {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+	path, _ := GetConfigPath()
+	return path
+}
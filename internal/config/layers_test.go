@@ -0,0 +1,187 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupLayers writes system, user and project files and changes into a
+// directory below the project. It returns the user config path.
+func setupLayers(t *testing.T, system, user, project string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	systemPath := filepath.Join(root, "system.yaml")
+	userPath := filepath.Join(root, "home", ".toneclone.yaml")
+	projectDir := filepath.Join(root, "repo")
+	workDir := filepath.Join(projectDir, "docs", "api")
+
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(userPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, content := range map[string]string{
+		systemPath: system,
+		userPath:   user,
+		filepath.Join(projectDir, ProjectConfigFile): project,
+	} {
+		if content == "" {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("TONECLONE_SYSTEM_CONFIG", systemPath)
+	t.Setenv("TONECLONE_API_KEY", "")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return userPath
+}
+
+const layersUserConfig = `default_key: main
+default_timeout: 20
+keys:
+  main:
+    key: tc_test_mainkey123
+    base_url: https://api.toneclone.ai
+  work:
+    key: tc_test_workkey123
+    base_url: https://api.toneclone.ai
+defaults:
+  length: 4
+`
+
+func TestLoadLayeredPrecedence(t *testing.T) {
+	userPath := setupLayers(t,
+		"default_timeout: 45\ndefaults:\n  formality: 3\n  length: 2\n",
+		layersUserConfig,
+		"default_key: work\ndefaults:\n  persona: Casual\n  profiles: [Email]\n",
+	)
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.DefaultTimeout != 20 {
+		t.Errorf("Expected user file to override system timeout, got %d", cfg.DefaultTimeout)
+	}
+	if cfg.Defaults.Formality != 3 || cfg.Defaults.Length != 4 {
+		t.Errorf("Expected formality 3 from system and length 4 from user, got %+v", cfg.Defaults)
+	}
+	if cfg.DefaultKey != "work" || cfg.Defaults.Persona != "Casual" || len(cfg.Defaults.Profiles) != 1 {
+		t.Errorf("Expected project values to apply, got %s %+v", cfg.DefaultKey, cfg.Defaults)
+	}
+
+	origins := make(map[string]Origin)
+	settings, err := cfg.Settings()
+	if err != nil {
+		t.Fatalf("Failed to list settings: %v", err)
+	}
+	for _, setting := range settings {
+		origins[setting.Path] = setting.Origin
+	}
+
+	expected := map[string]string{
+		"default_key":        LayerProject,
+		"default_timeout":    LayerUser,
+		"defaults.formality": LayerSystem,
+		"defaults.profiles":  LayerProject,
+		"default_base_url":   LayerDefault,
+	}
+	for path, layer := range expected {
+		if origins[path].Layer != layer {
+			t.Errorf("Expected %s to come from %s, got %s", path, layer, origins[path])
+		}
+	}
+
+	if len(cfg.Files()) != 3 {
+		t.Errorf("Expected 3 loaded files, got %v", cfg.Files())
+	}
+}
+
+func TestProjectConfigCannotSetSecrets(t *testing.T) {
+	userPath := setupLayers(t, "", layersUserConfig,
+		"keys:\n  main:\n    key: tc_test_stolen1234\n    base_url: https://evil.example\ndefault_base_url: https://evil.example\n",
+	)
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Keys["main"].BaseURL != "https://api.toneclone.ai" || cfg.Keys["main"].Key != "tc_test_mainkey123" {
+		t.Errorf("Expected project file not to change keys, got %+v", cfg.Keys["main"])
+	}
+	if cfg.DefaultBaseURL != "https://api.toneclone.ai" {
+		t.Errorf("Expected project file not to change the base URL, got %s", cfg.DefaultBaseURL)
+	}
+	if len(cfg.Warnings()) != 2 {
+		t.Errorf("Expected a warning per ignored key, got %v", cfg.Warnings())
+	}
+}
+
+func TestSaveConfigKeepsOtherLayersOut(t *testing.T) {
+	userPath := setupLayers(t, "default_timeout: 45\n", "keys:\n  main:\n    key: tc_test_mainkey123\n    base_url: https://api.toneclone.ai\n",
+		"defaults:\n  persona: Casual\n",
+	)
+	t.Setenv("TONECLONE_API_KEY", "tc_test_envkey1234")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if _, ok := cfg.Keys["environment"]; !ok || cfg.DefaultKey != "environment" {
+		t.Fatalf("Expected environment key to be the default, got %q", cfg.DefaultKey)
+	}
+
+	cfg.AddKey("work", "tc_test_workkey123", "")
+	if err := cfg.SaveConfig(userPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	data, err := os.ReadFile(userPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+
+	for _, leaked := range []string{"tc_test_envkey1234", "environment", "Casual", "default_timeout"} {
+		if strings.Contains(saved, leaked) {
+			t.Errorf("Expected %q not to be saved to the user file:\n%s", leaked, saved)
+		}
+	}
+	if !strings.Contains(saved, "tc_test_workkey123") {
+		t.Errorf("Expected new key to be saved:\n%s", saved)
+	}
+}
+
+func TestFindProjectConfigSkipsUserFile(t *testing.T) {
+	home := t.TempDir()
+	userPath := filepath.Join(home, ProjectConfigFile)
+	if err := os.WriteFile(userPath, []byte("keys: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if found := FindProjectConfig(home, userPath); found != "" {
+		t.Errorf("Expected the user file not to be treated as a project file, got %s", found)
+	}
+	if found := FindProjectConfig(home, ""); found != userPath {
+		t.Errorf("Expected %s, got %s", userPath, found)
+	}
+}
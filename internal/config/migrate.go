@@ -0,0 +1,319 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/toneclone/cli/internal/fsutil"
+)
+
+// CurrentVersion is the config schema version written by this CLI
+const CurrentVersion = 1
+
+// Migration upgrades raw config values from one schema version to the next
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(values map[string]interface{}) error
+}
+
+// migrations upgrade old config files, in order. Add a migration here and
+// bump CurrentVersion whenever the file layout changes.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "add the schema version key",
+		Apply:       func(values map[string]interface{}) error { return nil },
+	},
+}
+
+// MigrationResult describes the upgrade of a config file
+type MigrationResult struct {
+	Path    string
+	From    int
+	To      int
+	Applied []string // descriptions of the migrations that ran
+	Backup  string   // backup of the original file, if one was written
+	Data    []byte   // migrated file contents
+
+	// VersionOnly is set when the migrations changed nothing but the
+	// version key, so the file can keep its layout and comments
+	VersionOnly bool
+}
+
+// Changed reports whether the file needed migrating
+func (r *MigrationResult) Changed() bool {
+	return r.From != r.To
+}
+
+// MigrateFile upgrades a config file to the current schema version. The
+// original is kept next to it as <path>.v<version>.bak. With dryRun set,
+// nothing is written.
+func MigrateFile(path string, dryRun bool) (*MigrationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config %s: %w", path, err)
+	}
+
+	result, err := migrateValues(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+	result.Path = path
+
+	if !result.Changed() {
+		result.Data = data
+		return result, nil
+	}
+
+	if result.VersionOnly {
+		result.Data, err = setVersion(data, result.To)
+	}
+	if !result.VersionOnly || err != nil {
+		result.Data, err = yaml.Marshal(values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	result.Backup = fmt.Sprintf("%s.v%d.bak", path, result.From)
+	if err := fsutil.WriteFileAtomic(result.Backup, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, result.Data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return result, nil
+}
+
+// migrateValues upgrades raw config values in place
+func migrateValues(values map[string]interface{}) (*MigrationResult, error) {
+	version, err := schemaVersion(values)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is newer than this CLI supports (%d); upgrade toneclone", version, CurrentVersion)
+	}
+
+	original, err := toMap(values)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{From: version, To: version}
+	for _, migration := range migrations {
+		if migration.From != result.To {
+			continue
+		}
+		if err := migration.Apply(values); err != nil {
+			return nil, fmt.Errorf("migration from version %d (%s): %w", migration.From, migration.Description, err)
+		}
+		result.Applied = append(result.Applied, migration.Description)
+		result.To = migration.From + 1
+	}
+
+	if result.Changed() {
+		values["version"] = result.To
+		original["version"] = result.To
+		result.VersionOnly = reflect.DeepEqual(original, values)
+	}
+	return result, nil
+}
+
+// setVersion sets the version key in the text of a config file, leaving the
+// rest of the file as it is
+func setVersion(data []byte, version int) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode ||
+		doc.Content[0].Style&yaml.FlowStyle != 0 || len(doc.Content[0].Content) == 0 {
+		return nil, errors.New("config file is not a block mapping")
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	mapping := doc.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == "version" {
+			lines[mapping.Content[i+1].Line-1] = replaceValue(lines[mapping.Content[i+1].Line-1], mapping.Content[i+1], strconv.Itoa(version))
+			return []byte(strings.Join(lines, "")), nil
+		}
+	}
+
+	first := mapping.Content[0]
+	line := fmt.Sprintf("%sversion: %d\n", strings.Repeat(" ", first.Column-1), version)
+	lines = slices.Insert(lines, first.Line-1, line)
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// replaceValue replaces the scalar value node on its line of text, along
+// with anything after it such as a comment
+func replaceValue(line string, value *yaml.Node, replacement string) string {
+	start := min(value.Column-1, len(line))
+	end := ""
+	if strings.HasSuffix(line, "\n") {
+		end = "\n"
+	}
+	return line[:start] + replacement + end
+}
+
+// secretKeyFields are key profile settings holding credentials
+var secretKeyFields = map[string]bool{
+	"key":           true,
+	"refresh_token": true,
+}
+
+// RedactFile returns the text of a config file with API keys and tokens
+// hidden. Files in block style keep their layout; others are re-encoded.
+func RedactFile(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return data, nil
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	reencode := false
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "keys" {
+			continue
+		}
+		keys := root.Content[i+1]
+		for j := 1; j < len(keys.Content); j += 2 {
+			profile := keys.Content[j]
+			for k := 0; k+1 < len(profile.Content); k += 2 {
+				value := profile.Content[k+1]
+				if !secretKeyFields[profile.Content[k].Value] || value.Kind != yaml.ScalarNode {
+					continue
+				}
+				// Values sharing a line or spanning several need re-encoding
+				if (root.Style|keys.Style|profile.Style)&yaml.FlowStyle != 0 ||
+					value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+					reencode = true
+				} else {
+					lines[value.Line-1] = replaceValue(lines[value.Line-1], value, `"***REDACTED***"`)
+				}
+				value.Value, value.Style = "***REDACTED***", 0
+			}
+		}
+	}
+
+	if reencode {
+		return yaml.Marshal(&doc)
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// schemaVersion returns the version key of raw config values. Files
+// written before versioning have none and are version 0.
+func schemaVersion(values map[string]interface{}) (int, error) {
+	raw, ok := values["version"]
+	if !ok {
+		return 0, nil
+	}
+
+	version, ok := raw.(int)
+	if !ok || version < 0 {
+		return 0, fmt.Errorf("invalid config version %v", raw)
+	}
+	return version, nil
+}
+
+// UnknownKeys returns the paths in raw config values that match no setting,
+// each with a suggestion when a similar setting exists
+func UnknownKeys(values map[string]interface{}) []string {
+	var unknown []string
+	collectUnknownKeys(values, reflect.TypeOf(Config{}), "", &unknown)
+	sort.Strings(unknown)
+	return unknown
+}
+
+func collectUnknownKeys(values map[string]interface{}, t reflect.Type, prefix string, unknown *[]string) {
+	for key, value := range values {
+		path := joinPath(prefix, key)
+
+		var fieldType reflect.Type
+		switch t.Kind() {
+		case reflect.Map:
+			fieldType = t.Elem()
+		case reflect.Struct:
+			field, ok := yamlField(t, key)
+			if !ok {
+				message := fmt.Sprintf("unknown key '%s'", path)
+				if suggestion := suggestField(t, key); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean '%s'?)", joinPath(prefix, suggestion))
+				}
+				*unknown = append(*unknown, message)
+				continue
+			}
+			fieldType = field.Type
+		default:
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectUnknownKeys(nested, fieldType, path, unknown)
+		}
+	}
+}
+
+// suggestField returns the yaml name of the field closest to name, if any
+// is close enough to be a likely typo
+func suggestField(t reflect.Type, name string) string {
+	best, bestDistance := "", 3
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if distance := editDistance(strings.ToLower(name), tag); distance < bestDistance {
+			best, bestDistance = tag, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `default_key: main
+keys:
+  main:
+    key: tc_test_mainkey123
+    base_url: https://api.toneclone.ai
+`
+
+func TestMigrateFileDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(legacyConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateFile(path, true)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if result.From != 0 || result.To != CurrentVersion || len(result.Applied) == 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if !strings.Contains(string(result.Data), "version: 1") {
+		t.Errorf("Expected migrated data to carry the version:\n%s", result.Data)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != legacyConfig {
+		t.Error("Expected dry run to leave the file alone")
+	}
+	if result.Backup != "" {
+		t.Errorf("Expected no backup on dry run, got %s", result.Backup)
+	}
+}
+
+func TestMigrateFileWritesBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(legacyConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateFile(path, false)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	backup, err := os.ReadFile(result.Backup)
+	if err != nil || string(backup) != legacyConfig {
+		t.Errorf("Expected backup of the original file, got %q (%v)", backup, err)
+	}
+
+	// A second run has nothing to do
+	result, err = MigrateFile(path, false)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if result.Changed() {
+		t.Errorf("Expected migrated file to be current, got %+v", result)
+	}
+}
+
+func TestMigrateFileRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 99\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MigrateFile(path, true); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected newer version error, got %v", err)
+	}
+}
+
+func TestLoadLeavesVersionOnlyUserFile(t *testing.T) {
+	userPath := setupLayers(t, "", legacyConfig, "")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Expected version %d, got %d", CurrentVersion, cfg.Version)
+	}
+
+	// Nothing but the version key would change, so the file is left alone
+	data, _ := os.ReadFile(userPath)
+	if string(data) != legacyConfig {
+		t.Errorf("Expected user file to be left alone:\n%s", data)
+	}
+	if _, err := os.Stat(userPath + ".v0.bak"); !os.IsNotExist(err) {
+		t.Errorf("Expected no backup of the user file: %v", err)
+	}
+}
+
+func TestMigrateFileKeepsLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "# My settings\nkeys:\n    main:\n        key: tc_test_mainkey123 # personal\ndefault_key: main\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := MigrateFile(path, false)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	expected := "# My settings\nversion: 1\nkeys:\n    main:\n        key: tc_test_mainkey123 # personal\ndefault_key: main\n"
+	if data, _ := os.ReadFile(path); string(data) != expected || string(result.Data) != expected {
+		t.Errorf("Expected only the version key to be added, got:\n%s", data)
+	}
+}
+
+func TestRedactFile(t *testing.T) {
+	data := "keys:\n  main:\n    key: tc_test_mainkey123 # personal\n    base_url: https://api.toneclone.ai\n  sso:\n    key: \"access\"\n    refresh_token: refresh123\n"
+	redacted, err := RedactFile([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+	expected := "keys:\n  main:\n    key: \"***REDACTED***\"\n    base_url: https://api.toneclone.ai\n  sso:\n    key: \"***REDACTED***\"\n    refresh_token: \"***REDACTED***\"\n"
+	if string(redacted) != expected {
+		t.Errorf("Unexpected redacted file:\n%s", redacted)
+	}
+
+	// Flow style is re-encoded
+	redacted, err = RedactFile([]byte("keys: {main: {key: tc_test_mainkey123}}\n"))
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+	if strings.Contains(string(redacted), "tc_test_mainkey123") {
+		t.Errorf("Expected the key to be hidden:\n%s", redacted)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	userPath := setupLayers(t, "", legacyConfig+"defaults:\n  persna: Casual\n  tone: warm\nverbos: true\n", "")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	warnings := strings.Join(cfg.Warnings(), "\n")
+	for _, want := range []string{
+		"unknown key 'defaults.persna' (did you mean 'defaults.persona'?)",
+		"unknown key 'defaults.tone' in",
+		"unknown key 'verbos' (did you mean 'verbose'?)",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected warning %q, got:\n%s", want, warnings)
+		}
+	}
+}
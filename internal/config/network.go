@@ -0,0 +1,157 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// NetworkFlags holds the network settings given as command line flags. The
+// CLI fills it in before loading the configuration.
+var NetworkFlags NetworkConfig
+
+// NetworkConfig controls how the CLI connects to the API on corporate
+// networks: extra CA certificates, client certificates, proxies and the
+// minimum TLS version. It can be set globally and per environment.
+type NetworkConfig struct {
+	CACert        string   `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`         // PEM bundle trusted in addition to the system roots
+	ClientCert    string   `yaml:"client_cert,omitempty" json:"client_cert,omitempty"` // PEM certificate for mutual TLS
+	ClientKey     string   `yaml:"client_key,omitempty" json:"client_key,omitempty"`   // PEM private key for client_cert
+	Proxy         string   `yaml:"proxy,omitempty" json:"proxy,omitempty"`             // http, https or socks5 URL
+	NoProxy       []string `yaml:"no_proxy,omitempty" json:"no_proxy,omitempty"`       // hosts, domain suffixes and CIDR ranges to reach directly
+	MinTLSVersion string   `yaml:"min_tls_version,omitempty" json:"min_tls_version,omitempty"`
+}
+
+// IsZero reports whether no network setting is configured
+func (n NetworkConfig) IsZero() bool {
+	return n.CACert == "" && n.ClientCert == "" && n.ClientKey == "" &&
+		n.Proxy == "" && len(n.NoProxy) == 0 && n.MinTLSVersion == ""
+}
+
+// Merge returns n with every value set in override replacing its own
+func (n NetworkConfig) Merge(override NetworkConfig) NetworkConfig {
+	if override.CACert != "" {
+		n.CACert = override.CACert
+	}
+	if override.ClientCert != "" {
+		n.ClientCert = override.ClientCert
+	}
+	if override.ClientKey != "" {
+		n.ClientKey = override.ClientKey
+	}
+	if override.Proxy != "" {
+		n.Proxy = override.Proxy
+	}
+	if len(override.NoProxy) > 0 {
+		n.NoProxy = override.NoProxy
+	}
+	if override.MinTLSVersion != "" {
+		n.MinTLSVersion = override.MinTLSVersion
+	}
+	return n
+}
+
+// Validate checks the settings without reading any files
+func (n NetworkConfig) Validate() error {
+	if (n.ClientCert == "") != (n.ClientKey == "") {
+		return fmt.Errorf("client_cert and client_key must be set together")
+	}
+	if n.Proxy != "" {
+		if _, err := client.ParseProxyURL(n.Proxy); err != nil {
+			return err
+		}
+	}
+	if n.MinTLSVersion != "" {
+		if _, err := client.ParseTLSVersion(n.MinTLSVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientOptions loads the certificates and builds the API client options
+// for these settings. Without a proxy, the HTTPS_PROXY, HTTP_PROXY and
+// NO_PROXY environment variables still apply, and so does no_proxy.
+func (n NetworkConfig) ClientOptions() ([]client.ClientOption, error) {
+	var options []client.ClientOption
+
+	if n.CACert != "" {
+		pool, err := client.LoadCertPool(expandHome(n.CACert))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, client.WithRootCAs(pool))
+	}
+
+	if n.ClientCert != "" || n.ClientKey != "" {
+		cert, err := n.ClientCertificate()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, client.WithClientCertificate(cert))
+	}
+
+	if n.Proxy != "" {
+		proxyURL, err := client.ParseProxyURL(n.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, client.WithProxy(proxyURL, n.NoProxy))
+	} else if len(n.NoProxy) > 0 {
+		options = append(options, client.WithEnvironmentProxy(n.NoProxy))
+	}
+
+	if n.MinTLSVersion != "" {
+		version, err := client.ParseTLSVersion(n.MinTLSVersion)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, client.WithMinTLSVersion(version))
+	}
+
+	return options, nil
+}
+
+// ClientCertificate loads the client certificate for mutual TLS, refusing
+// one that has expired
+func (n NetworkConfig) ClientCertificate() (tls.Certificate, error) {
+	if n.ClientCert == "" || n.ClientKey == "" {
+		return tls.Certificate{}, fmt.Errorf("client_cert and client_key must be set together")
+	}
+
+	cert, err := client.LoadClientCertificate(expandHome(n.ClientCert), expandHome(n.ClientKey))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if cert.Leaf != nil && time.Now().After(cert.Leaf.NotAfter) {
+		return tls.Certificate{}, fmt.Errorf("client certificate %s expired on %s", n.ClientCert, cert.Leaf.NotAfter.Format("2006-01-02"))
+	}
+	return cert, nil
+}
+
+// NetworkFor returns the network settings for the named environment: the
+// global settings, overridden by the environment's and then by NetworkFlags
+func (c *Config) NetworkFor(envName string) NetworkConfig {
+	network := c.Network
+	if env, exists := c.Environments[envName]; exists {
+		network = network.Merge(env.Network)
+	}
+	return network.Merge(NetworkFlags)
+}
+
+// expandHome replaces a leading ~ in a path with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// resetNetworkFlags clears the network settings given as flags
+func resetNetworkFlags(t *testing.T) {
+	t.Helper()
+
+	NetworkFlags = NetworkConfig{}
+	t.Cleanup(func() { NetworkFlags = NetworkConfig{} })
+}
+
+func TestNetworkForPrecedence(t *testing.T) {
+	resetNetworkFlags(t)
+
+	cfg := environmentConfig()
+	cfg.Network = NetworkConfig{
+		CACert:        "/etc/ssl/corp.pem",
+		Proxy:         "http://proxy.corp:3128",
+		NoProxy:       []string{"localhost"},
+		MinTLSVersion: "1.2",
+	}
+	env := cfg.Environments["staging"]
+	env.Network = NetworkConfig{Proxy: "socks5://127.0.0.1:1080"}
+	cfg.Environments["staging"] = env
+
+	want := cfg.Network
+	if got := cfg.NetworkFor("prod"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected global settings for prod, got %+v", got)
+	}
+
+	want.Proxy = "socks5://127.0.0.1:1080"
+	if got := cfg.NetworkFor("staging"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected staging proxy over the global one, got %+v", got)
+	}
+
+	NetworkFlags = NetworkConfig{MinTLSVersion: "1.3", NoProxy: []string{".internal"}}
+	want.MinTLSVersion = "1.3"
+	want.NoProxy = []string{".internal"}
+	if got := cfg.NetworkFor("staging"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected flags to win, got %+v", got)
+	}
+}
+
+func TestGetCurrentKeyCarriesNetwork(t *testing.T) {
+	resetAccountSelection(t)
+	resetEnvironmentSelection(t)
+	resetNetworkFlags(t)
+
+	cfg := environmentConfig()
+	cfg.Network = NetworkConfig{Proxy: "http://proxy.corp:3128"}
+
+	keyConfig, err := cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if keyConfig.Network.Proxy != "http://proxy.corp:3128" {
+		t.Errorf("Expected key to carry the network settings, got %+v", keyConfig.Network)
+	}
+}
+
+func TestNetworkValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		network NetworkConfig
+		wantErr string
+	}{
+		{name: "empty", network: NetworkConfig{}},
+		{name: "valid", network: NetworkConfig{Proxy: "socks5://127.0.0.1:1080", MinTLSVersion: "1.3"}},
+		{name: "cert without key", network: NetworkConfig{ClientCert: "client.pem"}, wantErr: "set together"},
+		{name: "bad proxy scheme", network: NetworkConfig{Proxy: "ftp://proxy"}, wantErr: "unsupported proxy scheme"},
+		{name: "bad tls version", network: NetworkConfig{MinTLSVersion: "1.4"}, wantErr: "unsupported TLS version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.network.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	cfg := environmentConfig()
+	env := cfg.Environments["local"]
+	env.Network.MinTLSVersion = "9"
+	cfg.Environments["local"] = env
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "environment 'local'") {
+		t.Errorf("Expected environment network error, got %v", err)
+	}
+}
+
+func TestNetworkClientOptions(t *testing.T) {
+	options, err := NetworkConfig{}.ClientOptions()
+	if err != nil || len(options) != 0 {
+		t.Errorf("Expected no options for empty settings, got %d (%v)", len(options), err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := (NetworkConfig{CACert: missing}).ClientOptions(); err == nil {
+		t.Error("Expected error for a missing CA bundle")
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (NetworkConfig{CACert: notPEM}).ClientOptions(); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("Expected error for a bundle without certificates, got %v", err)
+	}
+
+	options, err = NetworkConfig{Proxy: "proxy.corp:3128", MinTLSVersion: "1.2"}.ClientOptions()
+	if err != nil || len(options) != 2 {
+		t.Errorf("Expected proxy and TLS options, got %d (%v)", len(options), err)
+	}
+}
+
+func TestLoadNetworkFromUserConfig(t *testing.T) {
+	userPath := setupLayers(t, "", `default_key: main
+network:
+  proxy: http://proxy.corp:3128
+  no_proxy: [localhost, .internal]
+environments:
+  local:
+    base_url: http://localhost:8080
+    network:
+      min_tls_version: "1.3"
+keys:
+  main:
+    key: tc_test_mainkey123
+    env: local
+`, "")
+
+	cfg, err := loadLayered(userPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.Warnings()) > 0 {
+		t.Errorf("Unexpected warnings: %v", cfg.Warnings())
+	}
+
+	got := cfg.NetworkFor("local")
+	if got.Proxy != "http://proxy.corp:3128" || len(got.NoProxy) != 2 || got.MinTLSVersion != "1.3" {
+		t.Errorf("Unexpected network settings: %+v", got)
+	}
+}
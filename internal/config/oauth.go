@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AuthOAuth marks a key profile holding OAuth tokens from a device login
+// instead of an API key
+const AuthOAuth = "oauth"
+
+// OAuthConfig overrides the authorization server used by device logins. By
+// default it lives under the API base URL.
+type OAuthConfig struct {
+	ClientID      string   `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+	DeviceAuthURL string   `yaml:"device_auth_url,omitempty" json:"device_auth_url,omitempty"`
+	TokenURL      string   `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+	Scopes        []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+}
+
+// IsOAuth reports whether the profile holds OAuth tokens
+func (k APIKeyConfig) IsOAuth() bool {
+	return k.Auth == AuthOAuth
+}
+
+// refreshRef is where a stored profile keeps its refresh token
+func (k APIKeyConfig) refreshRef(profile string) string {
+	return k.ref(profile) + ".refresh"
+}
+
+// ResolveRefreshToken returns the refresh token of an OAuth profile,
+// reading it from the profile's credential store if it has one
+func (k APIKeyConfig) ResolveRefreshToken(profile string) (string, error) {
+	if !k.IsOAuth() {
+		return "", fmt.Errorf("profile '%s' does not use device login", profile)
+	}
+	if !k.IsStored() {
+		return k.RefreshToken, nil
+	}
+
+	store, err := OpenCredentialStore(k.Store)
+	if err != nil {
+		return "", err
+	}
+	token, err := store.Get(k.refreshRef(profile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read refresh token for profile '%s' from %s: %w", profile, k.Store, err)
+	}
+	return token, nil
+}
+
+// SetTokens stores new OAuth tokens for a profile, in its credential store
+// if it has one, and marks the profile as using device login
+func (c *Config) SetTokens(name, accessToken, refreshToken string, expiry time.Time) error {
+	keyConfig, exists := c.Keys[name]
+	if !exists {
+		return fmt.Errorf("API key profile '%s' not found", name)
+	}
+	if keyConfig.Store == StoreCommand {
+		return fmt.Errorf("profile '%s' reads its key from key_command and cannot hold OAuth tokens", name)
+	}
+
+	keyConfig.Auth = AuthOAuth
+	keyConfig.TokenExpiry = expiry
+	c.Keys[name] = keyConfig
+
+	if err := c.UpdateKey(name, accessToken); err != nil {
+		return err
+	}
+	if !keyConfig.IsStored() {
+		keyConfig = c.Keys[name]
+		keyConfig.RefreshToken = refreshToken
+		c.Keys[name] = keyConfig
+		return nil
+	}
+
+	store, err := OpenCredentialStore(keyConfig.Store)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(keyConfig.refreshRef(name), refreshToken); err != nil {
+		return fmt.Errorf("failed to store refresh token for profile '%s': %w", name, err)
+	}
+	return nil
+}
+
+// migrateRefreshToken moves a profile's plaintext refresh token into store
+func migrateRefreshToken(name string, keyConfig APIKeyConfig, store CredentialStore) (APIKeyConfig, error) {
+	if keyConfig.RefreshToken == "" {
+		return keyConfig, nil
+	}
+	if err := store.Set(keyConfig.refreshRef(name), keyConfig.RefreshToken); err != nil {
+		return keyConfig, fmt.Errorf("failed to store refresh token for profile '%s': %w", name, err)
+	}
+	keyConfig.RefreshToken = ""
+	return keyConfig, nil
+}
+
+// deleteStoredRefreshToken removes a stored profile's refresh token
+func deleteStoredRefreshToken(name string, keyConfig APIKeyConfig, store CredentialStore) error {
+	if !keyConfig.IsOAuth() {
+		return nil
+	}
+	if err := store.Delete(keyConfig.refreshRef(name)); err != nil && !errors.Is(err, ErrCredentialNotFound) {
+		return fmt.Errorf("failed to delete refresh token for profile '%s' from %s: %w", name, keyConfig.Store, err)
+	}
+	return nil
+}
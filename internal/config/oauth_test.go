@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetTokensInline(t *testing.T) {
+	resetAccountSelection(t)
+	resetEnvironmentSelection(t)
+
+	cfg := NewConfig()
+	cfg.AddKey("work", "opaque-access-1", "https://api.toneclone.ai")
+	expiry := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if err := cfg.SetTokens("work", "opaque-access-2", "refresh-2", expiry); err != nil {
+		t.Fatalf("Failed to set tokens: %v", err)
+	}
+
+	keyConfig := cfg.Keys["work"]
+	if !keyConfig.IsOAuth() || keyConfig.Key != "opaque-access-2" || !keyConfig.TokenExpiry.Equal(expiry) {
+		t.Errorf("Unexpected profile: %+v", keyConfig)
+	}
+	if refresh, err := keyConfig.ResolveRefreshToken("work"); err != nil || refresh != "refresh-2" {
+		t.Errorf("Expected refresh-2, got %q (%v)", refresh, err)
+	}
+
+	// Access tokens are not API keys, but the profile is valid
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected OAuth profile to be valid: %v", err)
+	}
+
+	current, err := cfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if current.Profile != "work" || current.Key != "opaque-access-2" {
+		t.Errorf("Unexpected current key: %+v", current)
+	}
+
+	// Tokens survive a save and reload
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := cfg.SaveConfig(configPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	for _, want := range []string{"auth: oauth", "refresh_token: refresh-2", "token_expiry:"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected saved config to contain %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestSetTokensInStore(t *testing.T) {
+	t.Setenv("TONECLONE_FAKE_KEYRING", filepath.Join(t.TempDir(), "keyring.json"))
+
+	cfg := NewConfig()
+	cfg.AddKey("work", "opaque-access-1", "https://api.toneclone.ai")
+	if err := cfg.SetTokens("work", "opaque-access-1", "refresh-1", time.Time{}); err != nil {
+		t.Fatalf("Failed to set tokens: %v", err)
+	}
+
+	// Moving the profile to a store takes the refresh token along
+	if err := cfg.MigrateKey("work", StoreFake); err != nil {
+		t.Fatalf("Failed to migrate key: %v", err)
+	}
+	keyConfig := cfg.Keys["work"]
+	if keyConfig.Key != "" || keyConfig.RefreshToken != "" {
+		t.Errorf("Expected no secrets in config, got %+v", keyConfig)
+	}
+	if refresh, err := keyConfig.ResolveRefreshToken("work"); err != nil || refresh != "refresh-1" {
+		t.Errorf("Expected stored refresh-1, got %q (%v)", refresh, err)
+	}
+
+	// New tokens go to the store
+	if err := cfg.SetTokens("work", "opaque-access-2", "refresh-2", time.Time{}); err != nil {
+		t.Fatalf("Failed to set tokens: %v", err)
+	}
+	keyConfig = cfg.Keys["work"]
+	if key, err := keyConfig.ResolveKey("work"); err != nil || key != "opaque-access-2" {
+		t.Errorf("Expected stored opaque-access-2, got %q (%v)", key, err)
+	}
+	if refresh, err := keyConfig.ResolveRefreshToken("work"); err != nil || refresh != "refresh-2" {
+		t.Errorf("Expected stored refresh-2, got %q (%v)", refresh, err)
+	}
+
+	// Removing the profile removes both secrets
+	if err := cfg.RemoveKey("work"); err != nil {
+		t.Fatalf("Failed to remove key: %v", err)
+	}
+	if _, err := keyConfig.ResolveRefreshToken("work"); err == nil {
+		t.Error("Expected refresh token to be deleted with the profile")
+	}
+}
+
+func TestSetTokensRejectsKeyCommand(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Keys["work"] = APIKeyConfig{Store: StoreCommand, KeyCommand: "echo key", BaseURL: "https://api.toneclone.ai"}
+
+	if err := cfg.SetTokens("work", "access", "refresh", time.Time{}); err == nil {
+		t.Error("Expected error for a key_command profile")
+	}
+	if err := cfg.SetTokens("missing", "access", "refresh", time.Time{}); err == nil {
+		t.Error("Expected error for a missing profile")
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Keys["work"] = APIKeyConfig{Key: "tc_test_abcdefgh", BaseURL: "https://api.toneclone.ai", Auth: "saml"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "unknown auth") {
+		t.Errorf("Expected unknown auth error, got %v", err)
+	}
+
+	// Without device login an opaque key is still rejected
+	cfg.Keys["work"] = APIKeyConfig{Key: "opaque", BaseURL: "https://api.toneclone.ai"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected invalid key format error")
+	}
+}
@@ -0,0 +1,289 @@
+// Package docs tracks documentation files generated with ToneClone so that
+// drift between a file and the recipe that produced it can be detected.
+package docs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/toneclone/cli/internal/fsutil"
+	"github.com/toneclone/cli/internal/postprocess"
+)
+
+// Default file names, looked up in the working directory
+const (
+	DefaultManifestFile = "toneclone-docs.yaml"
+	LockFileSuffix      = ".lock"
+)
+
+// Doc describes how one output file is generated
+type Doc struct {
+	Output       string   `yaml:"output" json:"output"`
+	Persona      string   `yaml:"persona" json:"persona"`
+	Profiles     []string `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	Prompt       string   `yaml:"prompt,omitempty" json:"prompt,omitempty"`
+	PromptFile   string   `yaml:"prompt_file,omitempty" json:"prompt_file,omitempty"`
+	Format       string   `yaml:"format,omitempty" json:"format,omitempty"`
+	Wrap         int      `yaml:"wrap,omitempty" json:"wrap,omitempty"`
+	Formality    int      `yaml:"formality,omitempty" json:"formality,omitempty"`
+	ReadingLevel int      `yaml:"reading_level,omitempty" json:"reading_level,omitempty"`
+	Length       int      `yaml:"length,omitempty" json:"length,omitempty"`
+	Model        string   `yaml:"model,omitempty" json:"model,omitempty"`
+}
+
+// Manifest lists generated docs. Paths are relative to the manifest file.
+type Manifest struct {
+	Docs []Doc `yaml:"docs"`
+
+	path string
+}
+
+// LoadManifest reads and validates a manifest file
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	manifest.path = path
+
+	seen := make(map[string]bool)
+	for i, doc := range manifest.Docs {
+		if doc.Output == "" {
+			return nil, fmt.Errorf("doc #%d has no output", i+1)
+		}
+		if seen[doc.Output] {
+			return nil, fmt.Errorf("output '%s' is listed more than once", doc.Output)
+		}
+		seen[doc.Output] = true
+
+		if doc.Persona == "" {
+			return nil, fmt.Errorf("doc '%s' has no persona", doc.Output)
+		}
+		if (doc.Prompt == "") == (doc.PromptFile == "") {
+			return nil, fmt.Errorf("doc '%s' must set exactly one of prompt or prompt_file", doc.Output)
+		}
+		if doc.Format != "" && !slices.Contains(postprocess.Formats, doc.Format) {
+			return nil, fmt.Errorf("doc '%s' has unsupported format '%s' (use %s)", doc.Output, doc.Format, strings.Join(postprocess.Formats, ", "))
+		}
+	}
+
+	return &manifest, nil
+}
+
+// Path returns the manifest file path
+func (m *Manifest) Path() string {
+	return m.path
+}
+
+// LockPath returns the path of the lock file that records fingerprints
+func (m *Manifest) LockPath() string {
+	return m.path + LockFileSuffix
+}
+
+// Resolve returns a path relative to the manifest directory
+func (m *Manifest) Resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(m.path), path)
+}
+
+// PromptText returns the prompt for a doc, reading prompt_file if needed
+func (m *Manifest) PromptText(doc Doc) (string, error) {
+	if doc.PromptFile == "" {
+		return doc.Prompt, nil
+	}
+
+	data, err := os.ReadFile(m.Resolve(doc.PromptFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt file for '%s': %w", doc.Output, err)
+	}
+	return string(data), nil
+}
+
+// Fingerprint hashes everything that affects a doc's generated content.
+// Prompt files are hashed by content, so editing a prompt makes the doc stale.
+func (m *Manifest) Fingerprint(doc Doc) (string, error) {
+	prompt, err := m.PromptText(doc)
+	if err != nil {
+		return "", err
+	}
+
+	recipe := struct {
+		Prompt       string   `json:"prompt"`
+		Persona      string   `json:"persona"`
+		Profiles     []string `json:"profiles"`
+		Format       string   `json:"format"`
+		Wrap         int      `json:"wrap"`
+		Formality    int      `json:"formality"`
+		ReadingLevel int      `json:"reading_level"`
+		Length       int      `json:"length"`
+		Model        string   `json:"model"`
+	}{
+		Prompt:       prompt,
+		Persona:      doc.Persona,
+		Profiles:     doc.Profiles,
+		Format:       doc.Format,
+		Wrap:         doc.Wrap,
+		Formality:    doc.Formality,
+		ReadingLevel: doc.ReadingLevel,
+		Length:       doc.Length,
+		Model:        doc.Model,
+	}
+
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode recipe: %w", err)
+	}
+	return hashBytes(data), nil
+}
+
+// LockEntry records what a generated file was produced from
+type LockEntry struct {
+	Recipe      string    `yaml:"recipe"`
+	Content     string    `yaml:"content"`
+	GeneratedAt time.Time `yaml:"generated_at"`
+}
+
+// Lock maps output paths to their recorded fingerprints
+type Lock struct {
+	Docs map[string]LockEntry `yaml:"docs"`
+}
+
+// LoadLock reads a lock file. A missing lock file yields an empty lock.
+func LoadLock(path string) (*Lock, error) {
+	lock := &Lock{Docs: make(map[string]LockEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	if lock.Docs == nil {
+		lock.Docs = make(map[string]LockEntry)
+	}
+
+	return lock, nil
+}
+
+// Save writes the lock file atomically
+func (l *Lock) Save(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to encode lock file: %w", err)
+	}
+	return fsutil.WriteFileAtomic(path, data, 0644)
+}
+
+// Status values reported by Check
+const (
+	StatusFresh    = "fresh"
+	StatusStale    = "stale"
+	StatusMissing  = "missing"
+	StatusModified = "modified"
+)
+
+// Result is the drift status of a single doc
+type Result struct {
+	Doc    Doc    `json:"-"`
+	Output string `json:"output"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	Recipe string `json:"recipe"`
+}
+
+// OK reports whether the doc is up to date
+func (r Result) OK() bool {
+	return r.Status == StatusFresh
+}
+
+// Check compares every doc in the manifest with the lock file
+func Check(manifest *Manifest, lock *Lock) ([]Result, error) {
+	var results []Result
+
+	for _, doc := range manifest.Docs {
+		fingerprint, err := manifest.Fingerprint(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		result := Result{Doc: doc, Output: doc.Output, Recipe: fingerprint, Status: StatusFresh}
+		entry, recorded := lock.Docs[doc.Output]
+		content, readErr := os.ReadFile(manifest.Resolve(doc.Output))
+
+		switch {
+		case os.IsNotExist(readErr):
+			result.Status = StatusMissing
+			result.Reason = "output file does not exist"
+		case readErr != nil:
+			return nil, fmt.Errorf("failed to read %s: %w", doc.Output, readErr)
+		case !recorded:
+			result.Status = StatusStale
+			result.Reason = "no recorded recipe"
+		case entry.Recipe != fingerprint:
+			result.Status = StatusStale
+			result.Reason = "recipe changed since last generation"
+		case entry.Content != hashBytes(content):
+			result.Status = StatusModified
+			result.Reason = "file was edited after generation"
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Record stores the fingerprints of a freshly generated doc
+func (l *Lock) Record(output, recipe string, content []byte) {
+	l.Docs[output] = LockEntry{
+		Recipe:      recipe,
+		Content:     hashBytes(content),
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Prune removes lock entries for outputs no longer in the manifest and
+// returns their names
+func (l *Lock) Prune(manifest *Manifest) []string {
+	listed := make(map[string]bool)
+	for _, doc := range manifest.Docs {
+		listed[doc.Output] = true
+	}
+
+	var removed []string
+	for output := range l.Docs {
+		if !listed[output] {
+			delete(l.Docs, output)
+			removed = append(removed, output)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,150 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupManifest(t *testing.T) *Manifest {
+	t.Helper()
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "prompts", "intro.txt"), "Write an intro")
+	writeFile(t, filepath.Join(dir, DefaultManifestFile), `docs:
+  - output: docs/intro.md
+    persona: Professional
+    profiles: [Documentation]
+    prompt_file: prompts/intro.txt
+    format: markdown
+  - output: docs/faq.md
+    persona: Professional
+    prompt: Write an FAQ
+`)
+
+	manifest, err := LoadManifest(filepath.Join(dir, DefaultManifestFile))
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	return manifest
+}
+
+func statuses(t *testing.T, manifest *Manifest, lock *Lock) map[string]string {
+	t.Helper()
+	results, err := Check(manifest, lock)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	out := make(map[string]string)
+	for _, r := range results {
+		out[r.Output] = r.Status
+	}
+	return out
+}
+
+func TestCheckLifecycle(t *testing.T) {
+	manifest := setupManifest(t)
+	lock := &Lock{Docs: make(map[string]LockEntry)}
+
+	// Nothing generated yet
+	got := statuses(t, manifest, lock)
+	if got["docs/intro.md"] != StatusMissing || got["docs/faq.md"] != StatusMissing {
+		t.Errorf("Expected both docs missing, got %v", got)
+	}
+
+	// Generate both and record them
+	for _, doc := range manifest.Docs {
+		content := "generated " + doc.Output
+		writeFile(t, manifest.Resolve(doc.Output), content)
+		recipe, err := manifest.Fingerprint(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lock.Record(doc.Output, recipe, []byte(content))
+	}
+
+	got = statuses(t, manifest, lock)
+	if got["docs/intro.md"] != StatusFresh || got["docs/faq.md"] != StatusFresh {
+		t.Errorf("Expected both docs fresh, got %v", got)
+	}
+
+	// Editing the prompt file makes the doc stale
+	writeFile(t, manifest.Resolve("prompts/intro.txt"), "Write a better intro")
+	// Hand-editing the output is reported as modified
+	writeFile(t, manifest.Resolve("docs/faq.md"), "hand edited")
+
+	got = statuses(t, manifest, lock)
+	if got["docs/intro.md"] != StatusStale {
+		t.Errorf("Expected intro to be stale, got %s", got["docs/intro.md"])
+	}
+	if got["docs/faq.md"] != StatusModified {
+		t.Errorf("Expected faq to be modified, got %s", got["docs/faq.md"])
+	}
+}
+
+func TestFingerprintChangesWithKnobs(t *testing.T) {
+	manifest := setupManifest(t)
+	doc := manifest.Docs[1]
+
+	before, _ := manifest.Fingerprint(doc)
+	doc.Formality = 4
+	after, _ := manifest.Fingerprint(doc)
+
+	if before == after {
+		t.Error("Expected fingerprint to change when a generation knob changes")
+	}
+}
+
+func TestLockRoundTripAndPrune(t *testing.T) {
+	manifest := setupManifest(t)
+	lock := &Lock{Docs: make(map[string]LockEntry)}
+	lock.Record("docs/faq.md", "sha256:abc", []byte("x"))
+	lock.Record("docs/removed.md", "sha256:def", []byte("y"))
+
+	removed := lock.Prune(manifest)
+	if len(removed) != 1 || removed[0] != "docs/removed.md" {
+		t.Errorf("Expected docs/removed.md to be pruned, got %v", removed)
+	}
+
+	if err := lock.Save(manifest.LockPath()); err != nil {
+		t.Fatalf("Failed to save lock: %v", err)
+	}
+
+	loaded, err := LoadLock(manifest.LockPath())
+	if err != nil {
+		t.Fatalf("Failed to load lock: %v", err)
+	}
+	if loaded.Docs["docs/faq.md"].Recipe != "sha256:abc" {
+		t.Errorf("Unexpected lock contents: %+v", loaded.Docs)
+	}
+}
+
+func TestLoadManifestValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultManifestFile)
+	writeFile(t, path, "docs:\n  - output: a.md\n    persona: P\n")
+
+	if _, err := LoadManifest(path); err == nil {
+		t.Error("Expected error for doc without a prompt")
+	}
+
+	writeFile(t, path, "docs:\n  - output: a.md\n    persona: P\n    prompt: Hi\n    format: mardown\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "unsupported format 'mardown'") {
+		t.Errorf("Expected error for an unknown format, got %v", err)
+	}
+
+	writeFile(t, path, "docs:\n  - output: a.md\n    persona: P\n    prompt: Hi\n    format: markdown\n")
+	if _, err := LoadManifest(path); err != nil {
+		t.Errorf("Expected markdown to be accepted, got %v", err)
+	}
+}
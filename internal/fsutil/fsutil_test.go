@@ -0,0 +1,47 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "out.txt")
+
+	if err := WriteFileAtomic(path, []byte("first"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := WriteFileAtomic(path, []byte("second"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("Expected 'second', got %q", string(data))
+	}
+
+	// No temporary files should be left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 file in directory, got %d", len(entries))
+	}
+}
+
+func TestAppendFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+
+	for _, chunk := range []string{"a\n", "b\n"} {
+		if err := AppendFile(path, []byte(chunk), 0644); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "a\nb\n" {
+		t.Errorf("Expected 'a\\nb\\n', got %q", string(data))
+	}
+}
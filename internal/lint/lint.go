@@ -0,0 +1,253 @@
+// Package lint checks human-written text against style rules derived from
+// writing profiles and local rule files.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/toneclone/cli/internal/textutil"
+)
+
+// Severity levels
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Rule identifiers
+const (
+	RuleBannedWord     = "banned-word"
+	RuleRequiredTerm   = "required-term"
+	RuleSentenceLength = "sentence-length"
+	RulePassiveVoice   = "passive-voice"
+	RuleReadingLevel   = "reading-level"
+)
+
+// Diagnostic is a single lint finding. Line and column are 1-based and
+// columns count characters, not bytes.
+type Diagnostic struct {
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndColumn int    `json:"end_column,omitempty"`
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]", d.File, d.Line, d.Column, d.Severity, d.Message, d.Rule)
+}
+
+// passiveRe matches a form of "to be" followed by a past participle
+var passiveRe = regexp.MustCompile(`(?i)\b(am|is|are|was|were|be|been|being)\s+(\w+ed|built|chosen|done|driven|given|known|made|seen|shown|taken|written|sent|paid|held|kept|left|told|found|thought|brought|bought|caught|taught|sold|won|lost)\b`)
+
+// Lint checks text against rules and returns diagnostics ordered by position
+func Lint(file, text string, rules Rules) []Diagnostic {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	doc := newDocument(text)
+
+	var diags []Diagnostic
+	add := func(d Diagnostic) {
+		d.File = file
+		diags = append(diags, d)
+	}
+
+	// Banned words, reported at every occurrence
+	for _, word := range rules.BannedWords {
+		re, err := termRegexp(word)
+		if err != nil {
+			continue
+		}
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			line, col := doc.position(loc[0])
+			_, endCol := doc.position(loc[1])
+			add(Diagnostic{
+				Line: line, Column: col, EndColumn: endCol,
+				Rule:     RuleBannedWord,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("avoid '%s'", text[loc[0]:loc[1]]),
+			})
+		}
+	}
+
+	// Required terms, reported once at the start of the document
+	for _, term := range rules.RequiredTerms {
+		re, err := termRegexp(term)
+		if err != nil || re.MatchString(text) {
+			continue
+		}
+		add(Diagnostic{
+			Line: 1, Column: 1,
+			Rule:     RuleRequiredTerm,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("required term '%s' not found", term),
+		})
+	}
+
+	sentences := doc.sentences()
+
+	// Sentence length
+	if rules.MaxSentenceWords > 0 {
+		for _, s := range sentences {
+			words := len(textutil.Words(text[s.start:s.end]))
+			if words <= rules.MaxSentenceWords {
+				continue
+			}
+			line, col := doc.position(s.start)
+			add(Diagnostic{
+				Line: line, Column: col,
+				Rule:     RuleSentenceLength,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("sentence has %d words (max %d)", words, rules.MaxSentenceWords),
+			})
+		}
+	}
+
+	// Passive voice ratio across sentences
+	if rules.MaxPassiveRatio > 0 && len(sentences) > 0 {
+		var passive []int
+		for _, s := range sentences {
+			if loc := passiveRe.FindStringIndex(text[s.start:s.end]); loc != nil {
+				passive = append(passive, s.start+loc[0])
+			}
+		}
+
+		ratio := float64(len(passive)) / float64(len(sentences))
+		if ratio > rules.MaxPassiveRatio {
+			add(Diagnostic{
+				Line: 1, Column: 1,
+				Rule:     RulePassiveVoice,
+				Severity: SeverityWarning,
+				Message: fmt.Sprintf("%.0f%% of sentences use passive voice (max %.0f%%)",
+					ratio*100, rules.MaxPassiveRatio*100),
+			})
+			for _, offset := range passive {
+				line, col := doc.position(offset)
+				add(Diagnostic{
+					Line: line, Column: col,
+					Rule:     RulePassiveVoice,
+					Severity: SeverityInfo,
+					Message:  "passive voice",
+				})
+			}
+		}
+	}
+
+	// Reading level of the whole document
+	if rules.MaxReadingGrade > 0 {
+		if grade := textutil.ReadingGrade(text); grade > rules.MaxReadingGrade {
+			add(Diagnostic{
+				Line: 1, Column: 1,
+				Rule:     RuleReadingLevel,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("reading grade %.1f exceeds %.1f", grade, rules.MaxReadingGrade),
+			})
+		}
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Column < diags[j].Column
+	})
+
+	return diags
+}
+
+// termRegexp matches a term case-insensitively on word boundaries
+func termRegexp(term string) (*regexp.Regexp, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, fmt.Errorf("empty term")
+	}
+	return regexp.Compile(`(?i)(^|\b)` + regexp.QuoteMeta(term) + `($|\b)`)
+}
+
+// document maps byte offsets to line and column positions
+type document struct {
+	text       string
+	lineStarts []int
+}
+
+type span struct {
+	start, end int
+}
+
+func newDocument(text string) *document {
+	doc := &document{text: text, lineStarts: []int{0}}
+	for i, r := range text {
+		if r == '\n' {
+			doc.lineStarts = append(doc.lineStarts, i+1)
+		}
+	}
+	return doc
+}
+
+// position returns the 1-based line and character column of a byte offset
+func (d *document) position(offset int) (int, int) {
+	line := sort.Search(len(d.lineStarts), func(i int) bool {
+		return d.lineStarts[i] > offset
+	}) - 1
+	col := len([]rune(d.text[d.lineStarts[line]:offset])) + 1
+	return line + 1, col
+}
+
+// sentences returns spans of sentences, split on terminal punctuation
+// and blank lines. Leading whitespace is excluded from each span.
+func (d *document) sentences() []span {
+	var spans []span
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 && strings.TrimSpace(d.text[start:end]) != "" {
+			spans = append(spans, span{start: start, end: end})
+		}
+		start = -1
+	}
+
+	for i, r := range d.text {
+		if start < 0 {
+			if unicode.IsSpace(r) {
+				continue
+			}
+			start = i
+		}
+
+		next := i + len(string(r))
+		switch r {
+		case '.', '!', '?':
+			if next >= len(d.text) || unicode.IsSpace(rune(d.text[next])) {
+				flush(next)
+			}
+		case '\n':
+			if next < len(d.text) && d.text[next] == '\n' {
+				flush(i)
+			}
+		}
+	}
+	flush(len(d.text))
+
+	return spans
+}
+
+// Count returns the number of diagnostics at each severity
+func Count(diags []Diagnostic) (errors, warnings, infos int) {
+	for _, d := range diags {
+		switch d.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		default:
+			infos++
+		}
+	}
+	return
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRulesFromInstructions(t *testing.T) {
+	instructions := `Write friendly emails. Avoid "synergy" and "leverage". Never use jargon.
+Always mention ToneClone. Keep sentences under 20 words and avoid passive voice.
+Aim for a grade 8 reading level.`
+
+	rules := RulesFromInstructions(instructions)
+
+	if !reflect.DeepEqual(rules.BannedWords, []string{"synergy", "leverage"}) {
+		t.Errorf("Unexpected banned words: %q", rules.BannedWords)
+	}
+	if !reflect.DeepEqual(rules.RequiredTerms, []string{"ToneClone"}) {
+		t.Errorf("Unexpected required terms: %q", rules.RequiredTerms)
+	}
+	if rules.MaxSentenceWords != 20 {
+		t.Errorf("Expected max sentence words 20, got %d", rules.MaxSentenceWords)
+	}
+	if rules.MaxPassiveRatio == 0 {
+		t.Error("Expected passive voice rule to be enabled")
+	}
+	if rules.MaxReadingGrade != 8 {
+		t.Errorf("Expected max reading grade 8, got %.1f", rules.MaxReadingGrade)
+	}
+}
+
+func TestRulesFromInstructionsBannedClauses(t *testing.T) {
+	tests := []struct {
+		instructions string
+		expected     []string
+	}{
+		{`No words like "awesome" or "epic".`, []string{"awesome", "epic"}},
+		{"No the phrase circle back.", []string{"circle back"}},
+		{"Don't say utilize.", []string{"utilize"}},
+		// A plain "no" is an ordinary instruction, not a banned phrase
+		{"No need to apologise.", nil},
+		{"Use no more than 3 sentences.", nil},
+		{"No emoji, no exclamation marks.", nil},
+	}
+
+	for _, test := range tests {
+		rules := RulesFromInstructions(test.instructions)
+		if !reflect.DeepEqual(rules.BannedWords, test.expected) {
+			t.Errorf("%q: expected banned words %q, got %q", test.instructions, test.expected, rules.BannedWords)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := Rules{BannedWords: []string{"synergy"}, MaxSentenceWords: 20}
+	override := Rules{BannedWords: []string{"Synergy", "utilize"}, MaxReadingGrade: 10}
+
+	merged := Merge(base, override)
+
+	if !reflect.DeepEqual(merged.BannedWords, []string{"synergy", "utilize"}) {
+		t.Errorf("Unexpected banned words: %q", merged.BannedWords)
+	}
+	if merged.MaxSentenceWords != 20 || merged.MaxReadingGrade != 10 {
+		t.Errorf("Unexpected limits: %+v", merged)
+	}
+}
+
+func TestLintPositions(t *testing.T) {
+	text := "Hello team.\nWe love synergy here. Real Synergy!"
+	diags := Lint("draft.md", text, Rules{BannedWords: []string{"synergy"}})
+
+	if len(diags) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %d: %v", len(diags), diags)
+	}
+
+	first := diags[0]
+	if first.Line != 2 || first.Column != 9 || first.EndColumn != 16 {
+		t.Errorf("Unexpected position for first diagnostic: %+v", first)
+	}
+	if first.Severity != SeverityError || first.Rule != RuleBannedWord {
+		t.Errorf("Unexpected rule or severity: %+v", first)
+	}
+	if diags[1].Column != 28 {
+		t.Errorf("Expected second match at column 28, got %d", diags[1].Column)
+	}
+}
+
+func TestLintSentenceLengthAndRequired(t *testing.T) {
+	text := "Short one. This sentence is definitely going to be longer than six words."
+	diags := Lint("draft.md", text, Rules{MaxSentenceWords: 6, RequiredTerms: []string{"ToneClone"}})
+
+	var rulesFound []string
+	for _, d := range diags {
+		rulesFound = append(rulesFound, d.Rule)
+	}
+
+	expected := []string{RuleRequiredTerm, RuleSentenceLength}
+	if !reflect.DeepEqual(rulesFound, expected) {
+		t.Errorf("Expected rules %v, got %v", expected, rulesFound)
+	}
+
+	if diags[1].Column != 12 {
+		t.Errorf("Expected long sentence at column 12, got %d", diags[1].Column)
+	}
+}
+
+func TestLintPassiveVoice(t *testing.T) {
+	text := "The report was written by Sam. The bug was fixed. We shipped it."
+	diags := Lint("draft.md", text, Rules{MaxPassiveRatio: 0.5})
+
+	errors, warnings, infos := Count(diags)
+	if errors != 0 || warnings != 1 || infos != 2 {
+		t.Errorf("Expected 0 errors, 1 warning, 2 infos; got %d, %d, %d", errors, warnings, infos)
+	}
+}
+
+func TestToSARIF(t *testing.T) {
+	diags := []Diagnostic{{File: "a.md", Line: 2, Column: 3, Rule: RuleBannedWord, Severity: SeverityError, Message: "avoid 'x'"}}
+	log := ToSARIF(diags, "1.0.0")
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: %+v", log)
+	}
+
+	result := log.Runs[0].Results[0]
+	if result.Level != "error" || result.Locations[0].PhysicalLocation.Region.StartLine != 2 {
+		t.Errorf("Unexpected SARIF result: %+v", result)
+	}
+}
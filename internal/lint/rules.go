@@ -0,0 +1,165 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules configures the style checks. Zero values disable a check.
+type Rules struct {
+	BannedWords      []string `yaml:"banned_words,omitempty" json:"banned_words,omitempty"`
+	RequiredTerms    []string `yaml:"required_terms,omitempty" json:"required_terms,omitempty"`
+	MaxSentenceWords int      `yaml:"max_sentence_words,omitempty" json:"max_sentence_words,omitempty"`
+	MaxPassiveRatio  float64  `yaml:"max_passive_ratio,omitempty" json:"max_passive_ratio,omitempty"`
+	MaxReadingGrade  float64  `yaml:"max_reading_grade,omitempty" json:"max_reading_grade,omitempty"`
+}
+
+// LoadRules reads a YAML rules file
+func LoadRules(path string) (Rules, error) {
+	var rules Rules
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+// Merge combines two rule sets. List rules are unioned; for numeric limits
+// the override wins when set.
+func Merge(base, override Rules) Rules {
+	merged := Rules{
+		BannedWords:      unionFold(base.BannedWords, override.BannedWords),
+		RequiredTerms:    unionFold(base.RequiredTerms, override.RequiredTerms),
+		MaxSentenceWords: base.MaxSentenceWords,
+		MaxPassiveRatio:  base.MaxPassiveRatio,
+		MaxReadingGrade:  base.MaxReadingGrade,
+	}
+
+	if override.MaxSentenceWords > 0 {
+		merged.MaxSentenceWords = override.MaxSentenceWords
+	}
+	if override.MaxPassiveRatio > 0 {
+		merged.MaxPassiveRatio = override.MaxPassiveRatio
+	}
+	if override.MaxReadingGrade > 0 {
+		merged.MaxReadingGrade = override.MaxReadingGrade
+	}
+
+	return merged
+}
+
+func unionFold(a, b []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range [][]string{a, b} {
+		for _, item := range list {
+			item = strings.TrimSpace(item)
+			key := strings.ToLower(item)
+			if item == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+var (
+	quotedRe       = regexp.MustCompile(`["'“‘]([^"'”’]+)["'”’]`)
+	bannedClauseRe = regexp.MustCompile(`(?i)\b(?:(?:avoid(?: using)?|never (?:use|say|write)|don'?t (?:use|say|write)|do not (?:use|say|write))\s+(?:the (?:words?|terms?|phrases?)\s+)?|no\s+(?:the\s+)?(?:words?|terms?|phrases?)\s+(?:like\s+|such as\s+)?)([^.;\n]+)`)
+	requiredRe     = regexp.MustCompile(`(?i)\b(?:always (?:use|mention|include|say)|must (?:use|mention|include)|make sure to (?:use|mention|include))\s+(?:the (?:words?|terms?|phrases?)\s+)?([^.;\n]+)`)
+	sentenceLenRe  = regexp.MustCompile(`(?i)sentences?\s+(?:under|below|shorter than|of at most|no longer than|less than|fewer than|max(?:imum)?(?: of)?)\s+(\d+)\s+words`)
+	maxWordsRe     = regexp.MustCompile(`(?i)(?:no more than|at most|max(?:imum)?(?: of)?)\s+(\d+)\s+words\s+(?:per|a|each)\s+sentence`)
+	passiveRuleRe  = regexp.MustCompile(`(?i)\b(?:avoid|no|minimi[sz]e|limit)\s+(?:the\s+)?passive(?: voice)?|\b(?:use|prefer|write in)\s+(?:the\s+)?active voice`)
+	termSplitRe    = regexp.MustCompile(`,|\bor\b|\band\b`)
+	gradeRe        = regexp.MustCompile(`(?i)(?:grade\s+(\d+(?:\.\d+)?)\s+reading level|reading level\s+(?:of\s+|at\s+|below\s+|under\s+)?(?:grade\s+)?(\d+(?:\.\d+)?)|(\d+)(?:st|nd|rd|th)[- ]grade)`)
+)
+
+// ignoredBannedTerms are objects of "avoid ..." that describe style rather
+// than vocabulary and are handled by other rules
+var ignoredBannedTerms = map[string]bool{
+	"passive":        true,
+	"passive voice":  true,
+	"the passive":    true,
+	"jargon":         true,
+	"long sentences": true,
+}
+
+// RulesFromInstructions derives rules from free-text profile instructions.
+// It recognizes phrasings such as "avoid 'synergy'", "always mention
+// ToneClone", "keep sentences under 20 words", "avoid passive voice" and
+// "grade 8 reading level". Anything it does not understand is ignored.
+func RulesFromInstructions(instructions string) Rules {
+	var rules Rules
+
+	for _, m := range bannedClauseRe.FindAllStringSubmatch(instructions, -1) {
+		for _, term := range extractTerms(m[1]) {
+			if !ignoredBannedTerms[strings.ToLower(term)] {
+				rules.BannedWords = append(rules.BannedWords, term)
+			}
+		}
+	}
+
+	for _, m := range requiredRe.FindAllStringSubmatch(instructions, -1) {
+		rules.RequiredTerms = append(rules.RequiredTerms, extractTerms(m[1])...)
+	}
+
+	if m := sentenceLenRe.FindStringSubmatch(instructions); m != nil {
+		rules.MaxSentenceWords, _ = strconv.Atoi(m[1])
+	} else if m := maxWordsRe.FindStringSubmatch(instructions); m != nil {
+		rules.MaxSentenceWords, _ = strconv.Atoi(m[1])
+	}
+
+	if passiveRuleRe.MatchString(instructions) {
+		rules.MaxPassiveRatio = 0.1
+	}
+
+	if m := gradeRe.FindStringSubmatch(instructions); m != nil {
+		for _, group := range m[1:] {
+			if group != "" {
+				rules.MaxReadingGrade, _ = strconv.ParseFloat(group, 64)
+				break
+			}
+		}
+	}
+
+	rules.BannedWords = unionFold(rules.BannedWords, nil)
+	rules.RequiredTerms = unionFold(rules.RequiredTerms, nil)
+
+	return rules
+}
+
+// extractTerms pulls terms out of a clause. Quoted terms are preferred;
+// otherwise the clause is split on commas and "or"/"and".
+func extractTerms(clause string) []string {
+	if quoted := quotedRe.FindAllStringSubmatch(clause, -1); len(quoted) > 0 {
+		var terms []string
+		for _, q := range quoted {
+			terms = append(terms, strings.TrimSpace(q[1]))
+		}
+		return terms
+	}
+
+	var terms []string
+	for _, part := range termSplitRe.Split(clause, -1) {
+		part = strings.Trim(strings.TrimSpace(part), `"'`)
+		// Long fragments are prose, not terms
+		if part == "" || len(strings.Fields(part)) > 3 {
+			continue
+		}
+		terms = append(terms, part)
+	}
+	return terms
+}
@@ -0,0 +1,120 @@
+package lint
+
+// SARIF 2.1.0 types, limited to what the linter emits
+
+// SARIFLog is the top-level SARIF document
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single analysis run
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the analysis tool
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the tool component and its rules
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a rule
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFResult is a single finding
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFMessage holds message text
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation is the location of a finding
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a file region
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation identifies a file
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a line/column range
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+var ruleDescriptions = []SARIFRule{
+	{ID: RuleBannedWord, ShortDescription: SARIFMessage{Text: "Word or phrase that the style guide forbids"}},
+	{ID: RuleRequiredTerm, ShortDescription: SARIFMessage{Text: "Term that the style guide requires"}},
+	{ID: RuleSentenceLength, ShortDescription: SARIFMessage{Text: "Sentence longer than the configured maximum"}},
+	{ID: RulePassiveVoice, ShortDescription: SARIFMessage{Text: "Too much passive voice"}},
+	{ID: RuleReadingLevel, ShortDescription: SARIFMessage{Text: "Reading grade level above the configured maximum"}},
+}
+
+// ToSARIF converts diagnostics to a SARIF log
+func ToSARIF(diags []Diagnostic, toolVersion string) SARIFLog {
+	results := make([]SARIFResult, 0, len(diags))
+	for _, d := range diags {
+		level := d.Severity
+		if level == SeverityInfo {
+			level = "note"
+		}
+
+		results = append(results, SARIFResult{
+			RuleID:  d.Rule,
+			Level:   level,
+			Message: SARIFMessage{Text: d.Message},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: d.File},
+					Region: SARIFRegion{
+						StartLine:   d.Line,
+						StartColumn: d.Column,
+						EndColumn:   d.EndColumn,
+					},
+				},
+			}},
+		})
+	}
+
+	return SARIFLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "toneclone-lint",
+				Version:        toolVersion,
+				InformationURI: "https://github.com/toneclone/cli",
+				Rules:          ruleDescriptions,
+			}},
+			Results: results,
+		}},
+	}
+}
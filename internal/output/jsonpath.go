@@ -0,0 +1,653 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPathExpr is a parsed JSONPath template in the style of kubectl:
+// literal text with {path} actions, {"literal"} strings and
+// {range path}...{end} loops. Paths support .field, ['field'], [n], [a:b],
+// [*], .*, ..field and filters such as [?(@.status=="active")].
+type JSONPathExpr struct {
+	nodes []jpNode
+}
+
+// jpNode is literal text, a path to print or a range over a path
+type jpNode struct {
+	text    string
+	path    []jpStep
+	isRange bool
+	body    []jpNode
+}
+
+type jpStepKind int
+
+const (
+	stepField jpStepKind = iota
+	stepWildcard
+	stepIndex
+	stepSlice
+	stepRecursive
+	stepFilter
+	stepRoot
+	stepCurrent
+)
+
+type jpStep struct {
+	kind  jpStepKind
+	field string
+	index int
+	// Slice bounds, when set
+	start, end       int
+	hasStart, hasEnd bool
+	filter           *jpFilter
+}
+
+// jpFilter compares a path relative to each element with a literal, or
+// checks that the path exists when op is empty
+type jpFilter struct {
+	path  []jpStep
+	op    string
+	value interface{}
+}
+
+// ParseJSONPath parses a JSONPath template. A bare path without braces,
+// such as .personas[*].name, is accepted too.
+func ParseJSONPath(template string) (*JSONPathExpr, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	nodes, _, err := parseNodes(template, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %q: %w", template, err)
+	}
+	return &JSONPathExpr{nodes: nodes}, nil
+}
+
+// parseNodes parses until the end of the template, or an {end} when in a
+// range, and returns what follows it
+func parseNodes(s string, inRange bool) ([]jpNode, string, error) {
+	var nodes []jpNode
+	for s != "" {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			nodes = append(nodes, jpNode{text: s})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jpNode{text: s[:open]})
+		}
+
+		close := matchingBrace(s, open)
+		if close < 0 {
+			return nil, "", fmt.Errorf("unclosed {")
+		}
+		action := strings.TrimSpace(s[open+1 : close])
+		s = s[close+1:]
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("{end} without {range}")
+			}
+			return nodes, s, nil
+		case strings.HasPrefix(action, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseNodes(s, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{isRange: true, path: path, body: body})
+			s = rest
+		case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, "'"):
+			text, err := unquote(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{text: text})
+		default:
+			path, err := parsePath(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jpNode{path: path})
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("{range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// matchingBrace returns the index of the } closing the { at open, skipping
+// quoted strings
+func matchingBrace(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	text, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return text, nil
+}
+
+// parsePath parses a path such as $.personas[0].name or @.status
+func parsePath(s string) ([]jpStep, error) {
+	var steps []jpStep
+	original := s
+
+	switch {
+	case strings.HasPrefix(s, "$"):
+		steps = append(steps, jpStep{kind: stepRoot})
+		s = s[1:]
+	case strings.HasPrefix(s, "@"):
+		steps = append(steps, jpStep{kind: stepCurrent})
+		s = s[1:]
+	case !strings.HasPrefix(s, ".") && !strings.HasPrefix(s, "["):
+		return nil, fmt.Errorf("path %q must start with ., [, $ or @", original)
+	}
+
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			steps = append(steps, jpStep{kind: stepRecursive})
+			s = s[2:]
+			// ..[0] and ..* need no field name
+			if strings.HasPrefix(s, "[") {
+				continue
+			}
+			name, rest := splitName(s)
+			if name == "*" {
+				steps = append(steps, jpStep{kind: stepWildcard})
+			} else if name != "" {
+				steps = append(steps, jpStep{kind: stepField, field: name})
+			} else {
+				return nil, fmt.Errorf("path %q has no field after ..", original)
+			}
+			s = rest
+		case strings.HasPrefix(s, "."):
+			name, rest := splitName(s[1:])
+			switch name {
+			case "":
+				// A lone . is the current value
+				if rest != "" && !strings.HasPrefix(rest, "[") {
+					return nil, fmt.Errorf("path %q has an empty field name", original)
+				}
+			case "*":
+				steps = append(steps, jpStep{kind: stepWildcard})
+			default:
+				steps = append(steps, jpStep{kind: stepField, field: name})
+			}
+			s = rest
+		case strings.HasPrefix(s, "["):
+			close := matchingBracket(s)
+			if close < 0 {
+				return nil, fmt.Errorf("path %q has an unclosed [", original)
+			}
+			step, err := parseBracket(strings.TrimSpace(s[1:close]))
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %w", original, err)
+			}
+			steps = append(steps, step)
+			s = s[close+1:]
+		default:
+			return nil, fmt.Errorf("path %q has unexpected %q", original, s)
+		}
+	}
+	return steps, nil
+}
+
+// splitName splits a field name from the rest of a path
+func splitName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// matchingBracket returns the index of the ] closing the [ at the start of
+// s, skipping quoted strings and nested brackets
+func matchingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseBracket parses the inside of [...]
+func parseBracket(s string) (jpStep, error) {
+	switch {
+	case s == "*":
+		return jpStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		name, err := unquote(s)
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: stepField, field: name}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		filter, err := parseFilter(strings.TrimSpace(s[2 : len(s)-1]))
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: stepFilter, filter: filter}, nil
+	case strings.Contains(s, ":"):
+		step := jpStep{kind: stepSlice}
+		parts := strings.SplitN(s, ":", 3)
+		if parts[0] = strings.TrimSpace(parts[0]); parts[0] != "" {
+			start, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return jpStep{}, fmt.Errorf("invalid slice start %q", parts[0])
+			}
+			step.start, step.hasStart = start, true
+		}
+		if parts[1] = strings.TrimSpace(parts[1]); parts[1] != "" {
+			end, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return jpStep{}, fmt.Errorf("invalid slice end %q", parts[1])
+			}
+			step.end, step.hasEnd = end, true
+		}
+		return step, nil
+	}
+
+	index, err := strconv.Atoi(s)
+	if err != nil {
+		return jpStep{}, fmt.Errorf("invalid index %q", s)
+	}
+	return jpStep{kind: stepIndex, index: index}, nil
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses @.path, or @.path OP literal
+func parseFilter(s string) (*jpFilter, error) {
+	for _, op := range filterOps {
+		left, right, found := cutOutsideQuotes(s, op)
+		if !found {
+			continue
+		}
+		path, err := parsePath(strings.TrimSpace(left))
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseLiteral(strings.TrimSpace(right))
+		if err != nil {
+			return nil, err
+		}
+		return &jpFilter{path: path, op: op, value: value}, nil
+	}
+
+	path, err := parsePath(s)
+	if err != nil {
+		return nil, err
+	}
+	return &jpFilter{path: path}, nil
+}
+
+// cutOutsideQuotes cuts s around the first sep not inside quotes
+func cutOutsideQuotes(s, sep string) (string, string, bool) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(s[i:], sep):
+			return s[:i], s[i+len(sep):], true
+		}
+	}
+	return s, "", false
+}
+
+// parseLiteral parses a quoted string, number, true, false or null
+func parseLiteral(s string) (interface{}, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`) {
+		return unquote(s)
+	}
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %q", s)
+	}
+	return number, nil
+}
+
+// Execute writes the template for data, a value decoded from JSON
+func (j *JSONPathExpr) Execute(w io.Writer, data interface{}) error {
+	var b strings.Builder
+	if err := executeNodes(&b, j.nodes, data, data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func executeNodes(b *strings.Builder, nodes []jpNode, root, current interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			values, err := evalPath(node.path, root, current)
+			if err != nil {
+				return err
+			}
+			// Ranging over a single list walks its items
+			if len(values) == 1 {
+				if list, ok := values[0].([]interface{}); ok {
+					values = list
+				}
+			}
+			for _, value := range values {
+				if err := executeNodes(b, node.body, root, value); err != nil {
+					return err
+				}
+			}
+		case node.path != nil:
+			values, err := evalPath(node.path, root, current)
+			if err != nil {
+				return err
+			}
+			for i, value := range values {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				text, err := formatValue(value)
+				if err != nil {
+					return err
+				}
+				b.WriteString(text)
+			}
+		default:
+			b.WriteString(node.text)
+		}
+	}
+	return nil
+}
+
+// formatValue prints strings and numbers as they are and the rest as JSON
+func formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case nil:
+		return "", nil
+	case bool, float64:
+		return fmt.Sprint(v), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to print jsonpath result: %w", err)
+	}
+	return string(data), nil
+}
+
+// evalPath returns the values a path selects. As in kubectl, a field or
+// index that none of the values it applies to has is an error.
+func evalPath(steps []jpStep, root, current interface{}) ([]interface{}, error) {
+	values := []interface{}{current}
+	for i := 0; i < len(steps); i++ {
+		step := steps[i]
+		var next []interface{}
+		switch step.kind {
+		case stepRoot:
+			next = []interface{}{root}
+		case stepCurrent:
+			next = values
+		case stepRecursive:
+			for _, value := range values {
+				next = append(next, descendants(value)...)
+			}
+		default:
+			for _, value := range values {
+				next = append(next, applyStep(step, root, value)...)
+			}
+			if len(next) == 0 && len(values) > 0 {
+				if err := missingError(step, values[0]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// missingError describes a field or index step that selected nothing
+func missingError(step jpStep, value interface{}) error {
+	switch step.kind {
+	case stepField:
+		return fmt.Errorf("%s is not found", step.field)
+	case stepIndex:
+		if list, ok := value.([]interface{}); ok {
+			return fmt.Errorf("array index out of bounds: index %d, length %d", step.index, len(list))
+		}
+		return fmt.Errorf("[%d] is not found: not an array", step.index)
+	}
+	return nil
+}
+
+// applyStep applies a field, wildcard, index, slice or filter step
+func applyStep(step jpStep, root, value interface{}) []interface{} {
+	switch step.kind {
+	case stepField:
+		if object, ok := value.(map[string]interface{}); ok {
+			if child, ok := object[step.field]; ok {
+				return []interface{}{child}
+			}
+		}
+	case stepWildcard:
+		return children(value)
+	case stepIndex:
+		if list, ok := value.([]interface{}); ok {
+			index := step.index
+			if index < 0 {
+				index += len(list)
+			}
+			if index >= 0 && index < len(list) {
+				return []interface{}{list[index]}
+			}
+		}
+	case stepSlice:
+		if list, ok := value.([]interface{}); ok {
+			start, end := 0, len(list)
+			if step.hasStart {
+				start = clampIndex(step.start, len(list))
+			}
+			if step.hasEnd {
+				end = clampIndex(step.end, len(list))
+			}
+			if start < end {
+				return append([]interface{}(nil), list[start:end]...)
+			}
+		}
+	case stepFilter:
+		var matched []interface{}
+		for _, child := range children(value) {
+			if step.filter.matches(root, child) {
+				matched = append(matched, child)
+			}
+		}
+		return matched
+	}
+	return nil
+}
+
+func clampIndex(index, length int) int {
+	if index < 0 {
+		index += length
+	}
+	return max(0, min(index, length))
+}
+
+// children returns the items of a list, or the values of an object in key
+// order
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, v[key])
+		}
+		return result
+	}
+	return nil
+}
+
+// descendants returns value and everything nested in it, depth first
+func descendants(value interface{}) []interface{} {
+	result := []interface{}{value}
+	for _, child := range children(value) {
+		result = append(result, descendants(child)...)
+	}
+	return result
+}
+
+func (f *jpFilter) matches(root, value interface{}) bool {
+	// A missing path does not match
+	found, _ := evalPath(f.path, root, value)
+	if f.op == "" {
+		return len(found) > 0
+	}
+	if len(found) == 0 {
+		return f.op == "!="
+	}
+	return compare(found[0], f.op, f.value)
+}
+
+// compare compares a JSON value with a filter literal
+func compare(left interface{}, op string, right interface{}) bool {
+	if number, ok := left.(json.Number); ok {
+		left, _ = number.Float64()
+	}
+
+	switch r := right.(type) {
+	case float64:
+		l, ok := left.(float64)
+		if !ok {
+			return op == "!="
+		}
+		switch op {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case ">":
+			return l > r
+		case "<=":
+			return l <= r
+		case ">=":
+			return l >= r
+		}
+	case string:
+		l, ok := left.(string)
+		if !ok {
+			return op == "!="
+		}
+		switch op {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case ">":
+			return l > r
+		case "<=":
+			return l <= r
+		case ">=":
+			return l >= r
+		}
+	default:
+		switch op {
+		case "==":
+			return left == right
+		case "!=":
+			return left != right
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+const jsonPathData = `{
+	"count": 3,
+	"personas": [
+		{"name": "Casual", "status": "active", "files": 2, "tags": {"tone": "relaxed"}},
+		{"name": "Formal", "status": "archived", "files": 10},
+		{"name": "Support", "status": "active", "files": 5, "id": 12345678901}
+	],
+	"user": {"email": "me@example.com", "name with space": "Me"}
+}`
+
+func TestJSONPath(t *testing.T) {
+	data, err := normalize(jsonRaw(jsonPathData))
+	if err != nil {
+		t.Fatalf("Failed to normalize: %v", err)
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{.count}", "3"},
+		{".count", "3"},
+		{"$.user.email", "me@example.com"},
+		{"{.user['name with space']}", "Me"},
+		{"{.personas[0].name}", "Casual"},
+		{"{.personas[-1].name}", "Support"},
+		{"{.personas[0:2].name}", "Casual Formal"},
+		{"{.personas[1:].name}", "Formal Support"},
+		{"{.personas[*].files}", "2 10 5"},
+		{"{.personas[2].id}", "12345678901"},
+		{"{.personas[0].tags}", `{"tone":"relaxed"}`},
+		{"{.user.*}", "me@example.com Me"},
+		{"{..tone}", "relaxed"},
+		{`{.personas[?(@.status=="active")].name}`, "Casual Support"},
+		{`{.personas[?(@.status != 'active')].name}`, "Formal"},
+		{"{.personas[?(@.files>=5)].name}", "Formal Support"},
+		{"{.personas[?(@.tags)].name}", "Casual"},
+		{"{.personas[*].id}", "12345678901"},
+		{`Total: {.count}{"\n"}`, "Total: 3\n"},
+		{`{range .personas[*]}{.name}={.files}{"\n"}{end}`, "Casual=2\nFormal=10\nSupport=5\n"},
+		{`{range .personas}[{.name}]{end}`, "[Casual][Formal][Support]"},
+		{`{range .personas[?(@.tags)]}{.name}:{range .tags.*}{@}{end};{end}`, "Casual:relaxed;"},
+		{`{range .personas[0:1]}{$.count} {.name}{end}`, "3 Casual"},
+	}
+
+	for _, test := range tests {
+		expr, err := ParseJSONPath(test.template)
+		if err != nil {
+			t.Errorf("ParseJSONPath(%q) failed: %v", test.template, err)
+			continue
+		}
+		var out bytes.Buffer
+		if err := expr.Execute(&out, data); err != nil {
+			t.Errorf("Execute(%q) failed: %v", test.template, err)
+			continue
+		}
+		if out.String() != test.expected {
+			t.Errorf("Template %q: expected %q, got %q", test.template, test.expected, out.String())
+		}
+	}
+}
+
+func TestJSONPathMissing(t *testing.T) {
+	data, err := normalize(jsonRaw(jsonPathData))
+	if err != nil {
+		t.Fatalf("Failed to normalize: %v", err)
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{.missing.field}", "missing is not found"},
+		{"{.personas[5].name}", "array index out of bounds: index 5, length 3"},
+		{"{.count[0]}", "[0] is not found: not an array"},
+		{`{range .personas[*]}{.tags.tone}{end}`, "tags is not found"},
+	}
+
+	for _, test := range tests {
+		expr, err := ParseJSONPath(test.template)
+		if err != nil {
+			t.Fatalf("ParseJSONPath(%q) failed: %v", test.template, err)
+		}
+		var out bytes.Buffer
+		if err := expr.Execute(&out, data); err == nil || err.Error() != test.expected {
+			t.Errorf("Template %q: expected error %q, got %v", test.template, test.expected, err)
+		}
+		if out.Len() > 0 {
+			t.Errorf("Template %q: expected no output on error, got %q", test.template, out.String())
+		}
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, template := range []string{
+		"{.a",
+		"{range .a}{.b}",
+		"{end}",
+		"{name}",
+		"{.a[}",
+		"{.a[x]}",
+		`{.a[?(@.b=="c)]}`,
+		`{.a[?(@.b==bare)]}`,
+		"{.a..}",
+	} {
+		if _, err := ParseJSONPath(template); err == nil {
+			t.Errorf("Expected error for %q", template)
+		}
+	}
+}
+
+// jsonRaw is JSON text that marshals as itself
+type jsonRaw string
+
+func (j jsonRaw) MarshalJSON() ([]byte, error) {
+	return []byte(j), nil
+}
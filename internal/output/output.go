@@ -0,0 +1,310 @@
+// Package output prints command results in the format chosen with -o:
+// tables, CSV, JSON, YAML, JSONPath expressions or Go templates.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Output formats. JSONPath and GoTemplate take an expression after an
+// equals sign, as in jsonpath={.personas[*].name}.
+const (
+	Table      = "table"
+	Wide       = "wide"
+	JSON       = "json"
+	YAML       = "yaml"
+	CSV        = "csv"
+	JSONPath   = "jsonpath"
+	GoTemplate = "go-template"
+)
+
+// FormatHelp lists the formats for flag help and errors
+const FormatHelp = "table, wide, json, yaml, csv, jsonpath=<expr> or go-template=<template>"
+
+// FlagError is an invalid -o or --columns value, as opposed to a failure to
+// print
+type FlagError struct {
+	Err error
+}
+
+func (e *FlagError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FlagError) Unwrap() error {
+	return e.Err
+}
+
+// flagErrorf returns a FlagError with a formatted message
+func flagErrorf(format string, args ...interface{}) error {
+	return &FlagError{Err: fmt.Errorf(format, args...)}
+}
+
+// Column is a column of table and CSV output
+type Column[T any] struct {
+	// Name selects the column with --columns, e.g. last_used
+	Name string
+	// Header defaults to the upper-cased name, e.g. LAST USED
+	Header string
+	// Wide columns are shown only with -o wide or when selected
+	Wide  bool
+	Value func(T) string
+}
+
+func (c Column[T]) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+	return strings.ToUpper(strings.ReplaceAll(c.Name, "_", " "))
+}
+
+// Printer prints results in one format
+type Printer struct {
+	Out       io.Writer
+	Format    string
+	NoHeaders bool
+	// Columns selects and orders the table and CSV columns by name
+	Columns []string
+	// Quiet prints only the id column, or the first column of tables
+	// without one, one value per line
+	Quiet bool
+
+	jsonPath *JSONPathExpr
+	template *template.Template
+}
+
+// NewPrinter parses an -o value such as json or jsonpath={.count}. An
+// empty format means table.
+func NewPrinter(out io.Writer, format string, noHeaders bool, columns []string) (*Printer, error) {
+	p := &Printer{Out: out, NoHeaders: noHeaders, Columns: columns}
+
+	name, expr, hasExpr := strings.Cut(format, "=")
+	switch name {
+	case "":
+		p.Format = Table
+	case Table, Wide, JSON, YAML, CSV:
+		if hasExpr {
+			return nil, flagErrorf("output format '%s' takes no expression", name)
+		}
+		p.Format = name
+	case JSONPath:
+		if expr == "" {
+			return nil, flagErrorf("jsonpath output requires an expression, e.g. jsonpath={.count}")
+		}
+		parsed, err := ParseJSONPath(expr)
+		if err != nil {
+			return nil, &FlagError{Err: err}
+		}
+		p.Format = name
+		p.jsonPath = parsed
+	case GoTemplate:
+		if expr == "" {
+			return nil, flagErrorf("go-template output requires a template, e.g. go-template={{.count}}")
+		}
+		parsed, err := template.New("output").Parse(expr)
+		if err != nil {
+			return nil, flagErrorf("invalid go-template: %w", err)
+		}
+		p.Format = name
+		p.template = parsed
+	default:
+		return nil, flagErrorf("unknown output format '%s' (use %s)", format, FormatHelp)
+	}
+
+	return p, nil
+}
+
+// Structured reports whether the format prints the result document rather
+// than columns
+func (p *Printer) Structured() bool {
+	if p.Quiet {
+		return false
+	}
+	switch p.Format {
+	case JSON, YAML, JSONPath, GoTemplate:
+		return true
+	}
+	return false
+}
+
+// Tabular reports whether the format is a table meant for people
+func (p *Printer) Tabular() bool {
+	return !p.Quiet && (p.Format == Table || p.Format == Wide)
+}
+
+// PrintData prints v as JSON, YAML or through the JSONPath expression or
+// template. Values are converted to JSON first, so every structured format
+// uses the same field names.
+func (p *Printer) PrintData(v interface{}) error {
+	switch p.Format {
+	case JSON:
+		encoder := json.NewEncoder(p.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case YAML:
+		data, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+		_, err = p.Out.Write(data)
+		return err
+	}
+
+	data, err := normalize(v)
+	if err != nil {
+		return err
+	}
+	switch p.Format {
+	case JSONPath:
+		return p.jsonPath.Execute(p.Out, data)
+	case GoTemplate:
+		if err := p.template.Execute(p.Out, data); err != nil {
+			return fmt.Errorf("failed to execute go-template: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("output format '%s' needs columns", p.Format)
+}
+
+// List prints rows as a table or CSV, or data in the structured formats
+func List[T any](p *Printer, data interface{}, rows []T, columns []Column[T]) error {
+	if p.Quiet {
+		return printIDs(p, rows, columns)
+	}
+	if p.Structured() {
+		return p.PrintData(data)
+	}
+
+	selected, err := selectColumns(p, columns)
+	if err != nil {
+		return err
+	}
+
+	if p.Format == CSV {
+		w := csv.NewWriter(p.Out)
+		if !p.NoHeaders {
+			var headers []string
+			for _, column := range selected {
+				headers = append(headers, column.header())
+			}
+			w.Write(headers)
+		}
+		for _, row := range rows {
+			var record []string
+			for _, column := range selected {
+				record = append(record, column.Value(row))
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(p.Out, 0, 0, 2, ' ', 0)
+	if !p.NoHeaders {
+		var headers, rules []string
+		for _, column := range selected {
+			headers = append(headers, column.header())
+			rules = append(rules, strings.Repeat("-", utf8.RuneCountInString(column.header())))
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+		fmt.Fprintln(w, strings.Join(rules, "\t"))
+	}
+	for _, row := range rows {
+		var values []string
+		for _, column := range selected {
+			values = append(values, column.Value(row))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
+
+// Object prints a single item. Tables use details, a free-form view, unless
+// columns were selected; CSV prints one row.
+func Object[T any](p *Printer, data interface{}, row T, columns []Column[T], details func() error) error {
+	if p.Tabular() && len(p.Columns) == 0 && details != nil {
+		return details()
+	}
+	return List(p, data, []T{row}, columns)
+}
+
+// printIDs prints the id column of rows, one value per line
+func printIDs[T any](p *Printer, rows []T, columns []Column[T]) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	id, ok := findColumn(columns, "id")
+	if !ok {
+		id = columns[0]
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(p.Out, id.Value(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectColumns returns the columns named by p.Columns, or the default
+// columns for the format
+func selectColumns[T any](p *Printer, columns []Column[T]) ([]Column[T], error) {
+	if len(p.Columns) == 0 {
+		var selected []Column[T]
+		for _, column := range columns {
+			if !column.Wide || p.Format != Table {
+				selected = append(selected, column)
+			}
+		}
+		return selected, nil
+	}
+
+	var selected []Column[T]
+	for _, name := range p.Columns {
+		column, ok := findColumn(columns, strings.TrimSpace(name))
+		if !ok {
+			var names []string
+			for _, column := range columns {
+				names = append(names, column.Name)
+			}
+			return nil, flagErrorf("unknown column '%s' (available: %s)", name, strings.Join(names, ", "))
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+// findColumn matches a column by name or header, ignoring case
+func findColumn[T any](columns []Column[T], name string) (Column[T], bool) {
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, name) || strings.EqualFold(column.header(), name) {
+			return column, true
+		}
+	}
+	return Column[T]{}, false
+}
+
+// normalize converts v to the generic JSON values seen by JSONPath and
+// templates. Numbers stay json.Number so large integers print in full.
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return result, nil
+}
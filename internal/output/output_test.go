@@ -0,0 +1,175 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags,omitempty"`
+	ID    string   `json:"id"`
+}
+
+var itemColumns = []Column[item]{
+	{Name: "name", Value: func(i item) string { return i.Name }},
+	{Name: "count", Value: func(i item) string { return strings.Repeat("*", i.Count) }},
+	{Name: "tags", Wide: true, Value: func(i item) string { return strings.Join(i.Tags, ",") }},
+	{Name: "id", Header: "ITEM ID", Value: func(i item) string { return i.ID }},
+}
+
+var items = []item{
+	{Name: "first", Count: 2, Tags: []string{"a", "b"}, ID: "i1"},
+	{Name: "second, with comma", Count: 1, ID: "i2"},
+}
+
+func printItems(t *testing.T, format string, noHeaders bool, columns ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	p, err := NewPrinter(&out, format, noHeaders, columns)
+	if err != nil {
+		t.Fatalf("NewPrinter(%q) failed: %v", format, err)
+	}
+	data := map[string]interface{}{"items": items, "count": len(items)}
+	if err := List(p, data, items, itemColumns); err != nil {
+		t.Fatalf("List failed for %q: %v", format, err)
+	}
+	return out.String()
+}
+
+func TestListFormats(t *testing.T) {
+	tests := []struct {
+		format    string
+		noHeaders bool
+		columns   []string
+		expected  string
+	}{
+		{"table", false, nil, "NAME                COUNT  ITEM ID\n" +
+			"----                -----  -------\n" +
+			"first               **     i1\n" +
+			"second, with comma  *      i2\n"},
+		{"", true, nil, "first               **  i1\nsecond, with comma  *   i2\n"},
+		{"wide", false, nil, "NAME                COUNT  TAGS  ITEM ID\n" +
+			"----                -----  ----  -------\n" +
+			"first               **     a,b   i1\n" +
+			"second, with comma  *            i2\n"},
+		{"table", false, []string{"ID", "tags"}, "ITEM ID  TAGS\n-------  ----\ni1       a,b\ni2       \n"},
+		{"table", false, []string{"item id"}, "ITEM ID\n-------\ni1\ni2\n"},
+		{"csv", false, nil, "NAME,COUNT,TAGS,ITEM ID\nfirst,**,\"a,b\",i1\n\"second, with comma\",*,,i2\n"},
+		{"csv", true, []string{"name"}, "first\n\"second, with comma\"\n"},
+		{"json", false, nil, `{
+  "count": 2,
+  "items": [
+    {
+      "name": "first",
+      "count": 2,
+      "tags": [
+        "a",
+        "b"
+      ],
+      "id": "i1"
+    },
+    {
+      "name": "second, with comma",
+      "count": 1,
+      "id": "i2"
+    }
+  ]
+}
+`},
+		{"yaml", false, nil, `count: 2
+items:
+    - name: first
+      count: 2
+      tags:
+        - a
+        - b
+      id: i1
+    - name: second, with comma
+      count: 1
+      id: i2
+`},
+		{"jsonpath={.items[*].name}", false, nil, "first second, with comma"},
+		{`jsonpath={range .items[*]}{.id}{"\t"}{.count}{"\n"}{end}`, false, nil, "i1\t2\ni2\t1\n"},
+		{"go-template={{range .items}}{{.name}}={{.count}};{{end}}", false, nil, "first=2;second, with comma=1;"},
+	}
+
+	for _, test := range tests {
+		if result := printItems(t, test.format, test.noHeaders, test.columns...); result != test.expected {
+			t.Errorf("Format %q columns %v:\nexpected:\n%s\ngot:\n%s", test.format, test.columns, test.expected, result)
+		}
+	}
+}
+
+func TestYAMLQuotesAmbiguousStrings(t *testing.T) {
+	var out bytes.Buffer
+	p, _ := NewPrinter(&out, "yaml", false, nil)
+	if err := p.PrintData(map[string]string{"id": "123", "flag": "true", "plain": "text"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "flag: \"true\"\nid: \"123\"\nplain: text\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestObject(t *testing.T) {
+	var out bytes.Buffer
+	details := func() error {
+		out.WriteString("details\n")
+		return nil
+	}
+
+	p, _ := NewPrinter(&out, "table", false, nil)
+	if err := Object(p, items[0], items[0], itemColumns, details); err != nil || out.String() != "details\n" {
+		t.Errorf("Expected details view, got %q (%v)", out.String(), err)
+	}
+
+	out.Reset()
+	p, _ = NewPrinter(&out, "table", true, []string{"id"})
+	if err := Object(p, items[0], items[0], itemColumns, details); err != nil || out.String() != "i1\n" {
+		t.Errorf("Expected selected column, got %q (%v)", out.String(), err)
+	}
+
+	out.Reset()
+	p, _ = NewPrinter(&out, "jsonpath={.name}", false, nil)
+	if err := Object(p, items[0], items[0], itemColumns, details); err != nil || out.String() != "first" {
+		t.Errorf("Expected jsonpath result, got %q (%v)", out.String(), err)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	var out bytes.Buffer
+	p, _ := NewPrinter(&out, "json", false, []string{"name"})
+	p.Quiet = true
+	if err := List(p, nil, items, itemColumns); err != nil || out.String() != "i1\ni2\n" {
+		t.Errorf("Expected IDs, got %q (%v)", out.String(), err)
+	}
+	if p.Tabular() || p.Structured() {
+		t.Error("Expected quiet output to be neither tabular nor structured")
+	}
+
+	out.Reset()
+	if err := Object(p, items[1], items[1], itemColumns[:2], nil); err != nil || out.String() != "second, with comma\n" {
+		t.Errorf("Expected first column without an id column, got %q (%v)", out.String(), err)
+	}
+}
+
+func TestPrinterErrors(t *testing.T) {
+	for _, format := range []string{"xml", "json=x", "jsonpath=", "jsonpath={.a", "go-template={{.a", "go-template="} {
+		var flagErr *FlagError
+		if _, err := NewPrinter(&bytes.Buffer{}, format, false, nil); !errors.As(err, &flagErr) {
+			t.Errorf("Expected flag error for format %q, got %v", format, err)
+		}
+	}
+
+	p, _ := NewPrinter(&bytes.Buffer{}, "table", false, []string{"missing"})
+	err := List(p, nil, items, itemColumns)
+	var flagErr *FlagError
+	if !errors.As(err, &flagErr) || !strings.Contains(err.Error(), "available: name, count, tags, id") {
+		t.Errorf("Expected unknown column error, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// toYAML converts v to YAML through JSON, keeping the JSON field names and
+// their order
+func toYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	// JSON is valid YAML, so decoding it keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	blockStyle(&node)
+
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return out, nil
+}
+
+// blockStyle drops the flow style and quoting left over from JSON, so the
+// YAML encoder picks the usual style for each node
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
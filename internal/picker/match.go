@@ -0,0 +1,73 @@
+package picker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Match reports whether the characters of query appear in label in order,
+// ignoring case, and scores the match. Consecutive characters and characters
+// starting a word score higher, so "tw" ranks "Technical Writer" above
+// "Throwaway". An empty query matches everything with a score of 0.
+func Match(query, label string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+	l := []rune(strings.ToLower(label))
+
+	score, qi, last := 0, 0, -1
+	for li := 0; li < len(l) && qi < len(q); li++ {
+		if l[li] != q[qi] {
+			continue
+		}
+		score++
+		if last >= 0 && li == last+1 {
+			score += 4
+		}
+		if li == 0 || !unicode.IsLetter(l[li-1]) && !unicode.IsDigit(l[li-1]) {
+			score += 3
+		}
+		if last >= 0 {
+			// Penalize gaps a little, so tighter matches win ties
+			score -= min(li-last-1, 3)
+		}
+		last = li
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, false
+	}
+	// Exact matches of the whole label come first
+	if len(l) == len(q) {
+		score += 10
+	}
+	return score, true
+}
+
+// Filter returns the indexes of the items matching query, best first. Items
+// with equal scores keep their order.
+func Filter(items []Item, query string) []int {
+	type match struct {
+		index int
+		score int
+	}
+
+	var matches []match
+	for i, item := range items {
+		if score, ok := Match(query, item.Label); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
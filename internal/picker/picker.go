@@ -0,0 +1,213 @@
+// Package picker lets the user choose an item from a list on a terminal,
+// typing to filter it fuzzily and moving with the arrow keys.
+package picker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/toneclone/cli/internal/terminal"
+)
+
+// ErrCanceled is returned when the user leaves the picker with Escape or
+// Ctrl-C
+var ErrCanceled = errors.New("selection canceled")
+
+// Item is an entry of a picker
+type Item struct {
+	Label  string // matched against what the user types
+	Detail string // shown after the label, such as an ID
+}
+
+// Picker is a fuzzy-filtered list. The terminal must be in raw mode while it
+// runs.
+type Picker struct {
+	Prompt string
+	Items  []Item
+	Height int  // item rows shown at once (default 10)
+	Width  int  // terminal columns, to truncate long rows (0 for no limit)
+	Plain  bool // no colors
+
+	query   []rune
+	matches []int
+	cursor  int // index in matches
+	offset  int // first match shown
+}
+
+// New returns a picker for items
+func New(prompt string, items []Item) *Picker {
+	return &Picker{Prompt: prompt, Items: items, Height: 10}
+}
+
+// Run shows the picker on out, reads keys from in and returns the index in
+// Items of the chosen item
+func (p *Picker) Run(in io.Reader, out io.Writer) (int, error) {
+	if len(p.Items) == 0 {
+		return 0, errors.New("nothing to choose from")
+	}
+	if p.Height <= 0 {
+		p.Height = 10
+	}
+	p.filter()
+
+	r := bufio.NewReader(in)
+	for {
+		p.render(out)
+
+		key, err := terminal.ReadKey(r)
+		if err != nil {
+			p.clear(out)
+			if err == io.EOF {
+				return 0, ErrCanceled
+			}
+			return 0, err
+		}
+
+		switch key.Code {
+		case terminal.KeyEnter:
+			if len(p.matches) == 0 {
+				continue
+			}
+			index := p.matches[p.cursor]
+			p.clear(out)
+			fmt.Fprintf(out, "%s %s\r\n", p.Prompt, p.Items[index].Label)
+			return index, nil
+		case terminal.KeyEscape, terminal.KeyCtrlC, terminal.KeyCtrlD:
+			p.clear(out)
+			return 0, ErrCanceled
+		case terminal.KeyUp:
+			p.move(-1)
+		case terminal.KeyDown, terminal.KeyTab:
+			p.move(1)
+		case terminal.KeyPageUp:
+			p.move(-p.Height)
+		case terminal.KeyPageDown:
+			p.move(p.Height)
+		case terminal.KeyHome:
+			p.move(-len(p.matches))
+		case terminal.KeyEnd:
+			p.move(len(p.matches))
+		case terminal.KeyBackspace:
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.filter()
+			}
+		case terminal.KeyCtrlU:
+			p.query = nil
+			p.filter()
+		case terminal.KeyCtrlW:
+			p.query = deleteWord(p.query)
+			p.filter()
+		case terminal.KeyRune:
+			p.query = append(p.query, key.Rune)
+			p.filter()
+		}
+	}
+}
+
+// filter matches the items against the query and moves to the best match
+func (p *Picker) filter() {
+	p.matches = Filter(p.Items, string(p.query))
+	p.cursor, p.offset = 0, 0
+}
+
+// move moves the cursor by delta, scrolling to keep it in view
+func (p *Picker) move(delta int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.cursor = max(0, min(len(p.matches)-1, p.cursor+delta))
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+p.Height {
+		p.offset = p.cursor - p.Height + 1
+	}
+}
+
+// render draws the prompt line, the visible matches and a count, leaving the
+// terminal cursor after the query. It starts on the prompt line, where the
+// previous render left the cursor.
+func (p *Picker) render(out io.Writer) {
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+
+	prompt := p.Prompt + " " + string(p.query)
+	b.WriteString(p.truncate(prompt))
+
+	rows := 0
+	end := min(len(p.matches), p.offset+p.Height)
+	for i := p.offset; i < end; i++ {
+		item := p.Items[p.matches[i]]
+		marker := "  "
+		if i == p.cursor {
+			marker = "> "
+		}
+		row := p.truncate(marker + item.Label + detailSeparator(item) + item.Detail)
+		if i == p.cursor && !p.Plain {
+			row = "\x1b[7m" + row + "\x1b[0m"
+		}
+		b.WriteString("\r\n" + row)
+		rows++
+	}
+
+	status := fmt.Sprintf("  %d/%d", len(p.matches), len(p.Items))
+	if !p.Plain {
+		status = "\x1b[2m" + status + "\x1b[0m"
+	}
+	b.WriteString("\r\n" + status)
+	rows++
+
+	// Back to the end of the query
+	fmt.Fprintf(&b, "\x1b[%dA\r", rows)
+	if width := len([]rune(prompt)); width > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", p.limit(width))
+	}
+
+	io.WriteString(out, b.String())
+}
+
+// clear erases the picker. Render leaves the cursor on the prompt line, so
+// clearing from there down removes every row.
+func (p *Picker) clear(out io.Writer) {
+	io.WriteString(out, "\r\x1b[J")
+}
+
+// truncate cuts s to the terminal width
+func (p *Picker) truncate(s string) string {
+	runes := []rune(s)
+	if p.Width <= 0 || len(runes) < p.Width {
+		return s
+	}
+	return string(runes[:p.Width-1])
+}
+
+// limit keeps a column inside the terminal
+func (p *Picker) limit(column int) int {
+	if p.Width > 0 && column >= p.Width {
+		return p.Width - 1
+	}
+	return column
+}
+
+// deleteWord removes the last word of query, as Ctrl-W does in a shell
+func deleteWord(query []rune) []rune {
+	end := len(query)
+	for end > 0 && query[end-1] == ' ' {
+		end--
+	}
+	for end > 0 && query[end-1] != ' ' {
+		end--
+	}
+	return query[:end]
+}
+
+func detailSeparator(item Item) string {
+	if item.Detail == "" {
+		return ""
+	}
+	return "  "
+}
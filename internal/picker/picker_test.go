@@ -0,0 +1,115 @@
+package picker
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var personas = []Item{
+	{Label: "Throwaway", Detail: "persona-1"},
+	{Label: "Technical Writer", Detail: "persona-2"},
+	{Label: "Casual", Detail: "builtin-casual"},
+	{Label: "Tech", Detail: "persona-3"},
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		label string
+		ok    bool
+	}{
+		{"", "anything", true},
+		{"tw", "Technical Writer", true},
+		{"TW", "technical writer", true},
+		{"wx", "Technical Writer", false},
+		{"casualx", "Casual", false},
+		{"émi", "Émile", true},
+	}
+	for _, test := range tests {
+		if _, ok := Match(test.query, test.label); ok != test.ok {
+			t.Errorf("Match(%q, %q) = %v, expected %v", test.query, test.label, ok, test.ok)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"", []int{0, 1, 2, 3}},
+		// Word starts beat scattered letters
+		{"tw", []int{1, 0}},
+		// An exact label beats a longer one
+		{"tech", []int{3, 1}},
+		{"zzz", []int{}},
+	}
+	for _, test := range tests {
+		if got := Filter(personas, test.query); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Filter(%q) = %v, expected %v", test.query, got, test.expected)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		err      error
+	}{
+		{"first item", "\r", 0, nil},
+		{"arrow down", "\x1b[B\x1b[B\r", 2, nil},
+		{"arrow down past the end", strings.Repeat("\x1b[B", 9) + "\r", 3, nil},
+		{"ctrl-n and ctrl-p", "\x0e\x0e\x10\r", 1, nil},
+		{"type to filter", "cas\r", 2, nil},
+		{"backspace", "casx\x7f\r", 2, nil},
+		{"enter without matches is ignored", "zz\r\x15tw\r", 1, nil},
+		{"ctrl-w deletes a word", "xyz\x17tech\r", 3, nil},
+		{"end key", "\x1b[F\r", 3, nil},
+		{"escape", "\x1b", 0, ErrCanceled},
+		{"ctrl-c", "ca\x03", 0, ErrCanceled},
+		{"end of input", "ca", 0, ErrCanceled},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		p := New("Select a persona:", personas)
+		p.Plain = true
+		index, err := p.Run(strings.NewReader(test.input), &out)
+		if !errors.Is(err, test.err) || (err == nil && index != test.expected) {
+			t.Errorf("%s: expected %d (%v), got %d (%v)", test.name, test.expected, test.err, index, err)
+		}
+		if strings.Contains(out.String(), "\x1b[7m") {
+			t.Errorf("%s: expected no colors in plain mode", test.name)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	var out bytes.Buffer
+	p := New("Pick:", personas)
+	p.Height = 2
+	p.Width = 20
+	p.Run(strings.NewReader("\x1b[B\x1b[B\r"), &out)
+
+	screens := strings.Split(out.String(), "\r\x1b[J")
+	// The last render before Enter shows the second and third items, with
+	// the third highlighted and long rows cut to the width
+	last := screens[len(screens)-2]
+	for _, expected := range []string{"Pick: ", "  Technical Writer ", "\x1b[7m> Casual  builtin-c\x1b[0m", "4/4"} {
+		if !strings.Contains(last, expected) {
+			t.Errorf("Expected %q in %q", expected, last)
+		}
+	}
+	if !strings.HasSuffix(out.String(), "Pick: Casual\r\n") {
+		t.Errorf("Expected the choice to stay on screen, got %q", out.String())
+	}
+
+	if _, err := New("Pick:", nil).Run(strings.NewReader("\r"), &out); err == nil {
+		t.Error("Expected an error without items")
+	}
+}
@@ -0,0 +1,362 @@
+// Package postprocess converts generated text into the formats expected by
+// downstream publishing tools.
+package postprocess
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/toneclone/cli/internal/textutil"
+)
+
+// Supported output formats
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatPlain    = "plain"
+	FormatEmail    = "email"
+)
+
+// Formats lists the supported formats in display order
+var Formats = []string{FormatMarkdown, FormatHTML, FormatPlain, FormatEmail}
+
+// Options controls post-processing
+type Options struct {
+	// Format is one of the Format* constants; empty leaves text untouched
+	Format string
+	// Wrap hard-wraps text output at this many columns; zero disables wrapping.
+	// HTML output is never wrapped.
+	Wrap int
+}
+
+// Apply converts text according to the options
+func Apply(text string, options Options) (string, error) {
+	var out string
+	switch options.Format {
+	case "":
+		out = text
+	case FormatMarkdown:
+		out = strings.TrimSpace(normalizeNewlines(text)) + "\n"
+	case FormatPlain:
+		out = StripMarkdown(text)
+	case FormatHTML:
+		return RenderHTML(text), nil
+	case FormatEmail:
+		out = Email(text)
+	default:
+		return "", fmt.Errorf("unsupported format '%s' (use %s)", options.Format, strings.Join(Formats, ", "))
+	}
+
+	if options.Wrap > 0 {
+		out = wrapPreservingFences(out, options.Wrap)
+	}
+
+	return out, nil
+}
+
+var (
+	headingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletRe      = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedRe     = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	linkRe        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	imageRe       = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	boldRe        = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	italicStarRe  = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*?)\*`)
+	italicUnderRe = regexp.MustCompile(`(^|[^_\w])_([^_\s][^_]*?)_`)
+	codeRe        = regexp.MustCompile("`([^`]+)`")
+	quoteRe       = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	ruleRe        = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	subjectRe     = regexp.MustCompile(`(?i)^\s*subject\s*:\s*(.+)$`)
+	urlSchemeRe   = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+	// wrapPrefixRe matches what starts a line and must stay in front of it
+	// when wrapped: indentation, quote markers and a list marker
+	wrapPrefixRe = regexp.MustCompile(`^(\s*(?:>\s?)*)((?:[-*+]|\d+[.)])\s+)?`)
+)
+
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
+
+// StripMarkdown removes Markdown syntax, leaving readable plain text.
+// Links are rendered as "text (url)".
+func StripMarkdown(text string) string {
+	var out []string
+	inFence := false
+
+	for _, line := range strings.Split(normalizeNewlines(text), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+
+		if ruleRe.MatchString(line) {
+			out = append(out, "")
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			line = m[2]
+		} else if m := quoteRe.FindStringSubmatch(line); m != nil {
+			line = m[1]
+		} else if m := bulletRe.FindStringSubmatch(line); m != nil {
+			line = m[1] + "- " + m[2]
+		}
+
+		out = append(out, stripInline(line))
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n")) + "\n"
+}
+
+func stripInline(line string) string {
+	line = imageRe.ReplaceAllString(line, "$1")
+	line = linkRe.ReplaceAllStringFunc(line, func(m string) string {
+		parts := linkRe.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return parts[1]
+		}
+		return fmt.Sprintf("%s (%s)", parts[1], parts[2])
+	})
+	line = boldRe.ReplaceAllString(line, "$2")
+	line = italicStarRe.ReplaceAllString(line, "$1$2")
+	line = italicUnderRe.ReplaceAllString(line, "$1$2")
+	line = codeRe.ReplaceAllString(line, "$1")
+	return line
+}
+
+// RenderHTML renders a practical subset of Markdown to HTML: headings,
+// paragraphs, lists, block quotes, code blocks, rules and inline emphasis,
+// code and links. All text content is HTML-escaped.
+func RenderHTML(text string) string {
+	var b strings.Builder
+	var paragraph []string
+	listTag := ""
+	inFence := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			b.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			b.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for _, line := range strings.Split(normalizeNewlines(text), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inFence {
+				b.WriteString("</code></pre>\n")
+			} else {
+				flushParagraph()
+				closeList()
+				b.WriteString("<pre><code>")
+			}
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case ruleRe.MatchString(line):
+			flushParagraph()
+			closeList()
+			b.WriteString("<hr>\n")
+		case headingRe.MatchString(line):
+			flushParagraph()
+			closeList()
+			m := headingRe.FindStringSubmatch(line)
+			level := len(m[1])
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, renderInline(m[2]), level)
+		case bulletRe.MatchString(line):
+			flushParagraph()
+			openList("ul")
+			b.WriteString("<li>" + renderInline(bulletRe.FindStringSubmatch(line)[2]) + "</li>\n")
+		case orderedRe.MatchString(line):
+			flushParagraph()
+			openList("ol")
+			b.WriteString("<li>" + renderInline(orderedRe.FindStringSubmatch(line)[3]) + "</li>\n")
+		case quoteRe.MatchString(line):
+			flushParagraph()
+			closeList()
+			b.WriteString("<blockquote>" + renderInline(quoteRe.FindStringSubmatch(line)[1]) + "</blockquote>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, renderInline(trimmed))
+		}
+	}
+
+	if inFence {
+		b.WriteString("</code></pre>\n")
+	}
+	flushParagraph()
+	closeList()
+
+	return b.String()
+}
+
+func renderInline(text string) string {
+	// Escape first; Markdown punctuation is unaffected by HTML escaping
+	text = html.EscapeString(text)
+	text = codeRe.ReplaceAllString(text, "<code>$1</code>")
+	text = imageRe.ReplaceAllStringFunc(text, func(image string) string {
+		m := imageRe.FindStringSubmatch(image)
+		if !safeURL(m[2]) {
+			return m[1]
+		}
+		return `<img src="` + m[2] + `" alt="` + m[1] + `">`
+	})
+	text = linkRe.ReplaceAllStringFunc(text, func(link string) string {
+		m := linkRe.FindStringSubmatch(link)
+		if !safeURL(m[2]) {
+			return m[1]
+		}
+		return `<a href="` + m[2] + `">` + m[1] + `</a>`
+	})
+	text = boldRe.ReplaceAllString(text, "<strong>$2</strong>")
+	text = italicStarRe.ReplaceAllString(text, "$1<em>$2</em>")
+	text = italicUnderRe.ReplaceAllString(text, "$1<em>$2</em>")
+	return text
+}
+
+// safeURL reports whether a link target may be emitted in HTML: relative
+// URLs and the http, https and mailto schemes. Others, such as javascript:,
+// would run or load something when clicked.
+func safeURL(url string) bool {
+	scheme := urlSchemeRe.FindStringSubmatch(url)
+	if scheme == nil {
+		return true
+	}
+	switch strings.ToLower(scheme[1]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// DetectSubject finds an email subject line in generated text. It recognizes
+// an explicit "Subject:" line or a leading Markdown heading, and returns the
+// subject and the remaining body. If no subject is found the subject is
+// empty and the body is the original text.
+func DetectSubject(text string) (string, string) {
+	lines := strings.Split(normalizeNewlines(strings.TrimSpace(text)), "\n")
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		// Only the first few non-blank lines are considered
+		if i > 3 {
+			break
+		}
+		if m := subjectRe.FindStringSubmatch(line); m != nil {
+			rest := append(append([]string{}, lines[:i]...), lines[i+1:]...)
+			return stripInline(strings.TrimSpace(m[1])), strings.TrimSpace(strings.Join(rest, "\n"))
+		}
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil && i < len(lines)-1 {
+			return stripInline(m[2]), strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		}
+		break
+	}
+
+	return "", strings.TrimSpace(text)
+}
+
+// Email formats text as a plain-text email, with a "Subject:" header when a
+// subject line can be detected.
+func Email(text string) string {
+	subject, body := DetectSubject(text)
+	body = StripMarkdown(body)
+	if subject == "" {
+		return body
+	}
+	return "Subject: " + subject + "\n\n" + body
+}
+
+// wrapPreservingFences wraps text line by line, leaving fenced code blocks
+// and tables intact. List items and quotes keep their markers, and their
+// continuation lines are indented under the text.
+func wrapPreservingFences(text string, width int) string {
+	var out []string
+	inFence := false
+
+	trailingNewline := strings.HasSuffix(text, "\n")
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence || strings.HasPrefix(trimmed, "|") {
+			out = append(out, line)
+			continue
+		}
+		out = append(out, wrapLine(line, width)...)
+	}
+
+	result := strings.Join(out, "\n")
+	if trailingNewline {
+		result += "\n"
+	}
+	return result
+}
+
+// wrapLine wraps one line, repeating its indentation and quote markers on
+// every row and indenting rows after a list marker to line up with the text
+func wrapLine(line string, width int) []string {
+	m := wrapPrefixRe.FindStringSubmatch(line)
+	lead, marker := m[1], m[2]
+	content := line[len(m[0]):]
+	if strings.TrimSpace(content) == "" {
+		return []string{strings.TrimRight(line, " ")}
+	}
+
+	first := lead + marker
+	rest := lead + strings.Repeat(" ", utf8.RuneCountInString(marker))
+	available := width - utf8.RuneCountInString(first)
+	if available < 1 {
+		// No room left after the prefix; keep the line as it is
+		return []string{line}
+	}
+
+	rows := textutil.Wrap(content, available)
+	for i := range rows {
+		if i == 0 {
+			rows[i] = first + rows[i]
+		} else {
+			rows[i] = rest + rows[i]
+		}
+	}
+	return rows
+}
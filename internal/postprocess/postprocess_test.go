@@ -0,0 +1,133 @@
+package postprocess
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `# Launch Update
+
+We shipped **dark mode** and _faster_ sync. See [the docs](https://example.com/docs).
+
+- One <thing>
+- Two
+
+` + "```" + `
+code & stuff
+` + "```"
+
+func TestStripMarkdown(t *testing.T) {
+	got := StripMarkdown(sample)
+
+	for _, unwanted := range []string{"#", "**", "_faster_", "[the docs]", "```"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Expected %q to be stripped, got:\n%s", unwanted, got)
+		}
+	}
+
+	for _, wanted := range []string{"Launch Update", "dark mode", "the docs (https://example.com/docs)", "- One <thing>", "code & stuff"} {
+		if !strings.Contains(got, wanted) {
+			t.Errorf("Expected output to contain %q, got:\n%s", wanted, got)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	got := RenderHTML(sample)
+
+	for _, wanted := range []string{
+		"<h1>Launch Update</h1>",
+		"<strong>dark mode</strong>",
+		"<em>faster</em>",
+		`<a href="https://example.com/docs">the docs</a>`,
+		"<li>One &lt;thing&gt;</li>",
+		"<pre><code>code &amp; stuff\n</code></pre>",
+	} {
+		if !strings.Contains(got, wanted) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", wanted, got)
+		}
+	}
+}
+
+func TestRenderHTMLUnsafeLinks(t *testing.T) {
+	got := RenderHTML("[click](javascript:alert(1)) [mail](mailto:a@b.test) ![x](JavaScript:y) [rel](/docs)")
+
+	if strings.Contains(strings.ToLower(got), "javascript:") {
+		t.Errorf("Expected javascript: links to be dropped, got:\n%s", got)
+	}
+	for _, wanted := range []string{"click", `<a href="mailto:a@b.test">mail</a>`, `<a href="/docs">rel</a>`} {
+		if !strings.Contains(got, wanted) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", wanted, got)
+		}
+	}
+}
+
+func TestDetectSubject(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		subject string
+		body    string
+	}{
+		{
+			name:    "explicit subject line",
+			text:    "Subject: Quarterly update\n\nHi team,\nAll good.",
+			subject: "Quarterly update",
+			body:    "Hi team,\nAll good.",
+		},
+		{
+			name:    "heading as subject",
+			text:    "## **Welcome aboard**\nHi there",
+			subject: "Welcome aboard",
+			body:    "Hi there",
+		},
+		{
+			name:    "no subject",
+			text:    "Hi there,\nThanks!",
+			subject: "",
+			body:    "Hi there,\nThanks!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, body := DetectSubject(tt.text)
+			if subject != tt.subject {
+				t.Errorf("Expected subject %q, got %q", tt.subject, subject)
+			}
+			if body != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
+
+func TestApplyWrap(t *testing.T) {
+	got, err := Apply("one two three four five six", Options{Format: FormatPlain, Wrap: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got != "one two\nthree four\nfive six\n" {
+		t.Errorf("Unexpected wrapped output: %q", got)
+	}
+}
+
+func TestApplyWrapKeepsStructure(t *testing.T) {
+	text := "Intro text here.\n\n- first item is long\n- second\n  1. nested item words\n> quoted text that wraps\n\n| a | b | c | d |\n"
+	got, err := Apply(text, Options{Format: FormatMarkdown, Wrap: 14})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Intro text\nhere.\n\n- first item\n  is long\n- second\n  1. nested\n     item\n     words\n> quoted text\n> that wraps\n\n| a | b | c | d |\n"
+	if got != expected {
+		t.Errorf("Unexpected wrapped output:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestApplyUnsupportedFormat(t *testing.T) {
+	if _, err := Apply("text", Options{Format: "rtf"}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,166 @@
+// Package ranking scores generated text candidates locally so the CLI can
+// pick the best of several generations without another API round trip.
+package ranking
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/toneclone/cli/internal/textutil"
+)
+
+// Options controls which scoring criteria are applied.
+// Criteria left at their zero value are disabled.
+type Options struct {
+	// TargetWords rewards candidates whose word count is close to this value
+	TargetWords int
+	// TargetGrade rewards candidates whose Flesch-Kincaid grade is close to this value
+	TargetGrade float64
+	// BannedPhrases penalizes candidates containing any of these phrases (case-insensitive)
+	BannedPhrases []string
+	// Samples rewards candidates whose vocabulary is similar to these writing samples
+	Samples []string
+}
+
+// Enabled reports whether any scoring criterion is configured
+func (o Options) Enabled() bool {
+	return o.TargetWords > 0 || o.TargetGrade > 0 || len(o.BannedPhrases) > 0 || len(o.Samples) > 0
+}
+
+// Scores holds the per-criterion scores of a candidate, each in the range [0, 1].
+// Disabled criteria are omitted.
+type Scores struct {
+	Length      *float64 `json:"length,omitempty"`
+	Readability *float64 `json:"readability,omitempty"`
+	Banned      *float64 `json:"banned,omitempty"`
+	Similarity  *float64 `json:"similarity,omitempty"`
+}
+
+// Result is a scored candidate
+type Result struct {
+	Rank        int      `json:"rank"`
+	Index       int      `json:"index"`
+	Text        string   `json:"text"`
+	Score       float64  `json:"score"`
+	Scores      Scores   `json:"scores"`
+	Words       int      `json:"words"`
+	Grade       float64  `json:"grade"`
+	BannedFound []string `json:"banned_found,omitempty"`
+}
+
+// Scorer scores candidates against a fixed set of options
+type Scorer struct {
+	options      Options
+	sampleVector map[string]float64
+}
+
+// NewScorer creates a scorer, precomputing the sample vocabulary
+func NewScorer(options Options) *Scorer {
+	scorer := &Scorer{options: options}
+	if len(options.Samples) > 0 {
+		scorer.sampleVector = termFrequencies(strings.Join(options.Samples, "\n"))
+	}
+	return scorer
+}
+
+// Score evaluates a single candidate. The overall score is the mean of the
+// enabled criteria, or 1 when no criteria are enabled.
+func (s *Scorer) Score(index int, text string) Result {
+	result := Result{
+		Index: index,
+		Text:  text,
+		Words: len(textutil.Words(text)),
+		Grade: math.Round(textutil.ReadingGrade(text)*10) / 10,
+	}
+
+	var total float64
+	var criteria int
+	add := func(value float64) *float64 {
+		total += value
+		criteria++
+		return &value
+	}
+
+	if s.options.TargetWords > 0 {
+		diff := math.Abs(float64(result.Words - s.options.TargetWords))
+		result.Scores.Length = add(1 - math.Min(1, diff/float64(s.options.TargetWords)))
+	}
+
+	if s.options.TargetGrade > 0 {
+		// Being ten grades off is as bad as it gets
+		diff := math.Abs(textutil.ReadingGrade(text) - s.options.TargetGrade)
+		result.Scores.Readability = add(1 - math.Min(1, diff/10))
+	}
+
+	if len(s.options.BannedPhrases) > 0 {
+		lower := strings.ToLower(text)
+		hits := 0
+		for _, phrase := range s.options.BannedPhrases {
+			phrase = strings.ToLower(strings.TrimSpace(phrase))
+			if phrase == "" {
+				continue
+			}
+			if n := strings.Count(lower, phrase); n > 0 {
+				hits += n
+				result.BannedFound = append(result.BannedFound, phrase)
+			}
+		}
+		result.Scores.Banned = add(1 / float64(1+hits))
+	}
+
+	if s.sampleVector != nil {
+		result.Scores.Similarity = add(cosineSimilarity(termFrequencies(text), s.sampleVector))
+	}
+
+	if criteria == 0 {
+		result.Score = 1
+	} else {
+		result.Score = total / float64(criteria)
+	}
+
+	return result
+}
+
+// Rank scores all candidates and returns them best first.
+// Ties keep the original generation order.
+func (s *Scorer) Rank(candidates []string) []Result {
+	results := make([]Result, len(candidates))
+	for i, text := range candidates {
+		results[i] = s.Score(i, text)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+
+	return results
+}
+
+func termFrequencies(text string) map[string]float64 {
+	frequencies := make(map[string]float64)
+	for _, word := range textutil.Words(text) {
+		frequencies[strings.ToLower(word)]++
+	}
+	return frequencies
+}
+
+func cosineSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
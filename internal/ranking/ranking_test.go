@@ -0,0 +1,68 @@
+package ranking
+
+import (
+	"testing"
+)
+
+func TestRankWithoutCriteriaKeepsOrder(t *testing.T) {
+	scorer := NewScorer(Options{})
+	results := scorer.Rank([]string{"first", "second", "third"})
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("Expected candidate %d at rank %d, got %d", i, i+1, result.Index)
+		}
+		if result.Score != 1 {
+			t.Errorf("Expected score 1 without criteria, got %f", result.Score)
+		}
+	}
+}
+
+func TestRankByTargetWords(t *testing.T) {
+	scorer := NewScorer(Options{TargetWords: 5})
+	results := scorer.Rank([]string{
+		"one two",
+		"one two three four five",
+		"one two three four five six seven eight nine ten eleven",
+	})
+
+	if results[0].Index != 1 {
+		t.Errorf("Expected the five-word candidate to rank first, got index %d", results[0].Index)
+	}
+
+	if results[0].Scores.Length == nil || *results[0].Scores.Length != 1 {
+		t.Errorf("Expected a perfect length score for the best candidate")
+	}
+
+	if results[0].Scores.Readability != nil {
+		t.Error("Expected readability score to be omitted when not enabled")
+	}
+}
+
+func TestRankBannedPhrases(t *testing.T) {
+	scorer := NewScorer(Options{BannedPhrases: []string{"synergy", "circle back"}})
+	results := scorer.Rank([]string{
+		"Let's circle back on the Synergy plan.",
+		"Let's review the plan tomorrow.",
+	})
+
+	if results[0].Index != 1 {
+		t.Errorf("Expected the clean candidate to rank first, got index %d", results[0].Index)
+	}
+
+	if len(results[1].BannedFound) != 2 {
+		t.Errorf("Expected 2 banned phrases found, got %v", results[1].BannedFound)
+	}
+}
+
+func TestRankSimilarity(t *testing.T) {
+	scorer := NewScorer(Options{Samples: []string{"Cheers mate, grab a coffee and we'll sort the deploy."}})
+	results := scorer.Rank([]string{
+		"Dear Sir or Madam, please find attached the quarterly report.",
+		"Cheers mate, let's sort the deploy after coffee.",
+	})
+
+	if results[0].Index != 1 {
+		t.Errorf("Expected the candidate closest to the samples to rank first, got index %d", results[0].Index)
+	}
+}
@@ -0,0 +1,147 @@
+// Package terminal reads keys from and draws screens on a terminal in raw
+// mode, and emulates one for tests.
+package terminal
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a key that is not a printable character
+type KeyCode int
+
+// Keys decoded by ReadKey
+const (
+	KeyRune KeyCode = iota // a printable character, in Key.Rune
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyTab
+	KeyCtrlC
+	KeyCtrlD
+	KeyCtrlS
+	KeyCtrlU
+	KeyCtrlW
+	KeyDelete
+	KeyUnknown
+)
+
+// Key is a key press read from a terminal in raw mode
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ReadKey reads one key press. An escape byte with nothing buffered after it
+// is the Escape key; otherwise it starts an escape sequence such as an arrow.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 0x7f, 0x08:
+		return Key{Code: KeyBackspace}, nil
+	case '\t':
+		return Key{Code: KeyTab}, nil
+	case 0x03:
+		return Key{Code: KeyCtrlC}, nil
+	case 0x04:
+		return Key{Code: KeyCtrlD}, nil
+	case 0x0e: // Ctrl-N
+		return Key{Code: KeyDown}, nil
+	case 0x10: // Ctrl-P
+		return Key{Code: KeyUp}, nil
+	case 0x13:
+		return Key{Code: KeyCtrlS}, nil
+	case 0x15:
+		return Key{Code: KeyCtrlU}, nil
+	case 0x17:
+		return Key{Code: KeyCtrlW}, nil
+	case 0x1b:
+		if r.Buffered() == 0 {
+			return Key{Code: KeyEscape}, nil
+		}
+		return readEscapeSequence(r)
+	}
+
+	if b < 0x20 {
+		return Key{Code: KeyUnknown}, nil
+	}
+	if b < utf8.RuneSelf {
+		return Key{Code: KeyRune, Rune: rune(b)}, nil
+	}
+
+	r.UnreadByte()
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{Code: KeyRune, Rune: ch}, nil
+}
+
+// readEscapeSequence decodes the CSI and SS3 sequences terminals send for
+// arrows, Home, End, Page Up and Page Down
+func readEscapeSequence(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		// Alt+key; the key itself is ignored
+		return Key{Code: KeyUnknown}, nil
+	}
+
+	// Parameters, then a final byte in @..~
+	var params []byte
+	for {
+		b, err = r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+		params = append(params, b)
+	}
+
+	switch b {
+	case 'A':
+		return Key{Code: KeyUp}, nil
+	case 'B':
+		return Key{Code: KeyDown}, nil
+	case 'C':
+		return Key{Code: KeyRight}, nil
+	case 'D':
+		return Key{Code: KeyLeft}, nil
+	case 'H':
+		return Key{Code: KeyHome}, nil
+	case 'F':
+		return Key{Code: KeyEnd}, nil
+	case '~':
+		switch string(params) {
+		case "1", "7":
+			return Key{Code: KeyHome}, nil
+		case "3":
+			return Key{Code: KeyDelete}, nil
+		case "4", "8":
+			return Key{Code: KeyEnd}, nil
+		case "5":
+			return Key{Code: KeyPageUp}, nil
+		case "6":
+			return Key{Code: KeyPageDown}, nil
+		}
+	}
+	return Key{Code: KeyUnknown}, nil
+}
@@ -0,0 +1,32 @@
+package terminal
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a\x1b[A\x1bOB\x1b[5~\x1b[6~\x1b[1~\x1b[4~\x1bxé\x13\x1b[3~\r\x1b"))
+	expected := []Key{
+		{Code: KeyRune, Rune: 'a'},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyPageUp},
+		{Code: KeyPageDown},
+		{Code: KeyHome},
+		{Code: KeyEnd},
+		{Code: KeyUnknown},
+		{Code: KeyRune, Rune: 'é'},
+		{Code: KeyCtrlS},
+		{Code: KeyDelete},
+		{Code: KeyEnter},
+		{Code: KeyEscape},
+	}
+	for i, want := range expected {
+		key, err := ReadKey(r)
+		if err != nil || key != want {
+			t.Errorf("Key %d: expected %+v, got %+v (%v)", i, want, key, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Style is a combination of text attributes
+type Style uint8
+
+// Text attributes
+const (
+	Bold Style = 1 << iota
+	Dim
+	Reverse
+	Underline
+)
+
+// sgr returns the escape sequence selecting style
+func (s Style) sgr() string {
+	codes := []string{"0"}
+	if s&Bold != 0 {
+		codes = append(codes, "1")
+	}
+	if s&Dim != 0 {
+		codes = append(codes, "2")
+	}
+	if s&Underline != 0 {
+		codes = append(codes, "4")
+	}
+	if s&Reverse != 0 {
+		codes = append(codes, "7")
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// cell is a character on the screen
+type cell struct {
+	ch    rune
+	style Style
+}
+
+// Screen is a grid of styled characters, drawn off screen and then written
+// to the terminal in one go
+type Screen struct {
+	Width  int
+	Height int
+	// Plain drops styles when rendering, for NO_COLOR
+	Plain bool
+
+	cells []cell
+}
+
+// NewScreen returns a blank screen of width columns and height rows
+func NewScreen(width, height int) *Screen {
+	s := &Screen{Width: width, Height: height, cells: make([]cell, width*height)}
+	s.Clear()
+	return s
+}
+
+// Clear blanks the screen
+func (s *Screen) Clear() {
+	for i := range s.cells {
+		s.cells[i] = cell{ch: ' '}
+	}
+}
+
+// Text writes text at column x of row y, clipped to width columns and the
+// screen. It returns the number of columns written.
+func (s *Screen) Text(x, y, width int, text string, style Style) int {
+	if y < 0 || y >= s.Height {
+		return 0
+	}
+	written := 0
+	for _, ch := range text {
+		if written >= width || x+written >= s.Width {
+			break
+		}
+		if ch == '\t' || ch == '\n' || ch < ' ' {
+			ch = ' '
+		}
+		if x+written >= 0 {
+			s.cells[y*s.Width+x+written] = cell{ch: ch, style: style}
+		}
+		written++
+	}
+	return written
+}
+
+// Fill sets width columns of row y, from column x, to style, keeping their
+// characters. It highlights whole rows.
+func (s *Screen) Fill(x, y, width int, style Style) {
+	if y < 0 || y >= s.Height {
+		return
+	}
+	for i := max(x, 0); i < min(x+width, s.Width); i++ {
+		s.cells[y*s.Width+i].style = style
+	}
+}
+
+// Row returns the characters of row y, without trailing spaces
+func (s *Screen) Row(y int) string {
+	runes := make([]rune, s.Width)
+	for i := range runes {
+		runes[i] = s.cells[y*s.Width+i].ch
+	}
+	return strings.TrimRight(string(runes), " ")
+}
+
+// String returns the characters of the screen, one row per line
+func (s *Screen) String() string {
+	rows := make([]string, s.Height)
+	for y := range rows {
+		rows[y] = s.Row(y)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// Render writes the whole screen to a terminal, from the top left corner
+func (s *Screen) Render(w io.Writer) error {
+	var b strings.Builder
+	for y := 0; y < s.Height; y++ {
+		fmt.Fprintf(&b, "\x1b[%d;1H", y+1)
+		current := Style(0)
+		for x := 0; x < s.Width; x++ {
+			c := s.cells[y*s.Width+x]
+			if !s.Plain && c.style != current {
+				b.WriteString(c.style.sgr())
+				current = c.style
+			}
+			b.WriteRune(c.ch)
+		}
+		if current != 0 {
+			b.WriteString("\x1b[0m")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
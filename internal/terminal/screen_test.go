@@ -0,0 +1,57 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestScreenText(t *testing.T) {
+	s := NewScreen(10, 3)
+	if n := s.Text(2, 0, 20, "hello world", 0); n != 8 {
+		t.Errorf("Expected 8 columns written, got %d", n)
+	}
+	s.Text(0, 1, 3, "a\tbcdef", Bold)
+	s.Text(0, 5, 10, "off screen", 0)
+
+	expected := "  hello wo\na b\n"
+	if got := s.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	s.Clear()
+	if got := s.String(); got != "\n\n" {
+		t.Errorf("Expected a blank screen, got %q", got)
+	}
+}
+
+func TestScreenRender(t *testing.T) {
+	s := NewScreen(6, 2)
+	s.Text(0, 0, 6, "ab", 0)
+	s.Text(0, 1, 6, "cd", 0)
+	s.Fill(0, 1, 6, Reverse)
+
+	var out bytes.Buffer
+	if err := s.Render(&out); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, expected := range []string{"\x1b[1;1Hab    ", "\x1b[2;1H\x1b[0;7mcd    \x1b[0m"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in %q", expected, out.String())
+		}
+	}
+
+	out.Reset()
+	s.Plain = true
+	s.Render(&out)
+	if strings.Contains(out.String(), "m") {
+		t.Errorf("Expected no styles in plain mode, got %q", out.String())
+	}
+
+	// Rendering onto a virtual terminal reproduces the screen
+	vt := NewVirtualTerminal(6, 2)
+	s.Render(vt)
+	if vt.String() != s.String() {
+		t.Errorf("Expected %q, got %q", s.String(), vt.String())
+	}
+}
@@ -0,0 +1,213 @@
+package terminal
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// VirtualTerminal is an in-memory terminal for tests. It keeps a grid of
+// characters up to date from the escape sequences the picker and the
+// dashboard write: cursor movement, erasing and line feeds. Styles and
+// modes are accepted and ignored.
+type VirtualTerminal struct {
+	width  int
+	height int
+
+	mu      sync.Mutex
+	grid    [][]rune
+	x, y    int
+	pending []byte // an incomplete escape sequence or character
+}
+
+// NewVirtualTerminal returns a blank terminal of width columns and height rows
+func NewVirtualTerminal(width, height int) *VirtualTerminal {
+	vt := &VirtualTerminal{width: width, height: height, grid: make([][]rune, height)}
+	for y := range vt.grid {
+		vt.grid[y] = blankRow(width)
+	}
+	return vt
+}
+
+// Size returns the terminal size
+func (vt *VirtualTerminal) Size() (width, height int) {
+	return vt.width, vt.height
+}
+
+// Write interprets p as terminal output
+func (vt *VirtualTerminal) Write(p []byte) (int, error) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	data := append(vt.pending, p...)
+	vt.pending = nil
+
+	for i := 0; i < len(data); {
+		switch b := data[i]; {
+		case b == 0x1b:
+			n, ok := vt.escape(data[i:])
+			if !ok {
+				vt.pending = append([]byte{}, data[i:]...)
+				return len(p), nil
+			}
+			i += n
+		case b == '\r':
+			vt.x = 0
+			i++
+		case b == '\n':
+			vt.lineFeed()
+			i++
+		case b == '\b':
+			vt.x = max(0, vt.x-1)
+			i++
+		case b < ' ':
+			i++
+		default:
+			if !utf8.FullRune(data[i:]) {
+				vt.pending = append([]byte{}, data[i:]...)
+				return len(p), nil
+			}
+			ch, size := utf8.DecodeRune(data[i:])
+			vt.put(ch)
+			i += size
+		}
+	}
+	return len(p), nil
+}
+
+// put writes a character at the cursor, wrapping at the right edge
+func (vt *VirtualTerminal) put(ch rune) {
+	if vt.x >= vt.width {
+		vt.x = 0
+		vt.lineFeed()
+	}
+	vt.grid[vt.y][vt.x] = ch
+	vt.x++
+}
+
+// lineFeed moves the cursor down, scrolling at the bottom
+func (vt *VirtualTerminal) lineFeed() {
+	if vt.y < vt.height-1 {
+		vt.y++
+		return
+	}
+	copy(vt.grid, vt.grid[1:])
+	vt.grid[vt.height-1] = blankRow(vt.width)
+}
+
+// escape applies the escape sequence at the start of data and returns its
+// length, or false if data ends before the sequence does
+func (vt *VirtualTerminal) escape(data []byte) (int, bool) {
+	if len(data) < 2 {
+		return 0, false
+	}
+	if data[1] != '[' {
+		return 2, true
+	}
+
+	end := 2
+	for end < len(data) && (data[end] < 0x40 || data[end] > 0x7e) {
+		end++
+	}
+	if end == len(data) {
+		return 0, false
+	}
+
+	params := string(data[2:end])
+	private := strings.HasPrefix(params, "?")
+	var args []int
+	for _, field := range strings.Split(strings.TrimPrefix(params, "?"), ";") {
+		n, _ := strconv.Atoi(field)
+		args = append(args, n)
+	}
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+
+	switch data[end] {
+	case 'A':
+		vt.y = max(0, vt.y-arg(0, 1))
+	case 'B':
+		vt.y = min(vt.height-1, vt.y+arg(0, 1))
+	case 'C':
+		vt.x = min(vt.width-1, vt.x+arg(0, 1))
+	case 'D':
+		vt.x = max(0, vt.x-arg(0, 1))
+	case 'H':
+		vt.y = min(vt.height, arg(0, 1)) - 1
+		vt.x = min(vt.width, arg(1, 1)) - 1
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			vt.eraseLine(vt.x, vt.width)
+			for y := vt.y + 1; y < vt.height; y++ {
+				vt.grid[y] = blankRow(vt.width)
+			}
+		case 2, 3:
+			for y := range vt.grid {
+				vt.grid[y] = blankRow(vt.width)
+			}
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			vt.eraseLine(vt.x, vt.width)
+		case 1:
+			vt.eraseLine(0, vt.x+1)
+		case 2:
+			vt.eraseLine(0, vt.width)
+		}
+	case 'h', 'l':
+		// Alternate screen, cursor visibility and other modes
+		if private && arg(0, 0) == 1049 {
+			for y := range vt.grid {
+				vt.grid[y] = blankRow(vt.width)
+			}
+		}
+	}
+	return end + 1, true
+}
+
+// eraseLine blanks columns from to to of the cursor row
+func (vt *VirtualTerminal) eraseLine(from, to int) {
+	for x := max(from, 0); x < min(to, vt.width); x++ {
+		vt.grid[vt.y][x] = ' '
+	}
+}
+
+// Row returns row y, without trailing spaces
+func (vt *VirtualTerminal) Row(y int) string {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	return strings.TrimRight(string(vt.grid[y]), " ")
+}
+
+// String returns the screen, one row per line, without trailing spaces
+func (vt *VirtualTerminal) String() string {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	rows := make([]string, vt.height)
+	for y, row := range vt.grid {
+		rows[y] = strings.TrimRight(string(row), " ")
+	}
+	return strings.Join(rows, "\n")
+}
+
+// Cursor returns the cursor position, from 0
+func (vt *VirtualTerminal) Cursor() (x, y int) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	return vt.x, vt.y
+}
+
+func blankRow(width int) []rune {
+	row := make([]rune, width)
+	for i := range row {
+		row[i] = ' '
+	}
+	return row
+}
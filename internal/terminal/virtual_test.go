@@ -0,0 +1,32 @@
+package terminal
+
+import "testing"
+
+func TestVirtualTerminal(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   []string
+		expected string
+	}{
+		{"text and line breaks", []string{"ab\r\ncd"}, "ab\ncd\n"},
+		{"wrap at the right edge", []string{"abcdef"}, "abcd\nef\n"},
+		{"scroll at the bottom", []string{"a\r\nb\r\nc\r\nd"}, "b\nc\nd"},
+		{"cursor position", []string{"\x1b[2;3Hx\x1b[Hy"}, "y\n  x\n"},
+		{"relative moves", []string{"abc\x1b[2D\x1b[Bz\x1b[Aq"}, "abq\n z\n"},
+		{"erase to end of screen", []string{"abcd\r\nefgh\r\nijkl\x1b[2;2H\x1b[J"}, "abcd\ne\n"},
+		{"erase line", []string{"abcd\x1b[2D\x1b[K"}, "ab\n\n"},
+		{"styles are ignored", []string{"\x1b[1;7ma\x1b[0mb"}, "ab\n\n"},
+		{"sequences split across writes", []string{"a\x1b[", "2;1Hb\xc3", "\xa9"}, "a\nbé\n"},
+		{"alternate screen", []string{"abc\x1b[?1049h\x1b[?25lx"}, "   x\n\n"},
+	}
+
+	for _, test := range tests {
+		vt := NewVirtualTerminal(4, 3)
+		for _, output := range test.output {
+			vt.Write([]byte(output))
+		}
+		if got := vt.String(); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words returns the words in s with surrounding punctuation removed.
+func Words(s string) []string {
+	var words []string
+	for _, field := range strings.Fields(s) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// Sentences splits s into sentences on terminal punctuation and blank lines.
+func Sentences(s string) []string {
+	var sentences []string
+	var current strings.Builder
+
+	flush := func() {
+		sentence := strings.TrimSpace(current.String())
+		if sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		current.WriteRune(r)
+		switch r {
+		case '.', '!', '?':
+			// Only end the sentence at the last of a run of punctuation
+			// that is followed by whitespace or the end of the text
+			if i+1 == len(runes) || unicode.IsSpace(runes[i+1]) {
+				flush()
+			}
+		case '\n':
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	return sentences
+}
+
+// Syllables estimates the number of syllables in an English word.
+func Syllables(word string) int {
+	word = strings.ToLower(word)
+	if word == "" {
+		return 0
+	}
+
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+
+	// Silent trailing 'e' ("make") but not "-le" ("table")
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+		count--
+	}
+
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+// ReadingGrade returns the Flesch-Kincaid grade level of s.
+// Empty text has a grade level of zero.
+func ReadingGrade(s string) float64 {
+	words := Words(s)
+	if len(words) == 0 {
+		return 0
+	}
+
+	sentences := len(Sentences(s))
+	if sentences == 0 {
+		sentences = 1
+	}
+
+	syllables := 0
+	for _, word := range words {
+		syllables += Syllables(word)
+	}
+
+	wordsPerSentence := float64(len(words)) / float64(sentences)
+	syllablesPerWord := float64(syllables) / float64(len(words))
+	grade := 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59
+	if grade < 0 {
+		return 0
+	}
+	return grade
+}
@@ -0,0 +1,43 @@
+package textutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	got := Sentences("Hello there. How are you?! Fine...\n\nNew paragraph")
+	expected := []string{"Hello there.", "How are you?!", "Fine...", "New paragraph"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestSyllables(t *testing.T) {
+	tests := map[string]int{
+		"cat":         1,
+		"make":        1,
+		"table":       2,
+		"readability": 5,
+		"rhythm":      1,
+	}
+
+	for word, expected := range tests {
+		if got := Syllables(word); got != expected {
+			t.Errorf("Syllables(%q) = %d, expected %d", word, got, expected)
+		}
+	}
+}
+
+func TestReadingGrade(t *testing.T) {
+	simple := ReadingGrade("The cat sat on the mat. The dog ran.")
+	complex := ReadingGrade("Organizational transformation necessitates comprehensive stakeholder alignment regarding institutional prioritization.")
+
+	if simple >= complex {
+		t.Errorf("Expected simple text (%.1f) to have a lower grade than complex text (%.1f)", simple, complex)
+	}
+
+	if got := ReadingGrade(""); got != 0 {
+		t.Errorf("Expected grade 0 for empty text, got %.1f", got)
+	}
+}
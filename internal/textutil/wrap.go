@@ -0,0 +1,108 @@
+package textutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Wrap hard-wraps text so that no line exceeds width runes.
+// Existing line breaks are preserved, words are broken only when a single
+// word is longer than width. A width of zero or less disables wrapping.
+func Wrap(text string, width int) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if width <= 0 {
+		return strings.Split(text, "\n")
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		var current strings.Builder
+		currentLen := 0
+		for _, word := range words {
+			wordLen := utf8.RuneCountInString(word)
+
+			// Break words that cannot fit on a line of their own
+			for wordLen > width {
+				if currentLen > 0 {
+					lines = append(lines, current.String())
+					current.Reset()
+					currentLen = 0
+				}
+				head, tail := splitRunes(word, width)
+				lines = append(lines, head)
+				word = tail
+				wordLen = utf8.RuneCountInString(word)
+			}
+
+			if currentLen > 0 && currentLen+1+wordLen > width {
+				lines = append(lines, current.String())
+				current.Reset()
+				currentLen = 0
+			}
+
+			if currentLen > 0 {
+				current.WriteByte(' ')
+				currentLen++
+			}
+			current.WriteString(word)
+			currentLen += wordLen
+		}
+
+		if currentLen > 0 {
+			lines = append(lines, current.String())
+		}
+	}
+
+	return lines
+}
+
+// WrapString is like Wrap but joins the wrapped lines with newlines.
+func WrapString(text string, width int) string {
+	return strings.Join(Wrap(text, width), "\n")
+}
+
+// PadRight pads s with spaces to width runes. Strings that are already
+// wider than width are returned unchanged.
+func PadRight(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// Truncate shortens s to at most width runes, adding an ellipsis when
+// anything was removed.
+func Truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		head, _ := splitRunes(s, width)
+		return head
+	}
+	head, _ := splitRunes(s, width-3)
+	return head + "..."
+}
+
+// WordCount returns the number of whitespace-separated words in s.
+func WordCount(s string) int {
+	return len(strings.Fields(s))
+}
+
+func splitRunes(s string, n int) (string, string) {
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos], s[pos:]
+		}
+		i++
+	}
+	return s, ""
+}
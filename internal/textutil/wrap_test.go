@@ -0,0 +1,75 @@
+package textutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		expected []string
+	}{
+		{
+			name:     "no wrapping needed",
+			text:     "short line",
+			width:    20,
+			expected: []string{"short line"},
+		},
+		{
+			name:     "wraps on word boundaries",
+			text:     "the quick brown fox jumps",
+			width:    10,
+			expected: []string{"the quick", "brown fox", "jumps"},
+		},
+		{
+			name:     "preserves paragraphs",
+			text:     "first\n\nsecond",
+			width:    10,
+			expected: []string{"first", "", "second"},
+		},
+		{
+			name:     "breaks long words",
+			text:     "abcdefghij",
+			width:    4,
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "zero width disables wrapping",
+			text:     "a very long line that is not wrapped",
+			width:    0,
+			expected: []string{"a very long line that is not wrapped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(tt.text, tt.width)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Wrap(%q, %d) = %q, expected %q", tt.text, tt.width, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("hello world", 8); got != "hello..." {
+		t.Errorf("Expected 'hello...', got %q", got)
+	}
+
+	if got := Truncate("hello", 8); got != "hello" {
+		t.Errorf("Expected 'hello', got %q", got)
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	if got := PadRight("ab", 4); got != "ab  " {
+		t.Errorf("Expected 'ab  ', got %q", got)
+	}
+
+	if got := PadRight("abcdef", 4); got != "abcdef" {
+		t.Errorf("Expected 'abcdef', got %q", got)
+	}
+}
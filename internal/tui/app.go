@@ -0,0 +1,233 @@
+// Package tui is a full-screen terminal dashboard for browsing personas with
+// their profiles and files, editing profile instructions, watching training
+// jobs and trying out generations.
+//
+// The dashboard is a state machine: Update applies a message, such as a key
+// press or an API response, and returns commands that run in the background
+// and produce further messages; View draws the state onto a screen. Run
+// drives it on a terminal, and tests drive it directly.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// Msg is an event for the dashboard
+type Msg interface{}
+
+// Cmd is work started by Update, such as an API call, whose result is fed
+// back to Update. It may return nil.
+type Cmd func() Msg
+
+// KeyMsg is a key press
+type KeyMsg terminal.Key
+
+// TickMsg is sent at every App.Interval, to refresh live views
+type TickMsg time.Time
+
+type tab int
+
+const (
+	personasTab tab = iota
+	profilesTab
+	jobsTab
+)
+
+var tabNames = []string{"Personas", "Profiles", "Jobs"}
+
+// App is the dashboard state
+type App struct {
+	// Interval is how often Run sends a TickMsg, which refreshes the
+	// training jobs while they are shown (default 2s)
+	Interval time.Duration
+	// Plain draws without styles
+	Plain bool
+
+	ctx     context.Context
+	api     *client.ToneCloneClient
+	tab     tab
+	status  string
+	failed  string // the kind of request whose error is the status
+	done    bool
+	loading map[string]bool // requests in flight, by kind
+
+	personas personasView
+	profiles profilesView
+	jobs     jobsView
+}
+
+// New returns a dashboard using api. Requests are canceled with ctx.
+func New(ctx context.Context, api *client.ToneCloneClient) *App {
+	return &App{
+		Interval: 2 * time.Second,
+		ctx:      ctx,
+		api:      api,
+		loading:  make(map[string]bool),
+		personas: personasView{details: make(map[string]*personaDetails)},
+	}
+}
+
+// Done reports whether the user has quit
+func (a *App) Done() bool {
+	return a.done
+}
+
+// Init returns the commands loading the first data
+func (a *App) Init() []Cmd {
+	return []Cmd{a.loadPersonas(), a.loadProfiles()}
+}
+
+// Update applies msg to the state and returns the commands to run next
+func (a *App) Update(msg Msg) []Cmd {
+	switch msg := msg.(type) {
+	case TickMsg:
+		if a.tab == jobsTab {
+			return a.refreshJobs()
+		}
+		return nil
+	case KeyMsg:
+		return a.key(terminal.Key(msg))
+	case personasMsg:
+		return a.personasLoaded(msg)
+	case detailsMsg:
+		return a.detailsLoaded(msg)
+	case generatedMsg:
+		return a.generated(msg)
+	case profilesMsg:
+		return a.profilesLoaded(msg)
+	case savedMsg:
+		return a.saved(msg)
+	case jobsMsg:
+		return a.jobsLoaded(msg)
+	}
+	return nil
+}
+
+// key handles a key press. Text inputs take every key but Ctrl-C; otherwise
+// a few keys are shared by all tabs.
+func (a *App) key(key terminal.Key) []Cmd {
+	if key.Code == terminal.KeyCtrlC {
+		a.done = true
+		return nil
+	}
+	if a.typing() {
+		return a.tabKey(key)
+	}
+
+	switch {
+	case key.Code == terminal.KeyRune && key.Rune == 'q':
+		a.done = true
+		return nil
+	case key.Code == terminal.KeyTab:
+		return a.switchTab((a.tab + 1) % tab(len(tabNames)))
+	case key.Code == terminal.KeyRune && key.Rune >= '1' && key.Rune < '1'+rune(len(tabNames)):
+		return a.switchTab(tab(key.Rune - '1'))
+	}
+	return a.tabKey(key)
+}
+
+// typing reports whether a text input has the keyboard
+func (a *App) typing() bool {
+	return a.personas.prompting || a.profiles.editor != nil
+}
+
+func (a *App) tabKey(key terminal.Key) []Cmd {
+	switch a.tab {
+	case personasTab:
+		return a.personasKey(key)
+	case profilesTab:
+		return a.profilesKey(key)
+	default:
+		return a.jobsKey(key)
+	}
+}
+
+// switchTab shows another tab, loading its data the first time
+func (a *App) switchTab(t tab) []Cmd {
+	a.tab = t
+	a.status, a.failed = "", ""
+	if t == jobsTab {
+		return a.refreshJobs()
+	}
+	return nil
+}
+
+// request marks a kind of request in flight and returns cmd, or returns nil
+// if one is already running
+func (a *App) request(kind string, cmd func() Msg) Cmd {
+	if a.loading[kind] {
+		return nil
+	}
+	a.loading[kind] = true
+	return cmd
+}
+
+// finish marks a kind of request done and shows its error, if any. An
+// error stays until the same kind of request succeeds.
+func (a *App) finish(kind string, err error) {
+	delete(a.loading, kind)
+	switch {
+	case err != nil && !errors.Is(err, context.Canceled):
+		a.status, a.failed = "Error: "+err.Error(), kind
+	case err == nil && a.failed == kind:
+		a.status, a.failed = "", ""
+	}
+}
+
+// View draws the dashboard onto screen: the tabs, the current tab and a
+// status line
+func (a *App) View(screen *terminal.Screen) {
+	screen.Clear()
+	if screen.Width < 20 || screen.Height < 5 {
+		screen.Text(0, 0, screen.Width, "Terminal too small", 0)
+		return
+	}
+
+	x := screen.Text(0, 0, screen.Width, " ToneClone ", terminal.Bold)
+	for i, name := range tabNames {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		style := terminal.Dim
+		if tab(i) == a.tab {
+			style = terminal.Reverse
+		}
+		x += screen.Text(x+1, 0, screen.Width, label, style) + 1
+	}
+
+	body := area{x: 0, y: 2, width: screen.Width, height: screen.Height - 3}
+	switch a.tab {
+	case personasTab:
+		a.viewPersonas(screen, body)
+	case profilesTab:
+		a.viewProfiles(screen, body)
+	default:
+		a.viewJobs(screen, body)
+	}
+
+	status, style := a.status, terminal.Bold
+	if status == "" {
+		status, style = a.help(), terminal.Dim
+	}
+	screen.Text(0, screen.Height-1, screen.Width, status, style)
+}
+
+// help returns the keys of the current tab
+func (a *App) help() string {
+	switch {
+	case a.personas.prompting:
+		return "Enter generate  Esc close"
+	case a.profiles.editor != nil:
+		return "Ctrl-S save  Esc cancel"
+	case a.tab == personasTab:
+		return "↑/↓ select  g generate  r refresh  Tab/1-3 switch  q quit"
+	case a.tab == profilesTab:
+		return "↑/↓ select  e edit  r refresh  Tab/1-3 switch  q quit"
+	default:
+		return "↑/↓ select  r refresh  Tab/1-3 switch  q quit"
+	}
+}
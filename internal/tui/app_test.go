@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/pkg/client"
+	"github.com/toneclone/cli/pkg/clienttest"
+)
+
+// fixture is a dashboard on a fake API holding a persona with a profile and
+// a file
+type fixture struct {
+	t       *testing.T
+	app     *App
+	server  *clienttest.Server
+	api     *client.ToneCloneClient
+	persona client.Persona
+	profile client.Profile
+}
+
+func newFixture(t *testing.T) *fixture {
+	server, url := clienttest.NewTestServer(t)
+	api := clienttest.NewClient(url)
+	ctx := context.Background()
+
+	persona := server.AddPersona("Blogger")
+	profile := server.AddProfile("Formal", "Write formally.")
+	file := server.AddFile("notes.txt", "Some notes")
+	if err := api.Profiles.AssociateWithPersona(ctx, profile.ProfileID, persona.PersonaID); err != nil {
+		t.Fatalf("Failed to associate profile: %v", err)
+	}
+	if err := api.Personas.AssociateFiles(ctx, persona.PersonaID, []string{file.FileID}); err != nil {
+		t.Fatalf("Failed to associate file: %v", err)
+	}
+
+	f := &fixture{t: t, app: New(ctx, api), server: server, api: api, persona: persona, profile: profile}
+	f.run(f.app.Init())
+	return f
+}
+
+// run runs cmds one after the other, feeding their messages back to the
+// app, until no work is left
+func (f *fixture) run(cmds []Cmd) {
+	for len(cmds) > 0 {
+		cmd := cmds[0]
+		cmds = cmds[1:]
+		if cmd == nil {
+			continue
+		}
+		if msg := cmd(); msg != nil {
+			cmds = append(cmds, f.app.Update(msg)...)
+		}
+	}
+}
+
+// press sends the keys encoded in input
+func (f *fixture) press(input string) {
+	r := bufio.NewReader(strings.NewReader(input))
+	for {
+		key, err := terminal.ReadKey(r)
+		if err != nil {
+			return
+		}
+		f.run(f.app.Update(KeyMsg(key)))
+	}
+}
+
+// screen returns the dashboard as drawn on a 100x24 terminal
+func (f *fixture) screen() string {
+	screen := terminal.NewScreen(100, 24)
+	f.app.View(screen)
+	return screen.String()
+}
+
+func (f *fixture) expectScreen(expected ...string) {
+	f.t.Helper()
+	screen := f.screen()
+	for _, e := range expected {
+		if !strings.Contains(screen, e) {
+			f.t.Errorf("Expected %q on screen:\n%s", e, screen)
+		}
+	}
+}
+
+func TestPersonasTab(t *testing.T) {
+	f := newFixture(t)
+	f.expectScreen("1 Personas", "> Blogger", "  Professional (built-in)", "ID: "+f.persona.PersonaID,
+		"Profiles (1)", "- Formal", "Files (1)", "- notes.txt")
+
+	// Moving loads the details of the next persona
+	f.press("j")
+	f.expectScreen("> Professional (built-in)", "Profiles (0)", "Files (0)")
+	f.press("\x1b[A")
+	f.expectScreen("> Blogger", "- Formal")
+}
+
+func TestGenerate(t *testing.T) {
+	f := newFixture(t)
+
+	f.press("g")
+	f.expectScreen("Generate with Blogger", "> _")
+	// Keys go to the prompt while it is open
+	f.press("q hi")
+	if f.app.Done() {
+		t.Fatal("Expected q to be typed into the prompt")
+	}
+	f.press("\r")
+	f.expectScreen("q hi", "Mock response in the voice of", "Blogger: q hi")
+
+	f.press("\x1b")
+	if strings.Contains(f.screen(), "Generate with") {
+		t.Error("Expected Escape to close the generation pane")
+	}
+}
+
+func TestEditProfile(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	f.press("2")
+	f.expectScreen("> Formal", "Write formally.")
+
+	// Escape discards changes
+	f.press("eXYZ")
+	f.expectScreen("Editing Formal", "Write formally.XYZ", "Ctrl-S save")
+	f.press("\x1b")
+	f.expectScreen("Write formally.")
+
+	f.press("e\x7f\r Be brief.\x13")
+	f.expectScreen("Saved Formal")
+	profile, err := f.api.Profiles.Get(ctx, f.profile.ProfileID)
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	if profile.Instructions != "Write formally\n Be brief." {
+		t.Errorf("Unexpected instructions %q", profile.Instructions)
+	}
+	f.expectScreen("Write formally", "Be brief.")
+}
+
+func TestEditor(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{"type at the end", "!", "ab\ncd!"},
+		{"left and backspace", "\x1b[D\x7f", "ab\nd"},
+		{"home", "\x1b[Hx", "ab\nxcd"},
+		{"up keeps the column", "\x1b[A\x1b[Dx", "axb\ncd"},
+		{"down at the last line goes to the end", "\x1b[H\x1b[Bx", "ab\ncdx"},
+		{"delete", "\x1b[A\x1b[H\x1b[3~", "b\ncd"},
+	}
+	for _, test := range tests {
+		e := newEditor("ab\ncd")
+		r := bufio.NewReader(strings.NewReader(test.keys))
+		for {
+			key, err := terminal.ReadKey(r)
+			if err != nil {
+				break
+			}
+			e.key(key)
+		}
+		if got := e.String(); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestJobsTab(t *testing.T) {
+	f := newFixture(t)
+	job, err := f.api.Training.CreateJob(context.Background(), f.persona.PersonaID, nil)
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	f.press("3")
+	f.expectScreen("JOB ID", "> "+job.JobID, "Blogger", "running")
+
+	// Ticks refresh the jobs while the tab is shown
+	for i := 0; i < 3; i++ {
+		f.run(f.app.Update(TickMsg(time.Now())))
+	}
+	f.expectScreen("completed")
+
+	requests := len(f.server.Requests())
+	f.press("1")
+	f.run(f.app.Update(TickMsg(time.Now())))
+	if len(f.server.Requests()) != requests {
+		t.Error("Expected no refresh while the jobs are hidden")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	f := newFixture(t)
+	f.server.FailNext(http.StatusForbidden, 1)
+	f.press("r")
+	f.expectScreen("Error: failed to list personas")
+
+	// The error clears once the listing works again
+	f.press("r")
+	if strings.Contains(f.screen(), "Error:") {
+		t.Errorf("Expected the error to clear:\n%s", f.screen())
+	}
+}
+
+func TestQuit(t *testing.T) {
+	for _, keys := range []string{"q", "\x03", "g\x03"} {
+		f := newFixture(t)
+		f.press(keys)
+		if !f.app.Done() {
+			t.Errorf("Expected %q to quit", keys)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/internal/textutil"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// jobsView is the Jobs tab: training jobs, newest first, refreshed while
+// the tab is shown
+type jobsView struct {
+	items  []client.TrainingJob
+	list   list
+	loaded time.Time
+}
+
+type jobsMsg struct {
+	jobs []client.TrainingJob
+	err  error
+	at   time.Time
+}
+
+// refreshJobs lists the training jobs unless a listing is running
+func (a *App) refreshJobs() []Cmd {
+	cmd := a.request("jobs", func() Msg {
+		jobs, err := a.api.Training.ListJobs(a.ctx)
+		if err != nil {
+			return jobsMsg{err: fmt.Errorf("failed to list training jobs: %w", err)}
+		}
+		return jobsMsg{jobs: jobs, at: time.Now()}
+	})
+	if cmd == nil {
+		return nil
+	}
+	return []Cmd{cmd}
+}
+
+func (a *App) jobsLoaded(msg jobsMsg) []Cmd {
+	a.finish("jobs", msg.err)
+	if msg.err != nil {
+		return nil
+	}
+
+	v := &a.jobs
+	// Keep the same job selected as jobs are added
+	var selected string
+	if v.list.cursor < len(v.items) {
+		selected = v.items[v.list.cursor].JobID
+	}
+	v.items = msg.jobs
+	sort.SliceStable(v.items, func(i, j int) bool {
+		return v.items[i].CreatedAt.After(v.items[j].CreatedAt)
+	})
+	for i, job := range v.items {
+		if job.JobID == selected {
+			v.list.cursor = i
+		}
+	}
+	v.list.clamp(len(v.items))
+	v.loaded = msg.at
+	return nil
+}
+
+func (a *App) jobsKey(key terminal.Key) []Cmd {
+	if listKey(&a.jobs.list, key, len(a.jobs.items)) {
+		return nil
+	}
+	if key.Code == terminal.KeyRune && key.Rune == 'r' {
+		return a.refreshJobs()
+	}
+	return nil
+}
+
+// personaName returns the name of a persona, or its ID if it is not listed
+func (a *App) personaName(id string) string {
+	for _, p := range a.personas.items {
+		if p.PersonaID == id {
+			return p.Name
+		}
+	}
+	return id
+}
+
+func (a *App) viewJobs(screen *terminal.Screen, body area) {
+	v := &a.jobs
+	if len(v.items) == 0 {
+		message := "No training jobs"
+		if v.loaded.IsZero() {
+			message = "Loading training jobs..."
+		}
+		screen.Text(body.x, body.y, body.width, message, terminal.Dim)
+		return
+	}
+
+	columns := []struct {
+		title string
+		width int
+	}{
+		{"JOB ID", 16},
+		{"PERSONA", 20},
+		{"STATUS", 12},
+		{"FILES", 9},
+		{"UPDATED", 20},
+	}
+	row := func(values ...string) string {
+		var line string
+		for i, value := range values {
+			line += textutil.PadRight(textutil.Truncate(value, columns[i].width), columns[i].width) + " "
+		}
+		return line
+	}
+
+	titles := make([]string, len(columns))
+	for i, c := range columns {
+		titles[i] = c.title
+	}
+	screen.Text(body.x+2, body.y, body.width-2, row(titles...), terminal.Bold)
+
+	rows := make([]string, len(v.items))
+	for i, job := range v.items {
+		rows[i] = row(
+			job.JobID,
+			a.personaName(job.PersonaID),
+			job.Status,
+			fmt.Sprintf("%d/%d", job.FilesProcessed, job.TotalFiles),
+			job.UpdatedAt.Local().Format("2006-01-02 15:04:05"),
+		)
+	}
+	v.list.draw(screen, area{x: body.x, y: body.y + 1, width: body.width, height: body.height - 2}, rows)
+
+	updated := "Updated " + v.loaded.Local().Format("15:04:05")
+	screen.Text(body.x, body.y+body.height-1, body.width, updated, terminal.Dim)
+}
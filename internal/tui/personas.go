@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// personasView is the Personas tab: a list of personas, the profiles and
+// files of the selected one, and a pane to generate text with it
+type personasView struct {
+	items   []client.Persona
+	list    list
+	details map[string]*personaDetails // by persona ID
+
+	prompting  bool   // the prompt input has the keyboard
+	prompt     []rune // text typed into the prompt input
+	generation *generation
+}
+
+// personaDetails holds what is associated with a persona
+type personaDetails struct {
+	profiles []client.Profile
+	files    []client.TrainingFile
+	err      error
+}
+
+// generation is a generation request and, once it returns, its result
+type generation struct {
+	personaID string
+	prompt    string
+	text      string
+	err       error
+	done      bool
+}
+
+type personasMsg struct {
+	personas []client.Persona
+	err      error
+}
+
+type detailsMsg struct {
+	personaID string
+	details   personaDetails
+}
+
+type generatedMsg struct {
+	personaID string
+	prompt    string
+	text      string
+	err       error
+}
+
+// loadPersonas lists user personas followed by built-in ones
+func (a *App) loadPersonas() Cmd {
+	return a.request("personas", func() Msg {
+		personas, err := a.api.Personas.List(a.ctx)
+		if err != nil {
+			return personasMsg{err: fmt.Errorf("failed to list personas: %w", err)}
+		}
+		builtIn, err := a.api.Personas.ListBuiltIn(a.ctx)
+		if err != nil {
+			return personasMsg{err: fmt.Errorf("failed to list built-in personas: %w", err)}
+		}
+		for i := range builtIn {
+			builtIn[i].IsBuiltIn = true
+		}
+		return personasMsg{personas: append(personas, builtIn...)}
+	})
+}
+
+// loadDetails lists the profiles and files of a persona
+func (a *App) loadDetails(personaID string) Cmd {
+	return a.request("details "+personaID, func() Msg {
+		msg := detailsMsg{personaID: personaID}
+		profiles, err := a.api.Profiles.GetPersonaProfiles(a.ctx, personaID)
+		if err != nil {
+			msg.details.err = fmt.Errorf("failed to list profiles: %w", err)
+			return msg
+		}
+		files, err := a.api.Personas.ListFiles(a.ctx, personaID)
+		if err != nil {
+			msg.details.err = fmt.Errorf("failed to list files: %w", err)
+			return msg
+		}
+		msg.details.profiles, msg.details.files = profiles, files
+		return msg
+	})
+}
+
+// generate sends the prompt to the selected persona
+func (a *App) generate(persona client.Persona, prompt string) Cmd {
+	a.personas.generation = &generation{personaID: persona.PersonaID, prompt: prompt}
+	return a.request("generate", func() Msg {
+		response, err := a.api.Generate.Text(a.ctx, &client.GenerateTextRequest{
+			Prompt:    prompt,
+			PersonaID: persona.PersonaID,
+		})
+		msg := generatedMsg{personaID: persona.PersonaID, prompt: prompt, err: err}
+		if err == nil {
+			msg.text = response.Text
+		}
+		return msg
+	})
+}
+
+func (a *App) personasLoaded(msg personasMsg) []Cmd {
+	a.finish("personas", msg.err)
+	if msg.err != nil {
+		return nil
+	}
+	v := &a.personas
+	v.items = msg.personas
+	v.list.clamp(len(v.items))
+	// Details may have changed too
+	clear(v.details)
+	return a.selectPersona()
+}
+
+func (a *App) detailsLoaded(msg detailsMsg) []Cmd {
+	a.finish("details "+msg.personaID, nil)
+	a.personas.details[msg.personaID] = &msg.details
+	return nil
+}
+
+func (a *App) generated(msg generatedMsg) []Cmd {
+	a.finish("generate", nil)
+	g := a.personas.generation
+	if g == nil || g.personaID != msg.personaID || g.prompt != msg.prompt {
+		// The pane was closed or reused meanwhile
+		return nil
+	}
+	g.text, g.err, g.done = msg.text, msg.err, true
+	return nil
+}
+
+// selected returns the persona under the cursor
+func (v *personasView) selected() (client.Persona, bool) {
+	if v.list.cursor >= len(v.items) {
+		return client.Persona{}, false
+	}
+	return v.items[v.list.cursor], true
+}
+
+// selectPersona loads the details of the persona under the cursor unless
+// they are known
+func (a *App) selectPersona() []Cmd {
+	persona, ok := a.personas.selected()
+	if !ok || a.personas.details[persona.PersonaID] != nil {
+		return nil
+	}
+	if cmd := a.loadDetails(persona.PersonaID); cmd != nil {
+		return []Cmd{cmd}
+	}
+	return nil
+}
+
+func (a *App) personasKey(key terminal.Key) []Cmd {
+	v := &a.personas
+	if v.prompting {
+		return a.promptKey(key)
+	}
+
+	if listKey(&v.list, key, len(v.items)) {
+		// The generation pane belongs to the persona it was opened for
+		v.generation = nil
+		return a.selectPersona()
+	}
+	switch {
+	case key.Code == terminal.KeyRune && key.Rune == 'r':
+		clear(v.details)
+		if cmd := a.loadPersonas(); cmd != nil {
+			return []Cmd{cmd}
+		}
+	case key.Code == terminal.KeyRune && key.Rune == 'g', key.Code == terminal.KeyEnter:
+		if _, ok := v.selected(); ok {
+			v.prompting = true
+		}
+	case key.Code == terminal.KeyEscape:
+		v.generation = nil
+	}
+	return nil
+}
+
+// promptKey edits the prompt input; Enter sends it and Escape closes the pane
+func (a *App) promptKey(key terminal.Key) []Cmd {
+	v := &a.personas
+	switch key.Code {
+	case terminal.KeyEscape:
+		v.prompting = false
+		v.prompt = nil
+		v.generation = nil
+	case terminal.KeyEnter:
+		prompt := strings.TrimSpace(string(v.prompt))
+		persona, ok := v.selected()
+		if prompt == "" || !ok || a.loading["generate"] {
+			return nil
+		}
+		v.prompting = false
+		v.prompt = nil
+		return []Cmd{a.generate(persona, prompt)}
+	case terminal.KeyBackspace:
+		if len(v.prompt) > 0 {
+			v.prompt = v.prompt[:len(v.prompt)-1]
+		}
+	case terminal.KeyCtrlU:
+		v.prompt = nil
+	case terminal.KeyRune:
+		v.prompt = append(v.prompt, key.Rune)
+	}
+	return nil
+}
+
+func (a *App) viewPersonas(screen *terminal.Screen, body area) {
+	v := &a.personas
+	if len(v.items) == 0 {
+		message := "No personas"
+		if a.loading["personas"] {
+			message = "Loading personas..."
+		}
+		screen.Text(body.x, body.y, body.width, message, terminal.Dim)
+		return
+	}
+
+	listArea, rest := body.split(min(32, body.width/3))
+	rows := make([]string, len(v.items))
+	for i, p := range v.items {
+		rows[i] = p.Name
+		if p.IsBuiltIn {
+			rows[i] += " (built-in)"
+		}
+	}
+	v.list.draw(screen, listArea, rows)
+
+	persona, _ := v.selected()
+	detailsArea := rest
+	if v.prompting || v.generation != nil {
+		var generateArea area
+		detailsArea, generateArea = rest.split(rest.width / 2)
+		a.viewGeneration(screen, generateArea, persona)
+	}
+	a.viewPersonaDetails(screen, detailsArea, persona)
+}
+
+func (a *App) viewPersonaDetails(screen *terminal.Screen, box area, persona client.Persona) {
+	box = heading(screen, box, persona.Name)
+	lines := []string{
+		"ID: " + persona.PersonaID,
+		"Status: " + persona.Status,
+		"Training: " + persona.TrainingStatus,
+	}
+	if persona.PromptDescription != "" {
+		lines = append(lines, "", persona.PromptDescription)
+	}
+
+	details := a.personas.details[persona.PersonaID]
+	switch {
+	case details == nil:
+		lines = append(lines, "", "Loading profiles and files...")
+	case details.err != nil:
+		lines = append(lines, "", "Error: "+details.err.Error())
+	default:
+		lines = append(lines, "", fmt.Sprintf("Profiles (%d)", len(details.profiles)))
+		for _, p := range details.profiles {
+			lines = append(lines, "- "+p.Name)
+		}
+		lines = append(lines, "", fmt.Sprintf("Files (%d)", len(details.files)))
+		for _, f := range details.files {
+			lines = append(lines, "- "+f.FileName)
+		}
+	}
+
+	y := box.y
+	for _, line := range lines {
+		if y >= box.y+box.height {
+			break
+		}
+		y += paragraph(screen, area{x: box.x, y: y, width: box.width, height: box.y + box.height - y}, line, 0, 0)
+	}
+}
+
+func (a *App) viewGeneration(screen *terminal.Screen, box area, persona client.Persona) {
+	v := &a.personas
+	box = heading(screen, box, "Generate with "+persona.Name)
+
+	if v.prompting {
+		input := "> " + string(v.prompt)
+		rows := paragraph(screen, box, input+"_", 0, 0)
+		box.y += rows + 1
+		box.height -= rows + 1
+	}
+
+	g := v.generation
+	switch {
+	case g == nil:
+	case !g.done:
+		paragraph(screen, box, "Generating: "+g.prompt, 0, terminal.Dim)
+	case g.err != nil:
+		paragraph(screen, box, "Error: "+g.err.Error(), 0, terminal.Bold)
+	default:
+		rows := paragraph(screen, box, g.prompt, 0, terminal.Dim)
+		box.y += rows + 1
+		box.height -= rows + 1
+		paragraph(screen, box, g.text, 0, 0)
+	}
+}
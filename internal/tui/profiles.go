@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// profilesView is the Profiles tab: a list of profiles and the instructions
+// of the selected one, which can be edited in place
+type profilesView struct {
+	items  []client.Profile
+	list   list
+	scroll int     // first line of instructions shown
+	editor *editor // the instructions being edited, or nil
+}
+
+type profilesMsg struct {
+	profiles []client.Profile
+	err      error
+}
+
+type savedMsg struct {
+	profile *client.Profile
+	err     error
+}
+
+func (a *App) loadProfiles() Cmd {
+	return a.request("profiles", func() Msg {
+		profiles, err := a.api.Profiles.List(a.ctx)
+		if err != nil {
+			return profilesMsg{err: fmt.Errorf("failed to list profiles: %w", err)}
+		}
+		return profilesMsg{profiles: profiles}
+	})
+}
+
+// saveProfile updates the instructions of a profile
+func (a *App) saveProfile(profile client.Profile, instructions string) Cmd {
+	return a.request("save", func() Msg {
+		profile.Instructions = instructions
+		updated, err := a.api.Profiles.Update(a.ctx, profile.ProfileID, &profile)
+		if err != nil {
+			return savedMsg{err: fmt.Errorf("failed to update profile: %w", err)}
+		}
+		return savedMsg{profile: updated}
+	})
+}
+
+func (a *App) profilesLoaded(msg profilesMsg) []Cmd {
+	a.finish("profiles", msg.err)
+	if msg.err == nil {
+		a.profiles.items = msg.profiles
+		a.profiles.list.clamp(len(msg.profiles))
+	}
+	return nil
+}
+
+func (a *App) saved(msg savedMsg) []Cmd {
+	a.finish("save", msg.err)
+	if msg.err != nil {
+		// Keep the editor open so the changes are not lost
+		return nil
+	}
+
+	v := &a.profiles
+	for i := range v.items {
+		if v.items[i].ProfileID == msg.profile.ProfileID {
+			v.items[i] = *msg.profile
+		}
+	}
+	v.editor = nil
+	a.status = "Saved " + msg.profile.Name
+	// Personas show the names of their profiles, not the instructions, so
+	// their details stay valid
+	return nil
+}
+
+// selected returns the profile under the cursor
+func (v *profilesView) selected() (client.Profile, bool) {
+	if v.list.cursor >= len(v.items) {
+		return client.Profile{}, false
+	}
+	return v.items[v.list.cursor], true
+}
+
+func (a *App) profilesKey(key terminal.Key) []Cmd {
+	v := &a.profiles
+	if v.editor != nil {
+		return a.editorKey(key)
+	}
+
+	if listKey(&v.list, key, len(v.items)) {
+		v.scroll = 0
+		return nil
+	}
+	switch {
+	case key.Code == terminal.KeyRune && key.Rune == 'r':
+		if cmd := a.loadProfiles(); cmd != nil {
+			return []Cmd{cmd}
+		}
+	case key.Code == terminal.KeyRune && key.Rune == 'e', key.Code == terminal.KeyEnter:
+		if profile, ok := v.selected(); ok {
+			v.editor = newEditor(profile.Instructions)
+			a.status = ""
+		}
+	case key.Code == terminal.KeyRune && key.Rune == 'J':
+		v.scroll++
+	case key.Code == terminal.KeyRune && key.Rune == 'K':
+		v.scroll = max(0, v.scroll-1)
+	}
+	return nil
+}
+
+// editorKey edits the instructions; Ctrl-S saves them and Escape discards
+// the changes
+func (a *App) editorKey(key terminal.Key) []Cmd {
+	v := &a.profiles
+	switch key.Code {
+	case terminal.KeyEscape:
+		v.editor = nil
+		a.status = ""
+	case terminal.KeyCtrlS:
+		profile, ok := v.selected()
+		if !ok {
+			return nil
+		}
+		if cmd := a.saveProfile(profile, v.editor.String()); cmd != nil {
+			a.status = "Saving..."
+			return []Cmd{cmd}
+		}
+	default:
+		v.editor.key(key)
+	}
+	return nil
+}
+
+func (a *App) viewProfiles(screen *terminal.Screen, body area) {
+	v := &a.profiles
+	if len(v.items) == 0 {
+		message := "No profiles"
+		if a.loading["profiles"] {
+			message = "Loading profiles..."
+		}
+		screen.Text(body.x, body.y, body.width, message, terminal.Dim)
+		return
+	}
+
+	listArea, rest := body.split(min(32, body.width/3))
+	rows := make([]string, len(v.items))
+	for i, p := range v.items {
+		rows[i] = p.Name
+	}
+	v.list.draw(screen, listArea, rows)
+
+	profile, _ := v.selected()
+	title := profile.Name
+	if v.editor != nil {
+		title = "Editing " + profile.Name
+	}
+	box := heading(screen, rest, title)
+	switch {
+	case v.editor != nil:
+		v.editor.draw(screen, box)
+	case profile.Instructions == "":
+		screen.Text(box.x, box.y, box.width, "No instructions", terminal.Dim)
+	default:
+		paragraph(screen, box, profile.Instructions, v.scroll, 0)
+	}
+}
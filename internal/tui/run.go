@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/toneclone/cli/internal/terminal"
+)
+
+// SizeFunc returns the size of the terminal in columns and rows
+type SizeFunc func() (width, height int, err error)
+
+// inputMsg is sent when reading keys fails, usually at the end of input
+type inputMsg struct {
+	err error
+}
+
+// resizeInterval is how often Run checks the terminal size
+const resizeInterval = 250 * time.Millisecond
+
+// Run shows the dashboard on out until the user quits, reading keys from in.
+// The terminal must be in raw mode. The screen follows the terminal size,
+// which is polled rather than signaled so that it works everywhere.
+func Run(ctx context.Context, app *App, in io.Reader, out io.Writer, size SizeFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Use the alternate screen, which the terminal restores on exit
+	if _, err := io.WriteString(out, "\x1b[?1049h\x1b[?25l\x1b[2J"); err != nil {
+		return err
+	}
+	defer io.WriteString(out, "\x1b[?25h\x1b[?1049l")
+
+	msgs := make(chan Msg)
+	send := func(msg Msg) {
+		select {
+		case msgs <- msg:
+		case <-ctx.Done():
+		}
+	}
+	start := func(cmds []Cmd) {
+		for _, cmd := range cmds {
+			if cmd == nil {
+				continue
+			}
+			go func() {
+				if msg := cmd(); msg != nil {
+					send(msg)
+				}
+			}()
+		}
+	}
+
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			key, err := terminal.ReadKey(r)
+			if err != nil {
+				send(inputMsg{err: err})
+				return
+			}
+			send(KeyMsg(key))
+		}
+	}()
+
+	width, height, err := size()
+	if err != nil {
+		return err
+	}
+	screen := terminal.NewScreen(width, height)
+	screen.Plain = app.Plain
+
+	interval := app.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	resize := time.NewTicker(resizeInterval)
+	defer resize.Stop()
+
+	start(app.Init())
+	dirty := true
+	for !app.Done() {
+		if dirty {
+			app.View(screen)
+			if err := screen.Render(out); err != nil {
+				return err
+			}
+		}
+		dirty = true
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-msgs:
+			if input, ok := msg.(inputMsg); ok {
+				if errors.Is(input.err, io.EOF) {
+					return nil
+				}
+				return input.err
+			}
+			start(app.Update(msg))
+		case now := <-ticker.C:
+			start(app.Update(TickMsg(now)))
+		case <-resize.C:
+			w, h, err := size()
+			if err != nil || (w == screen.Width && h == screen.Height) {
+				dirty = false
+				continue
+			}
+			screen = terminal.NewScreen(w, h)
+			screen.Plain = app.Plain
+			// Drop what the old size left behind
+			io.WriteString(out, "\x1b[2J")
+		}
+	}
+	return nil
+}
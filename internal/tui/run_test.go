@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/pkg/clienttest"
+)
+
+// waitFor waits until the virtual terminal shows text
+func waitFor(t *testing.T, vt *terminal.VirtualTerminal, text string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(vt.String(), text) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %q on screen:\n%s", text, vt.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRun(t *testing.T) {
+	server, url := clienttest.NewTestServer(t)
+	persona := server.AddPersona("Blogger")
+	server.AddFile("notes.txt", "Some notes")
+	api := clienttest.NewClient(url)
+	ctx := context.Background()
+	if _, err := api.Training.CreateJob(ctx, persona.PersonaID, []string{server.Files()[0].FileID}); err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	app := New(ctx, api)
+	app.Interval = 10 * time.Millisecond
+	vt := terminal.NewVirtualTerminal(90, 20)
+	in, keys := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, app, in, vt, func() (int, int, error) {
+			width, height := vt.Size()
+			return width, height, nil
+		})
+	}()
+
+	waitFor(t, vt, "> Blogger")
+	keys.Write([]byte("3"))
+	waitFor(t, vt, "completed")
+	keys.Write([]byte("q"))
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for Run to return")
+	}
+
+	// Leaving the alternate screen is the last thing written
+	if strings.Contains(vt.String(), "Blogger") {
+		t.Errorf("Expected the dashboard to be gone, got:\n%s", vt.String())
+	}
+}
+
+func TestRunEndOfInput(t *testing.T) {
+	_, url := clienttest.NewTestServer(t)
+	app := New(context.Background(), clienttest.NewClient(url))
+	vt := terminal.NewVirtualTerminal(80, 24)
+
+	err := Run(context.Background(), app, strings.NewReader(""), vt, func() (int, int, error) {
+		return 80, 24, nil
+	})
+	if err != nil {
+		t.Errorf("Expected Run to end cleanly at the end of input, got %v", err)
+	}
+}
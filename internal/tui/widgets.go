@@ -0,0 +1,233 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/toneclone/cli/internal/terminal"
+	"github.com/toneclone/cli/internal/textutil"
+)
+
+// area is a rectangle of the screen
+type area struct {
+	x, y          int
+	width, height int
+}
+
+// split divides a into a left part of width columns, a gap and the rest
+func (a area) split(width int) (area, area) {
+	left := area{x: a.x, y: a.y, width: width, height: a.height}
+	right := area{x: a.x + width + 2, y: a.y, width: max(0, a.width-width-2), height: a.height}
+	return left, right
+}
+
+// list is the selection and scroll position of a list
+type list struct {
+	cursor int
+	offset int
+}
+
+// move moves the cursor by delta within n rows
+func (l *list) move(delta, n int) {
+	l.cursor = max(0, min(n-1, l.cursor+delta))
+}
+
+// clamp keeps the cursor within n rows, after the rows change
+func (l *list) clamp(n int) {
+	l.move(0, n)
+}
+
+// draw draws rows into a, highlighting the cursor and scrolling to keep it
+// in view
+func (l *list) draw(screen *terminal.Screen, a area, rows []string) {
+	if a.height <= 0 {
+		return
+	}
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	}
+	if l.cursor >= l.offset+a.height {
+		l.offset = l.cursor - a.height + 1
+	}
+	for i := l.offset; i < len(rows) && i < l.offset+a.height; i++ {
+		y := a.y + i - l.offset
+		if i == l.cursor {
+			screen.Text(a.x, y, a.width, "> "+rows[i], 0)
+			screen.Fill(a.x, y, a.width, terminal.Reverse)
+		} else {
+			screen.Text(a.x, y, a.width, "  "+rows[i], 0)
+		}
+	}
+}
+
+// listKey moves l for the navigation keys and reports whether key was one
+func listKey(l *list, key terminal.Key, n int) bool {
+	switch {
+	case key.Code == terminal.KeyUp || key.Code == terminal.KeyRune && key.Rune == 'k':
+		l.move(-1, n)
+	case key.Code == terminal.KeyDown || key.Code == terminal.KeyRune && key.Rune == 'j':
+		l.move(1, n)
+	case key.Code == terminal.KeyPageUp:
+		l.move(-10, n)
+	case key.Code == terminal.KeyPageDown:
+		l.move(10, n)
+	case key.Code == terminal.KeyHome:
+		l.move(-n, n)
+	case key.Code == terminal.KeyEnd:
+		l.move(n, n)
+	default:
+		return false
+	}
+	return true
+}
+
+// paragraph draws text into a, wrapped to its width, from line offset. It
+// returns the rows used.
+func paragraph(screen *terminal.Screen, a area, text string, offset int, style terminal.Style) int {
+	lines := textutil.Wrap(text, a.width)
+	rows := 0
+	for i := offset; i < len(lines) && rows < a.height; i++ {
+		screen.Text(a.x, a.y+rows, a.width, lines[i], style)
+		rows++
+	}
+	return rows
+}
+
+// heading draws a title with a rule under it and returns the area below
+func heading(screen *terminal.Screen, a area, title string) area {
+	screen.Text(a.x, a.y, a.width, title, terminal.Bold)
+	screen.Text(a.x, a.y+1, a.width, strings.Repeat("─", a.width), terminal.Dim)
+	return area{x: a.x, y: a.y + 2, width: a.width, height: a.height - 2}
+}
+
+// editor is a multi-line text input
+type editor struct {
+	text   []rune
+	pos    int // cursor index in text
+	offset int // first row shown
+}
+
+func newEditor(text string) *editor {
+	e := &editor{text: []rune(text)}
+	e.pos = len(e.text)
+	return e
+}
+
+func (e *editor) String() string {
+	return string(e.text)
+}
+
+// key edits the text for a key press
+func (e *editor) key(key terminal.Key) {
+	switch key.Code {
+	case terminal.KeyRune:
+		e.insert(key.Rune)
+	case terminal.KeyEnter:
+		e.insert('\n')
+	case terminal.KeyTab:
+		e.insert('\t')
+	case terminal.KeyBackspace:
+		if e.pos > 0 {
+			e.text = append(e.text[:e.pos-1], e.text[e.pos:]...)
+			e.pos--
+		}
+	case terminal.KeyDelete:
+		if e.pos < len(e.text) {
+			e.text = append(e.text[:e.pos], e.text[e.pos+1:]...)
+		}
+	case terminal.KeyLeft:
+		e.pos = max(0, e.pos-1)
+	case terminal.KeyRight:
+		e.pos = min(len(e.text), e.pos+1)
+	case terminal.KeyHome:
+		e.pos = e.lineStart(e.pos)
+	case terminal.KeyEnd:
+		e.pos = e.lineEnd(e.pos)
+	case terminal.KeyUp:
+		if start := e.lineStart(e.pos); start > 0 {
+			e.pos = e.column(e.lineStart(start-1), e.pos-start)
+		} else {
+			e.pos = 0
+		}
+	case terminal.KeyDown:
+		if end := e.lineEnd(e.pos); end < len(e.text) {
+			e.pos = e.column(end+1, e.pos-e.lineStart(e.pos))
+		} else {
+			e.pos = end
+		}
+	}
+}
+
+func (e *editor) insert(r rune) {
+	e.text = append(e.text[:e.pos], append([]rune{r}, e.text[e.pos:]...)...)
+	e.pos++
+}
+
+func (e *editor) lineStart(pos int) int {
+	for pos > 0 && e.text[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+func (e *editor) lineEnd(pos int) int {
+	for pos < len(e.text) && e.text[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+// column returns the index col characters into the line starting at start,
+// or the end of that line if it is shorter
+func (e *editor) column(start, col int) int {
+	return min(start+col, e.lineEnd(start))
+}
+
+// draw draws the text into a, breaking lines at its width, with the cursor
+// as a highlighted cell
+func (e *editor) draw(screen *terminal.Screen, a area) {
+	if a.width <= 0 || a.height <= 0 {
+		return
+	}
+
+	// Lay out the text in rows, noting where the cursor falls
+	type position struct{ x, y int }
+	var cells []position
+	cursor := position{}
+	x, y := 0, 0
+	for i, r := range e.text {
+		if i == e.pos {
+			cursor = position{x, y}
+		}
+		if r == '\n' {
+			x, y = 0, y+1
+			cells = append(cells, position{-1, -1})
+			continue
+		}
+		if x == a.width {
+			x, y = 0, y+1
+		}
+		cells = append(cells, position{x, y})
+		x++
+	}
+	if e.pos == len(e.text) {
+		if x == a.width {
+			x, y = 0, y+1
+		}
+		cursor = position{x, y}
+	}
+
+	if cursor.y < e.offset {
+		e.offset = cursor.y
+	}
+	if cursor.y >= e.offset+a.height {
+		e.offset = cursor.y - a.height + 1
+	}
+
+	for i, cell := range cells {
+		if cell.y < e.offset || cell.y >= e.offset+a.height {
+			continue
+		}
+		screen.Text(a.x+cell.x, a.y+cell.y-e.offset, 1, string(e.text[i]), 0)
+	}
+	screen.Fill(a.x+cursor.x, a.y+cursor.y-e.offset, 1, terminal.Reverse)
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KnownScopes lists every scope an API key can be granted
+var KnownScopes = []APIKeyScope{
+	ScopePersonasRead, ScopeProfilesRead, ScopeTrainingRead, ScopeFilesRead, ScopeWritingRead, ScopeUserRead,
+	ScopePersonasWrite, ScopeProfilesWrite, ScopeTrainingWrite, ScopeFilesWrite, ScopeWritingWrite, ScopeUserWrite,
+	ScopeTextGenerate, ScopeAdmin, ScopeAll,
+}
+
+// ParseScopes converts scope names to APIKeyScope values, rejecting unknown scopes
+func ParseScopes(names []string) ([]APIKeyScope, error) {
+	var scopes []APIKeyScope
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		scope := APIKeyScope(name)
+		known := false
+		for _, s := range KnownScopes {
+			if s == scope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown scope '%s'", name)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// CreateAPIKeyRequest represents a request to create an API key
+type CreateAPIKeyRequest struct {
+	Name      string        `json:"name"`
+	Scopes    []APIKeyScope `json:"scopes,omitempty"`
+	ExpiresAt *time.Time    `json:"expiresAt,omitempty"`
+}
+
+// CreateAPIKeyResponse represents a newly created API key. The secret is
+// only returned once, at creation.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeysClient handles API key management operations
+type APIKeysClient struct {
+	client *Client
+}
+
+// NewAPIKeysClient creates a new API keys client
+func NewAPIKeysClient(client *Client) *APIKeysClient {
+	return &APIKeysClient{client: client}
+}
+
+// List retrieves all API keys for the authenticated user
+func (k *APIKeysClient) List(ctx context.Context) ([]APIKey, error) {
+	var response APIKeyListResponse
+	err := k.client.Get(ctx, "/keys", &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return response.Keys, nil
+}
+
+// Create creates a new API key
+func (k *APIKeysClient) Create(ctx context.Context, request *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	var result CreateAPIKeyResponse
+	err := k.client.Post(ctx, "/keys", request, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	if result.Key == "" {
+		return nil, fmt.Errorf("failed to create API key: response did not include the key")
+	}
+	return &result, nil
+}
+
+// Revoke revokes an API key
+func (k *APIKeysClient) Revoke(ctx context.Context, keyID string) error {
+	err := k.client.Delete(ctx, fmt.Sprintf("/keys/%s", keyID))
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key %s: %w", keyID, err)
+	}
+	return nil
+}
+
+// Current returns the record of the API key the client authenticates with.
+// It uses the key-info endpoint and falls back to matching the key against
+// the account's key list.
+func (k *APIKeysClient) Current(ctx context.Context) (*APIKey, error) {
+	var key APIKey
+	if err := k.client.Get(ctx, "/keys/current", &key); err == nil && key.KeyID != "" {
+		return &key, nil
+	}
+	return k.FindByKey(ctx, k.client.apiKey)
+}
+
+// FindByKey returns the API key record matching a secret key by its prefix
+func (k *APIKeysClient) FindByKey(ctx context.Context, key string) (*APIKey, error) {
+	keys, err := k.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *APIKey
+	for i := range keys {
+		if keys[i].Prefix == "" || !strings.HasPrefix(key, keys[i].Prefix) {
+			continue
+		}
+		// Prefer the longest prefix if several match
+		if match == nil || len(keys[i].Prefix) > len(match.Prefix) {
+			match = &keys[i]
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no API key on this account matches the configured key")
+	}
+	return match, nil
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeysCreateAndRevoke(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/keys":
+			var request CreateAPIKeyRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			if len(request.Scopes) != 2 || request.Scopes[0] != ScopePersonasRead {
+				t.Errorf("Unexpected scopes: %v", request.Scopes)
+			}
+			json.NewEncoder(w).Encode(CreateAPIKeyResponse{
+				APIKey: APIKey{KeyID: "k2", Name: request.Name, Prefix: "tc_live_new", Scopes: request.Scopes},
+				Key:    "tc_live_newsecret",
+			})
+		case r.Method == "DELETE" && r.URL.Path == "/keys/k1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewToneCloneClient("test_key", WithBaseURL(server.URL))
+
+	scopes, err := ParseScopes([]string{"personas:read", " text:generate"})
+	if err != nil {
+		t.Fatalf("Failed to parse scopes: %v", err)
+	}
+
+	created, err := client.APIKeys.Create(context.Background(), &CreateAPIKeyRequest{Name: "ci", Scopes: scopes})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.Key != "tc_live_newsecret" || created.KeyID != "k2" {
+		t.Errorf("Unexpected created key: %+v", created)
+	}
+
+	if err := client.APIKeys.Revoke(context.Background(), "k1"); err != nil {
+		t.Errorf("Unexpected revoke error: %v", err)
+	}
+}
+
+func TestAPIKeysFindByKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(APIKeyListResponse{Keys: []APIKey{
+			{KeyID: "k1", Prefix: "tc_live_"},
+			{KeyID: "k2", Prefix: "tc_live_abc1"},
+			{KeyID: "k3", Prefix: "tc_live_zzz9"},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewToneCloneClient("test_key", WithBaseURL(server.URL))
+
+	key, err := client.APIKeys.FindByKey(context.Background(), "tc_live_abc1234567")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.KeyID != "k2" {
+		t.Errorf("Expected longest prefix match k2, got %s", key.KeyID)
+	}
+}
+
+func TestParseScopesRejectsUnknown(t *testing.T) {
+	if _, err := ParseScopes([]string{"personas:read", "personas:destroy"}); err == nil {
+		t.Error("Expected error for unknown scope")
+	}
+}
+
+func TestAPIKeysCurrentFallsBackToList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/keys/current":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{ErrorMsg: "not found"})
+		case "/keys":
+			json.NewEncoder(w).Encode(APIKeyListResponse{Keys: []APIKey{
+				{KeyID: "k1", Prefix: "tc_live_abc1", Scopes: []APIKeyScope{ScopePersonasRead}},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	client := NewToneCloneClient("tc_live_abc1234567", WithBaseURL(server.URL))
+
+	key, err := client.APIKeys.Current(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.KeyID != "k1" {
+		t.Errorf("Expected k1, got %s", key.KeyID)
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	granted := []APIKeyScope{ScopePersonasRead, ScopeTextGenerate}
+
+	missing := MissingScopes(granted, []APIKeyScope{ScopePersonasRead, ScopeTrainingWrite})
+	if len(missing) != 1 || missing[0] != ScopeTrainingWrite {
+		t.Errorf("Expected training:write to be missing, got %v", missing)
+	}
+
+	if len(MissingScopes([]APIKeyScope{ScopeAll}, []APIKeyScope{ScopeTrainingWrite})) != 0 {
+		t.Error("Expected wildcard scope to grant everything")
+	}
+	if len(MissingScopes(nil, []APIKeyScope{ScopeUserRead})) != 0 {
+		t.Error("Expected a key without scopes to be unrestricted")
+	}
+}
@@ -0,0 +1,377 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// cassetteVersion is the format version written to cassette files
+const cassetteVersion = 1
+
+// CassetteMode is whether a cassette records or replays API traffic
+type CassetteMode string
+
+const (
+	// CassetteRecord sends requests to the API and saves the exchanges
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay answers requests from saved exchanges, without network
+	CassetteReplay CassetteMode = "replay"
+)
+
+// recordedHeaders are the request headers saved in a cassette. Credentials
+// are never saved.
+var recordedHeaders = []string{"Accept", "Content-Type", "TC-API-Version"}
+
+// CassetteRequest is a recorded request. URL is the path and query only, so
+// a cassette replays against any base URL.
+type CassetteRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response
+type CassetteResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type cassetteFile struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Matcher reports whether an incoming request matches a recorded one. The
+// incoming request is already scrubbed like a recorded one.
+type Matcher func(incoming, recorded CassetteRequest) bool
+
+// MatchMethodAndURL matches requests on method, path and query
+func MatchMethodAndURL(incoming, recorded CassetteRequest) bool {
+	return incoming.Method == recorded.Method && incoming.URL == recorded.URL
+}
+
+// MatchBody matches requests on method, path, query and body. JSON bodies
+// match when they are equal after decoding, whatever their formatting.
+func MatchBody(incoming, recorded CassetteRequest) bool {
+	if !MatchMethodAndURL(incoming, recorded) {
+		return false
+	}
+	if incoming.Body == recorded.Body {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal([]byte(incoming.Body), &a) != nil || json.Unmarshal([]byte(recorded.Body), &b) != nil {
+		return false
+	}
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// Cassette records API exchanges to a file or replays them, so CLI flows
+// can be tested and demoed without an account or network. Secrets are
+// scrubbed before anything is saved.
+type Cassette struct {
+	Path string
+	Mode CassetteMode
+	// Match selects the recorded exchange for a request. Nil uses MatchBody.
+	Match Matcher
+	// Session, when set, shares replay progress between processes: each
+	// replaying process of a session picks up after the exchanges used by
+	// the ones before it, so a script of several commands replays in
+	// order. A new session starts from the beginning. Without a session,
+	// every replay starts from the beginning.
+	Session string
+
+	mu       sync.Mutex
+	file     cassetteFile
+	used     []bool
+	progress bool // session progress was loaded
+}
+
+// replayProgress is the replay state shared by the processes of a session
+type replayProgress struct {
+	Session      string `json:"session"`
+	Interactions int    `json:"interactions"` // cassette size, to notice re-recording
+	Used         []int  `json:"used"`
+}
+
+// OpenCassette loads the cassette at path for mode. Recording appends to an
+// existing cassette, so several CLI runs can record one flow; replaying
+// requires the file to exist.
+func OpenCassette(path string, mode CassetteMode) (*Cassette, error) {
+	cassette := &Cassette{Path: path, Mode: mode, file: cassetteFile{Version: cassetteVersion}}
+
+	switch mode {
+	case CassetteRecord, CassetteReplay:
+	default:
+		return nil, fmt.Errorf("unknown cassette mode '%s'", mode)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && mode == CassetteRecord {
+			return cassette, nil
+		}
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &cassette.file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if cassette.file.Version > cassetteVersion {
+		return nil, fmt.Errorf("cassette %s has version %d; this CLI supports up to %d", path, cassette.file.Version, cassetteVersion)
+	}
+	cassette.used = make([]bool, len(cassette.file.Interactions))
+
+	return cassette, nil
+}
+
+// Interactions returns the cassette's exchanges
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.file.Interactions...)
+}
+
+// WithCassette records the client's API exchanges to cassette or answers
+// them from it, depending on its mode
+func WithCassette(cassette *Cassette) ClientOption {
+	return func(c *Client) {
+		c.cassette = cassette
+	}
+}
+
+// cassetteTransport records through base or replays without it
+type cassetteTransport struct {
+	base     http.RoundTripper
+	cassette *Cassette
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := captureRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	incoming := scrubRequest(req, body)
+
+	if t.cassette.Mode == CassetteReplay {
+		return t.cassette.replay(req, incoming)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := captureResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: incoming,
+		Response: CassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: scrubHeaders(resp.Header, nil),
+			Body:    Redact(string(respBody)),
+		},
+	}
+	if err := t.cassette.record(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// record appends an interaction and saves the cassette, so nothing is lost
+// if the process exits early
+func (c *Cassette) record(interaction Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.file.Interactions = append(c.file.Interactions, interaction)
+	c.used = append(c.used, true)
+
+	data, err := json.MarshalIndent(c.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if dir := filepath.Dir(c.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+	}
+	if err := writeFile(c.Path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// writeFile writes to a temporary file first so a failed write keeps the
+// old contents
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// progressPath returns the file holding the session's replay progress. It
+// lives in the temporary directory so replaying never touches the cassette
+// or its directory.
+func (c *Cassette) progressPath() string {
+	path, err := filepath.Abs(c.Path)
+	if err != nil {
+		path = c.Path
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("toneclone-replay-%x.json", sum[:8]))
+}
+
+// loadProgress marks the exchanges used earlier in the session, unless the
+// progress belongs to another session or an older recording
+func (c *Cassette) loadProgress() {
+	if c.Session == "" || c.progress {
+		return
+	}
+	c.progress = true
+
+	data, err := os.ReadFile(c.progressPath())
+	if err != nil {
+		return
+	}
+	var progress replayProgress
+	if json.Unmarshal(data, &progress) != nil || progress.Session != c.Session || progress.Interactions != len(c.used) {
+		return
+	}
+	for _, i := range progress.Used {
+		if i >= 0 && i < len(c.used) {
+			c.used[i] = true
+		}
+	}
+}
+
+// saveProgress records the exchanges used so far in the session
+func (c *Cassette) saveProgress() error {
+	if c.Session == "" {
+		return nil
+	}
+
+	progress := replayProgress{Session: c.Session, Interactions: len(c.used), Used: []int{}}
+	for i, used := range c.used {
+		if used {
+			progress.Used = append(progress.Used, i)
+		}
+	}
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay progress: %w", err)
+	}
+	if err := writeFile(c.progressPath(), data); err != nil {
+		return fmt.Errorf("failed to save replay progress: %w", err)
+	}
+	return nil
+}
+
+// replay answers a request with the first unused matching interaction, in
+// recorded order. Once all matches are used the last one is repeated.
+func (c *Cassette) replay(req *http.Request, incoming CassetteRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadProgress()
+
+	match := c.Match
+	if match == nil {
+		match = MatchBody
+	}
+
+	found := -1
+	for i, interaction := range c.file.Interactions {
+		if !match(incoming, interaction.Request) {
+			continue
+		}
+		found = i
+		if !c.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("no recorded response in %s for %s %s", c.Path, incoming.Method, incoming.URL)
+	}
+	c.used[found] = true
+	if err := c.saveProgress(); err != nil {
+		return nil, err
+	}
+
+	recorded := c.file.Interactions[found].Response
+	header := make(http.Header)
+	for name, value := range recorded.Headers {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// scrubRequest returns the parts of a request saved in a cassette, with
+// secrets removed
+func scrubRequest(req *http.Request, body []byte) CassetteRequest {
+	return CassetteRequest{
+		Method:  req.Method,
+		URL:     Redact(req.URL.RequestURI()),
+		Headers: scrubHeaders(req.Header, recordedHeaders),
+		Body:    Redact(string(body)),
+	}
+}
+
+// scrubHeaders returns the named headers, or all headers if names is nil,
+// leaving out credentials and per-request noise
+func scrubHeaders(header http.Header, names []string) map[string]string {
+	if names == nil {
+		for name := range header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	scrubbed := make(map[string]string)
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		if secretHeaders[canonical] || canonical == "Date" || canonical == "Content-Length" {
+			continue
+		}
+		if value := header.Get(name); value != "" {
+			scrubbed[canonical] = Redact(value)
+		}
+	}
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return scrubbed
+}
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// cassetteServer answers every request with a counter, so replays can be
+// told apart from fresh responses
+func cassetteServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		switch r.URL.Path {
+		case "/api-keys":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"key": "tc_live_newsecret", "call": n})
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"path": r.URL.Path, "call": n})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	server, calls := cassetteServer(t)
+	path := filepath.Join(t.TempDir(), "flows", "cassette.json")
+
+	recorder, err := OpenCassette(path, CassetteRecord)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	client := NewClient("tc_live_mysecret", WithBaseURL(server.URL), WithCassette(recorder))
+
+	ctx := context.Background()
+	var first, second, created map[string]interface{}
+	if err := client.Get(ctx, "/personas", &first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.Get(ctx, "/personas", &second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.Post(ctx, "/api-keys", map[string]string{"name": "ci"}, &created); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.Get(ctx, "/missing", nil); err == nil {
+		t.Fatal("Expected error for 404")
+	}
+
+	// The caller sees real secrets, the cassette does not
+	if created["key"] != "tc_live_newsecret" {
+		t.Errorf("Expected the real key while recording, got %v", created["key"])
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	for _, secret := range []string{"mysecret", "newsecret", "session=abc", server.URL} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	// Replay against a dead server, in the recorded order
+	server.Close()
+	recorded := atomic.LoadInt32(calls)
+
+	player, err := OpenCassette(path, CassetteReplay)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	if len(player.Interactions()) != 4 {
+		t.Fatalf("Expected 4 interactions, got %d", len(player.Interactions()))
+	}
+	client = NewClient("tc_live_other", WithBaseURL("http://replay.invalid"), WithCassette(player))
+
+	var replayed map[string]interface{}
+	for i, want := range []float64{1, 2, 2} {
+		if err := client.Get(ctx, "/personas", &replayed); err != nil {
+			t.Fatalf("Replay %d failed: %v", i, err)
+		}
+		if replayed["call"] != want {
+			t.Errorf("Replay %d: expected call %v, got %v", i, want, replayed["call"])
+		}
+	}
+
+	// JSON bodies match whatever their formatting
+	if err := client.Post(ctx, "/api-keys", json.RawMessage(`{ "name" : "ci" }`), &replayed); err != nil {
+		t.Fatalf("Replay of POST failed: %v", err)
+	}
+	if replayed["key"] != "tc_live_[REDACTED]" {
+		t.Errorf("Expected scrubbed key, got %v", replayed["key"])
+	}
+
+	err = client.Get(ctx, "/missing", nil)
+	if apiErr, ok := err.(ErrorResponse); !ok || apiErr.ErrorMsg != "not found" {
+		t.Errorf("Expected replayed 404, got %v", err)
+	}
+
+	if atomic.LoadInt32(calls) != recorded {
+		t.Error("Replay reached the network")
+	}
+}
+
+func TestCassetteReplayMismatch(t *testing.T) {
+	server, _ := cassetteServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, _ := OpenCassette(path, CassetteRecord)
+	client := NewClient("tc_live_mysecret", WithBaseURL(server.URL), WithCassette(recorder))
+	if err := client.Post(context.Background(), "/api-keys", map[string]string{"name": "ci"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	player, _ := OpenCassette(path, CassetteReplay)
+	client = NewClient("tc_live_mysecret", WithCassette(player))
+
+	err := client.Post(context.Background(), "/api-keys", map[string]string{"name": "prod"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("Expected mismatch error for a different body, got %v", err)
+	}
+
+	// Matching on method and URL ignores the body
+	player.Match = MatchMethodAndURL
+	if err := client.Post(context.Background(), "/api-keys", map[string]string{"name": "prod"}, nil); err != nil {
+		t.Errorf("Expected URL match, got %v", err)
+	}
+}
+
+func TestCassetteReplaySession(t *testing.T) {
+	server, _ := cassetteServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv("TMPDIR", t.TempDir())
+	ctx := context.Background()
+
+	// Each step stands for a separate CLI run
+	steps := []func(c *Client) error{
+		func(c *Client) error { return c.Get(ctx, "/personas", nil) },
+		func(c *Client) error { return c.Post(ctx, "/personas/new", nil, nil) },
+		func(c *Client) error { return c.Get(ctx, "/personas", nil) },
+	}
+	for _, step := range steps {
+		recorder, _ := OpenCassette(path, CassetteRecord)
+		if err := step(NewClient("tc_live_mysecret", WithBaseURL(server.URL), WithCassette(recorder))); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	replayList := func(session string) float64 {
+		t.Helper()
+		player, err := OpenCassette(path, CassetteReplay)
+		if err != nil {
+			t.Fatalf("Failed to open cassette: %v", err)
+		}
+		player.Session = session
+		var listed map[string]interface{}
+		if err := NewClient("tc_live_mysecret", WithCassette(player)).Get(ctx, "/personas", &listed); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return listed["call"].(float64)
+	}
+
+	if call := replayList("script"); call != 1 {
+		t.Errorf("Expected the first listing, got call %v", call)
+	}
+	player, _ := OpenCassette(path, CassetteReplay)
+	player.Session = "script"
+	if err := NewClient("tc_live_mysecret", WithCassette(player)).Post(ctx, "/personas/new", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if call := replayList("script"); call != 3 {
+		t.Errorf("Expected the listing recorded after the create, got call %v", call)
+	}
+
+	// Another session starts over
+	if call := replayList("rerun"); call != 1 {
+		t.Errorf("Expected a new session to start from the first listing, got call %v", call)
+	}
+
+	// Without a session, every replay starts over
+	player, _ = OpenCassette(path, CassetteReplay)
+	if err := NewClient("tc_live_mysecret", WithCassette(player)).Post(ctx, "/personas/new", nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if call := replayList(""); call != 1 {
+			t.Errorf("Run %d: expected a replay without a session to start from the first listing, got call %v", i+1, call)
+		}
+	}
+}
+
+func TestCassetteRecordAppends(t *testing.T) {
+	server, _ := cassetteServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	for _, persona := range []string{"/personas/a", "/personas/b"} {
+		recorder, err := OpenCassette(path, CassetteRecord)
+		if err != nil {
+			t.Fatalf("Failed to open cassette: %v", err)
+		}
+		client := NewClient("tc_live_mysecret", WithBaseURL(server.URL), WithCassette(recorder))
+		if err := client.Get(context.Background(), persona, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	player, err := OpenCassette(path, CassetteReplay)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	if got := len(player.Interactions()); got != 2 {
+		t.Errorf("Expected both runs in the cassette, got %d interactions", got)
+	}
+}
+
+func TestOpenCassetteErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := OpenCassette(filepath.Join(dir, "missing.json"), CassetteReplay); err == nil {
+		t.Error("Expected error replaying a missing cassette")
+	}
+	if _, err := OpenCassette(filepath.Join(dir, "new.json"), "rewind"); err == nil {
+		t.Error("Expected error for an unknown mode")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	os.WriteFile(future, []byte(`{"version": 99, "interactions": []}`), 0600)
+	if _, err := OpenCassette(future, CassetteReplay); err == nil {
+		t.Error("Expected error for a newer cassette version")
+	}
+}
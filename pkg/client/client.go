@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -27,6 +29,18 @@ type Client struct {
 	httpClient *http.Client
 	userAgent  string
 	timeout    time.Duration
+
+	// OAuth tokens used in place of apiKey
+	tokenSource TokenSource
+
+	// Logs requests and responses when set
+	tracer *Tracer
+
+	// Records or replays requests when set
+	cassette *Cassette
+
+	// Diagnostics about requests and retries
+	logger *slog.Logger
 }
 
 // ClientOption represents a configuration option for the client
@@ -39,6 +53,7 @@ func NewClient(apiKey string, options ...ClientOption) *Client {
 		apiKey:    apiKey,
 		userAgent: fmt.Sprintf("toneclone-cli/%s", APIVersion),
 		timeout:   DefaultTimeout,
+		logger:    discardLogger,
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
@@ -49,6 +64,33 @@ func NewClient(apiKey string, options ...ClientOption) *Client {
 		option(client)
 	}
 
+	// Cassettes, logging, tracing and token authentication wrap whatever
+	// transport the options set up. The cassette goes innermost so replayed
+	// traffic is traced, and tracing goes inside so it sees refreshed tokens
+	// and retries.
+	logging := client.logger != discardLogger
+	if client.cassette != nil || logging || client.tracer != nil || client.tokenSource != nil {
+		base := client.httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		if client.cassette != nil {
+			base = &cassetteTransport{base: base, cassette: client.cassette}
+		}
+		if logging {
+			base = &logTransport{base: base, logger: client.logger}
+		}
+		if client.tracer != nil {
+			base = &traceTransport{base: base, tracer: client.tracer}
+		}
+		if client.tokenSource != nil {
+			base = &tokenTransport{base: base, source: client.tokenSource}
+		}
+		httpClient := *client.httpClient
+		httpClient.Transport = base
+		client.httpClient = &httpClient
+	}
+
 	return client
 }
 
@@ -93,6 +135,9 @@ type ErrorResponse struct {
 	ErrorMsg string `json:"error"`
 	Message  string `json:"message,omitempty"`
 	Code     string `json:"code,omitempty"`
+
+	// StatusCode is the HTTP status of the response
+	StatusCode int `json:"-"`
 }
 
 // RateLimitError represents a rate limiting error with retry information
@@ -117,6 +162,29 @@ func (e ErrorResponse) Error() string {
 	return e.ErrorMsg
 }
 
+// StatusCode returns the HTTP status of an API error anywhere in err's
+// chain, or 0 when err did not come from an API response
+func StatusCode(err error) int {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return http.StatusTooManyRequests
+	}
+	var errorResp ErrorResponse
+	if errors.As(err, &errorResp) {
+		return errorResp.StatusCode
+	}
+	return 0
+}
+
+// statusError returns an API error for a response without a JSON error
+// body. The first verb of format is the status.
+func statusError(status int, format string, args ...interface{}) error {
+	return ErrorResponse{
+		ErrorMsg:   fmt.Sprintf(format, append([]interface{}{status}, args...)...),
+		StatusCode: status,
+	}
+}
+
 // makeRequest performs an HTTP request to the API
 func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	// Construct URL
@@ -176,8 +244,12 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		var errorResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errorResp); err != nil {
 			// If we can't parse the error response, return a generic error
-			return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+			return ErrorResponse{
+				ErrorMsg:   fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, string(respBody)),
+				StatusCode: resp.StatusCode,
+			}
 		}
+		errorResp.StatusCode = resp.StatusCode
 		
 		// Handle rate limiting specifically
 		if resp.StatusCode == http.StatusTooManyRequests {
@@ -288,17 +360,27 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 }
 
 // WithContext returns a new context with timeout if none is set
+//
+// Deprecated: the timeout is only released when it expires; use
+// ContextWithTimeout and call its cancel function instead.
 func (c *Client) WithContext(ctx context.Context) context.Context {
+	ctx, _ = c.ContextWithTimeout(ctx)
+	return ctx
+}
+
+// ContextWithTimeout returns a new context with timeout if none is set.
+// The returned cancel function must always be called.
+func (c *Client) ContextWithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
 	// If context doesn't have a deadline, add one based on client timeout
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
-		ctx, _ = context.WithTimeout(ctx, c.httpClient.Timeout)
+		return context.WithTimeout(ctx, c.httpClient.Timeout)
 	}
 
-	return ctx
+	return ctx, func() {}
 }
 
 // doRequestWithRetry performs a request with automatic retry for rate limits
@@ -329,6 +411,8 @@ func (c *Client) doRequestWithRetry(ctx context.Context, method, endpoint string
 			if delay > 60*time.Second {
 				delay = 60 * time.Second
 			}
+
+			c.logger.Info("Rate limited, retrying", "method", method, "path", endpoint, "attempt", attempt+2, "retry_in", delay.String())
 			
 			// Wait before retrying
 			select {
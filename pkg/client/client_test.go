@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -172,6 +174,46 @@ func TestClientErrorHandling(t *testing.T) {
 	}
 }
 
+func TestStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "unauthorized"}`))
+		case "/files":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not json"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewToneCloneClient("test_key", WithBaseURL(server.URL))
+
+	err := client.Get(context.Background(), "/json", nil)
+	if code := StatusCode(fmt.Errorf("wrapped: %w", err)); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for JSON error, got %d (%v)", code, err)
+	}
+
+	err = client.Get(context.Background(), "/text", nil)
+	if code := StatusCode(err); code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for plain error, got %d (%v)", code, err)
+	}
+
+	_, err = client.Training.ListFiles(context.Background())
+	if code := StatusCode(err); code != http.StatusForbidden {
+		t.Errorf("Expected status 403 from training client, got %d (%v)", code, err)
+	}
+
+	if code := StatusCode(&RateLimitError{}); code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 for rate limit error, got %d", code)
+	}
+	if code := StatusCode(errors.New("request failed")); code != 0 {
+		t.Errorf("Expected no status for other errors, got %d", code)
+	}
+}
+
 func TestClientTimeout(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Simulate slow response
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// HAR records HTTP traffic in the HTTP Archive 1.2 format, which browsers
+// and support tools can open. Secrets are redacted as in the trace log.
+type HAR struct {
+	mu  sync.Mutex
+	log harLog
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is one request and its response
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"` // milliseconds
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	// Error is set when no response was received
+	Error string `json:"_error,omitempty"`
+}
+
+// HARRequest is the request of a HAR entry
+type HARRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []HARHeader  `json:"headers"`
+	QueryString []HARHeader  `json:"queryString"`
+	Cookies     []HARHeader  `json:"cookies"`
+	PostData    *HARPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+// HARResponse is the response of a HAR entry
+type HARResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []HARHeader `json:"headers"`
+	Cookies     []HARHeader `json:"cookies"`
+	Content     HARContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// HARHeader is a name/value pair, used for headers and query parameters
+type HARHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is a request body
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent is a response body
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings splits an entry's time. The client only measures the wait.
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHAR returns an empty HAR log created by the given CLI version
+func NewHAR(version string) *HAR {
+	return &HAR{log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "toneclone-cli", Version: version},
+		Entries: []HAREntry{},
+	}}
+}
+
+// Entries returns the recorded entries
+func (h *HAR) Entries() []HAREntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]HAREntry(nil), h.log.Entries...)
+}
+
+// WriteFile saves the HAR log to path
+func (h *HAR) WriteFile(path string) error {
+	h.mu.Lock()
+	data, err := json.MarshalIndent(map[string]harLog{"log": h.log}, "", "  ")
+	h.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode HAR: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write HAR file: %w", err)
+	}
+	return nil
+}
+
+// add records a request and its response, or the error that replaced it
+func (h *HAR) add(start time.Time, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, elapsed time.Duration, err error) {
+	millis := float64(elapsed.Microseconds()) / 1000
+
+	entry := HAREntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Time:            millis,
+		Request: HARRequest{
+			Method:      req.Method,
+			URL:         Redact(req.URL.String()),
+			HTTPVersion: req.Proto,
+			Headers:     sortedHeaders(req.Header),
+			QueryString: sortedHeaders(http.Header(req.URL.Query())),
+			Cookies:     []HARHeader{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: HARResponse{
+			Headers:     []HARHeader{},
+			Cookies:     []HARHeader{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: HARTimings{Wait: millis},
+	}
+
+	if len(reqBody) > 0 {
+		contentType := req.Header.Get("Content-Type")
+		entry.Request.PostData = &HARPostData{MimeType: contentType, Text: harText(contentType, reqBody)}
+	}
+
+	if err != nil {
+		entry.Error = Redact(err.Error())
+	} else {
+		contentType := resp.Header.Get("Content-Type")
+		entry.Response.Status = resp.StatusCode
+		entry.Response.StatusText = http.StatusText(resp.StatusCode)
+		entry.Response.HTTPVersion = resp.Proto
+		entry.Response.Headers = sortedHeaders(resp.Header)
+		entry.Response.RedirectURL = resp.Header.Get("Location")
+		entry.Response.BodySize = len(respBody)
+		entry.Response.Content = HARContent{
+			Size:     len(respBody),
+			MimeType: contentType,
+			Text:     harText(contentType, respBody),
+		}
+		if !isTextContent(contentType) {
+			entry.Response.Content.Comment = "binary content omitted"
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.log.Entries = append(h.log.Entries, entry)
+}
+
+// harText returns a body for a HAR entry. Binary bodies such as uploaded
+// files are left out.
+func harText(contentType string, body []byte) string {
+	if !isTextContent(contentType) {
+		return ""
+	}
+	return Redact(string(body))
+}
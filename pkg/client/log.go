@@ -0,0 +1,41 @@
+package client
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// discardLogger is used when no logger is set
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// WithLogger logs each API request at debug level, and rate limit retries
+// at info level, to logger
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			logger = discardLogger
+		}
+		c.logger = logger
+	}
+}
+
+// logTransport logs a line per request to the client's logger
+type logTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Milliseconds()
+
+	if err != nil {
+		t.logger.Debug("API request failed", "method", req.Method, "path", req.URL.Path, "duration_ms", elapsed, "error", err)
+		return nil, err
+	}
+	t.logger.Debug("API request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "duration_ms", elapsed)
+	return resp, nil
+}
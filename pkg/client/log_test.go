@@ -0,0 +1,71 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoggerLogsRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Rate limit the first request so the retry is logged
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"rate_limited"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("tc_live_secret123", WithBaseURL(server.URL), WithLogger(logger))
+
+	if err := client.Get(context.Background(), "/personas", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(log.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d:\n%s", len(entries), log.String())
+	}
+	if entries[0]["msg"] != "API request" || entries[0]["status"] != float64(429) || entries[0]["path"] != "/personas" {
+		t.Errorf("Unexpected first entry: %v", entries[0])
+	}
+	if entries[1]["msg"] != "Rate limited, retrying" || entries[1]["level"] != "INFO" {
+		t.Errorf("Unexpected retry entry: %v", entries[1])
+	}
+	if entries[2]["status"] != float64(200) {
+		t.Errorf("Unexpected last entry: %v", entries[2])
+	}
+	if strings.Contains(log.String(), "secret123") {
+		t.Errorf("Log contains the API key:\n%s", log.String())
+	}
+}
+
+func TestWithoutLoggerIsQuiet(t *testing.T) {
+	client := NewClient("tc_live_secret123", WithLogger(nil))
+	if client.logger != discardLogger {
+		t.Error("Expected a nil logger to discard output")
+	}
+	if _, ok := client.httpClient.Transport.(*logTransport); ok {
+		t.Error("Expected no logging transport without a logger")
+	}
+}
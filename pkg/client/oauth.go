@@ -0,0 +1,332 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOAuthClientID identifies the CLI to the authorization server
+const DefaultOAuthClientID = "toneclone-cli"
+
+// pollUnit is the unit of the device grant's polling interval. Tests
+// shorten it.
+var pollUnit = time.Second
+
+// OAuth error codes of the device authorization grant (RFC 8628)
+const (
+	OAuthAuthorizationPending = "authorization_pending"
+	OAuthSlowDown             = "slow_down"
+	OAuthAccessDenied         = "access_denied"
+	OAuthExpiredToken         = "expired_token"
+	OAuthInvalidGrant         = "invalid_grant"
+)
+
+// DeviceAuth implements the OAuth 2.0 device authorization grant, which
+// lets a user approve the CLI in a browser on any device
+type DeviceAuth struct {
+	ClientID      string
+	DeviceAuthURL string
+	TokenURL      string
+	Scopes        []string
+
+	httpClient *http.Client
+}
+
+// DeviceCode is the authorization server's answer to a device
+// authorization request
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"` // seconds
+	Interval                int    `json:"interval"`   // seconds between polls
+}
+
+// Token is an OAuth access token and the refresh token that renews it
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresIn    int       `json:"expires_in,omitempty"` // seconds
+	Expiry       time.Time `json:"-"`
+}
+
+// OAuthError is an error response from the authorization server
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+// NewDeviceAuth returns a device grant client for the authorization server
+// at baseURL/oauth. The options configure its HTTP client, such as TLS and
+// proxy settings, as for NewClient.
+func NewDeviceAuth(baseURL, clientID string, options ...ClientOption) *DeviceAuth {
+	if clientID == "" {
+		clientID = DefaultOAuthClientID
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	return &DeviceAuth{
+		ClientID:      clientID,
+		DeviceAuthURL: baseURL + "/oauth/device/code",
+		TokenURL:      baseURL + "/oauth/token",
+		httpClient:    NewClient("", options...).httpClient,
+	}
+}
+
+// RequestCode starts a device login and returns the code for the user to
+// enter at the verification URI
+func (d *DeviceAuth) RequestCode(ctx context.Context) (*DeviceCode, error) {
+	values := url.Values{"client_id": {d.ClientID}}
+	if len(d.Scopes) > 0 {
+		values.Set("scope", strings.Join(d.Scopes, " "))
+	}
+
+	var code DeviceCode
+	if err := d.postForm(ctx, d.DeviceAuthURL, values, &code); err != nil {
+		return nil, fmt.Errorf("failed to start device login: %w", err)
+	}
+	if code.DeviceCode == "" || code.UserCode == "" || code.VerificationURI == "" {
+		return nil, fmt.Errorf("failed to start device login: incomplete response from %s", d.DeviceAuthURL)
+	}
+	return &code, nil
+}
+
+// PollToken waits for the user to approve the device code and returns the
+// issued token. It polls at the interval the server asks for and stops when
+// the code expires, the user denies access or ctx is done.
+func (d *DeviceAuth) PollToken(ctx context.Context, code *DeviceCode) (*Token, error) {
+	interval := time.Duration(code.Interval) * pollUnit
+	if interval <= 0 {
+		interval = 5 * pollUnit
+	}
+	var expired <-chan time.Time
+	if code.ExpiresIn > 0 {
+		timer := time.NewTimer(time.Duration(code.ExpiresIn) * pollUnit)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	values := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {code.DeviceCode},
+		"client_id":   {d.ClientID},
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-expired:
+			return nil, fmt.Errorf("device code expired before it was approved")
+		case <-time.After(interval):
+		}
+
+		token, err := d.requestToken(ctx, values)
+		if err == nil {
+			return token, nil
+		}
+
+		oauthErr, ok := err.(*OAuthError)
+		if !ok {
+			return nil, err
+		}
+		switch oauthErr.Code {
+		case OAuthAuthorizationPending:
+		case OAuthSlowDown:
+			interval += 5 * pollUnit
+		case OAuthAccessDenied:
+			return nil, fmt.Errorf("login was denied")
+		case OAuthExpiredToken:
+			return nil, fmt.Errorf("device code expired before it was approved")
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Refresh exchanges a refresh token for a new access token
+func (d *DeviceAuth) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	token, err := d.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {d.ClientID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh access token: %w", err)
+	}
+
+	// Servers that do not rotate refresh tokens leave it out
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// requestToken calls the token endpoint and fills in the token's expiry
+func (d *DeviceAuth) requestToken(ctx context.Context, values url.Values) (*Token, error) {
+	var token Token
+	if err := d.postForm(ctx, d.TokenURL, values, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("no access token in response from %s", d.TokenURL)
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// postForm posts form values and decodes the JSON response into result.
+// OAuth error responses are returned as *OAuthError.
+func (d *DeviceAuth) postForm(ctx context.Context, endpoint string, values url.Values, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var oauthErr OAuthError
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Code != "" {
+			return &oauthErr
+		}
+		return fmt.Errorf("request to %s failed with status %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// TokenSource supplies OAuth access tokens in place of an API key
+type TokenSource interface {
+	// Token returns the access token to send
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a new access token after the API rejected the given one
+	Refresh(ctx context.Context, rejected string) (string, error)
+}
+
+// WithTokenSource authenticates requests with tokens from source instead of
+// the API key. When the API answers 401, the token is refreshed and the
+// request is sent once more.
+func WithTokenSource(source TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSource = source
+	}
+}
+
+// tokenTransport sets the Authorization header from a TokenSource and
+// retries once with a refreshed token when a request is rejected
+type tokenTransport struct {
+	base   http.RoundTripper
+	source TokenSource
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withBearer(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Only requests whose body can be replayed are retried
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	refreshed, err := t.source.Refresh(req.Context(), token)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	retry := withBearer(req, refreshed)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// withBearer returns a copy of req authorized with token
+func withBearer(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	return clone
+}
+
+// RefreshingTokenSource returns token until the API rejects it, then the
+// token returned by refresh. A nil refresh never refreshes.
+func RefreshingTokenSource(token string, refresh func(ctx context.Context) (string, error)) TokenSource {
+	return &refreshingTokenSource{token: token, refresh: refresh}
+}
+
+type refreshingTokenSource struct {
+	mu      sync.Mutex
+	token   string
+	refresh func(ctx context.Context) (string, error)
+}
+
+func (s *refreshingTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+func (s *refreshingTokenSource) Refresh(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another request already replaced the rejected token
+	if s.token != rejected {
+		return s.token, nil
+	}
+	if s.refresh == nil {
+		return "", fmt.Errorf("access token was rejected and cannot be refreshed")
+	}
+
+	token, err := s.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	return token, nil
+}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// authServer is a stand-in authorization server and API. The device code
+// is approved after pending polls, and the API only accepts the latest
+// access token.
+type authServer struct {
+	mu        sync.Mutex
+	pending   int
+	deny      bool
+	slowDown  bool
+	issued    int
+	access    string
+	refresh   string
+	polls     int
+	refreshes int
+	apiCalls  []string
+}
+
+func (s *authServer) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *authServer) issue(w http.ResponseWriter) {
+	s.issued++
+	s.access = fmt.Sprintf("access-%d", s.issued)
+	s.refresh = fmt.Sprintf("refresh-%d", s.issued)
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  s.access,
+		"refresh_token": s.refresh,
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+	})
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/oauth/device/code":
+		r.ParseForm()
+		if r.Form.Get("client_id") != DefaultOAuthClientID {
+			s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+			return
+		}
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"device_code":      "device-123",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": "https://toneclone.test/device",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	case "/oauth/token":
+		r.ParseForm()
+		switch r.Form.Get("grant_type") {
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			s.polls++
+			switch {
+			case s.deny:
+				s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": OAuthAccessDenied})
+			case s.slowDown && s.polls == 1:
+				s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": OAuthSlowDown})
+			case s.polls <= s.pending:
+				s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": OAuthAuthorizationPending})
+			default:
+				s.issue(w)
+			}
+		case "refresh_token":
+			s.refreshes++
+			if r.Form.Get("refresh_token") != s.refresh {
+				s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": OAuthInvalidGrant})
+				return
+			}
+			s.issue(w)
+		default:
+			s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		}
+	default:
+		s.apiCalls = append(s.apiCalls, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer "+s.access {
+			s.writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		s.writeJSON(w, http.StatusOK, map[string]string{"email": "user@example.com"})
+	}
+}
+
+func shortPollUnit(t *testing.T) {
+	t.Helper()
+	pollUnit = time.Millisecond
+	t.Cleanup(func() { pollUnit = time.Second })
+}
+
+func TestDeviceLogin(t *testing.T) {
+	shortPollUnit(t)
+	auth := &authServer{pending: 2}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	deviceAuth := NewDeviceAuth(server.URL, "")
+	code, err := deviceAuth.RequestCode(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to request device code: %v", err)
+	}
+	if code.UserCode != "ABCD-EFGH" || code.VerificationURI != "https://toneclone.test/device" {
+		t.Errorf("Unexpected device code: %+v", code)
+	}
+
+	token, err := deviceAuth.PollToken(context.Background(), code)
+	if err != nil {
+		t.Fatalf("Failed to poll for token: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+	if token.Expiry.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("Expected expiry about an hour away, got %v", token.Expiry)
+	}
+	if auth.polls != 3 {
+		t.Errorf("Expected 3 polls, got %d", auth.polls)
+	}
+}
+
+func TestDeviceLoginSlowDown(t *testing.T) {
+	shortPollUnit(t)
+	auth := &authServer{slowDown: true}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	deviceAuth := NewDeviceAuth(server.URL, "")
+	start := time.Now()
+	_, err := deviceAuth.PollToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 1, ExpiresIn: 600})
+	if err != nil {
+		t.Fatalf("Failed to poll for token: %v", err)
+	}
+
+	// One interval, then one more with 5 units added
+	if elapsed := time.Since(start); elapsed < 7*pollUnit {
+		t.Errorf("Expected polling to slow down, took %v", elapsed)
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	shortPollUnit(t)
+	server := httptest.NewServer(&authServer{deny: true})
+	defer server.Close()
+
+	_, err := NewDeviceAuth(server.URL, "").PollToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 1})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("Expected denied error, got %v", err)
+	}
+}
+
+func TestDeviceLoginExpires(t *testing.T) {
+	shortPollUnit(t)
+	server := httptest.NewServer(&authServer{pending: 1000})
+	defer server.Close()
+
+	_, err := NewDeviceAuth(server.URL, "").PollToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 1, ExpiresIn: 20})
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected expired error, got %v", err)
+	}
+}
+
+func TestDeviceAuthUnknownClient(t *testing.T) {
+	server := httptest.NewServer(&authServer{})
+	defer server.Close()
+
+	_, err := NewDeviceAuth(server.URL, "someone-else").RequestCode(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Expected invalid_client error, got %v", err)
+	}
+}
+
+func TestTokenSourceRefreshesOn401(t *testing.T) {
+	shortPollUnit(t)
+	auth := &authServer{}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	// Issue a first token pair, then rotate it so the client's token is stale
+	deviceAuth := NewDeviceAuth(server.URL, "")
+	first, err := deviceAuth.PollToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 1, ExpiresIn: 5})
+	if err != nil {
+		t.Fatalf("Failed to get token: %v", err)
+	}
+	auth.access = "revoked"
+
+	var saved *Token
+	source := RefreshingTokenSource(first.AccessToken, func(ctx context.Context) (string, error) {
+		token, err := deviceAuth.Refresh(ctx, first.RefreshToken)
+		if err != nil {
+			return "", err
+		}
+		saved = token
+		return token.AccessToken, nil
+	})
+
+	client := NewClient("", WithBaseURL(server.URL), WithTokenSource(source))
+	var user map[string]string
+	if err := client.Post(context.Background(), "/user", map[string]string{"a": "b"}, &user); err != nil {
+		t.Fatalf("Expected request to succeed after refresh: %v", err)
+	}
+	if user["email"] != "user@example.com" {
+		t.Errorf("Unexpected response: %v", user)
+	}
+	if saved == nil || saved.AccessToken != "access-2" || auth.refreshes != 1 {
+		t.Errorf("Expected one refresh to access-2, got %+v after %d refreshes", saved, auth.refreshes)
+	}
+
+	want := []string{"Bearer access-1", "Bearer access-2"}
+	if strings.Join(auth.apiCalls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected calls %v, got %v", want, auth.apiCalls)
+	}
+
+	// Later requests use the refreshed token without refreshing again
+	if err := client.Get(context.Background(), "/user", &user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if auth.refreshes != 1 {
+		t.Errorf("Expected no further refresh, got %d", auth.refreshes)
+	}
+}
+
+func TestTokenSourceRefreshFailure(t *testing.T) {
+	server := httptest.NewServer(&authServer{access: "other", refresh: "other"})
+	defer server.Close()
+
+	deviceAuth := NewDeviceAuth(server.URL, "")
+	source := RefreshingTokenSource("stale", func(ctx context.Context) (string, error) {
+		token, err := deviceAuth.Refresh(ctx, "unknown")
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	})
+
+	client := NewClient("", WithBaseURL(server.URL), WithTokenSource(source))
+	err := client.Get(context.Background(), "/user", nil)
+	if err == nil || !strings.Contains(err.Error(), OAuthInvalidGrant) {
+		t.Errorf("Expected invalid_grant error, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package client
+
+// HasScope reports whether the granted scopes allow the required scope.
+// The wildcard and admin scopes grant everything, and a key with no scopes
+// listed is treated as unrestricted.
+func HasScope(granted []APIKeyScope, required APIKeyScope) bool {
+	if len(granted) == 0 {
+		return true
+	}
+
+	for _, scope := range granted {
+		if scope == required || scope == ScopeAll || scope == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingScopes returns the required scopes that are not granted
+func MissingScopes(granted, required []APIKeyScope) []APIKeyScope {
+	var missing []APIKeyScope
+	for _, scope := range required {
+		if !HasScope(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
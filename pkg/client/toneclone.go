@@ -15,6 +15,7 @@ type ToneCloneClient struct {
 	Generate *GenerateClient
 	Training *TrainingClient
 	Profiles *ProfilesClient
+	APIKeys  *APIKeysClient
 }
 
 // NewToneCloneClient creates a new ToneClone API client with all resource clients
@@ -27,6 +28,7 @@ func NewToneCloneClient(apiKey string, options ...ClientOption) *ToneCloneClient
 		Generate: NewGenerateClient(baseClient),
 		Training: NewTrainingClient(baseClient),
 		Profiles: NewProfilesClient(baseClient),
+		APIKeys:  NewAPIKeysClient(baseClient),
 	}
 }
 
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTraceBodyLimit is how many bytes of each body a Tracer logs
+const DefaultTraceBodyLimit = 2048
+
+// redacted replaces secrets in traces
+const redacted = "[REDACTED]"
+
+// DefaultTraceHeaders are the headers a Tracer logs
+var DefaultTraceHeaders = []string{
+	"Authorization",
+	"Content-Type",
+	"Content-Length",
+	"User-Agent",
+	"TC-API-Version",
+	"X-Request-Id",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"Retry-After",
+	"Location",
+}
+
+// Patterns of secrets removed from traces
+var (
+	apiKeyPattern     = regexp.MustCompile(`\b(tc_(?:live|test)_)[A-Za-z0-9_\-]+`)
+	jsonSecretPattern = regexp.MustCompile(`("(?:access_token|refresh_token|device_code|api_key|apiKey|client_secret|password|secret|token)"\s*:\s*")(?:[^"\\]|\\.)*(")`)
+	formSecretPattern = regexp.MustCompile(`(^|&)(access_token|refresh_token|device_code|client_secret|password)=[^&]*`)
+)
+
+// secretHeaders are never logged or recorded with their values
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// Redact removes API keys, bearer tokens and OAuth secrets from s
+func Redact(s string) string {
+	s = apiKeyPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = jsonSecretPattern.ReplaceAllString(s, "${1}"+redacted+"${2}")
+	s = formSecretPattern.ReplaceAllString(s, "${1}${2}="+redacted)
+	return s
+}
+
+// redactHeader returns the value of a header safe to log
+func redactHeader(name, value string) string {
+	if !secretHeaders[http.CanonicalHeaderKey(name)] {
+		return Redact(value)
+	}
+	// Keep the scheme so a missing "Bearer" is still visible
+	if scheme, _, found := strings.Cut(value, " "); found {
+		return scheme + " " + redacted
+	}
+	return redacted
+}
+
+// Tracer logs the HTTP traffic of a client for debugging: method, URL,
+// status, latency, selected headers and truncated bodies, with secrets
+// redacted. It can also record the traffic for a HAR file.
+type Tracer struct {
+	// Output receives the log. Nil logs nothing.
+	Output io.Writer
+	// MaxBody is how many bytes of each body are logged. Zero uses
+	// DefaultTraceBodyLimit; a negative value logs no bodies.
+	MaxBody int
+	// Headers are the headers logged. Nil uses DefaultTraceHeaders.
+	Headers []string
+	// HAR receives every request and response when set
+	HAR *HAR
+
+	mu sync.Mutex
+}
+
+// WithTracer logs the client's requests and responses with tracer
+func WithTracer(tracer *Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// traceTransport passes requests to base and reports them to a Tracer
+type traceTransport struct {
+	base   http.RoundTripper
+	tracer *Tracer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := captureRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	var respBody []byte
+	if err == nil {
+		if respBody, err = captureResponseBody(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	t.tracer.log(req, reqBody, resp, respBody, elapsed, err)
+	if t.tracer.HAR != nil {
+		t.tracer.HAR.add(start, req, reqBody, resp, respBody, elapsed, err)
+	}
+	return resp, err
+}
+
+// captureRequestBody returns the request body, leaving a fresh copy for
+// the transport to send
+func captureRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// captureResponseBody reads the response body and puts it back for the caller
+func captureResponseBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// log writes one request and its response to the tracer's output
+func (t *Tracer) log(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, elapsed time.Duration, err error) {
+	if t.Output == nil {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--> %s %s\n", req.Method, Redact(req.URL.String()))
+	t.writeHeaders(&b, req.Header)
+	t.writeBody(&b, req.Header.Get("Content-Type"), reqBody)
+
+	latency := elapsed.Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&b, "<-- ERROR %s (%s): %s\n", req.Method, latency, Redact(err.Error()))
+	} else {
+		fmt.Fprintf(&b, "<-- %s %s %s (%s)\n", resp.Status, req.Method, Redact(req.URL.String()), latency)
+		t.writeHeaders(&b, resp.Header)
+		t.writeBody(&b, resp.Header.Get("Content-Type"), respBody)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.Output, b.String())
+}
+
+func (t *Tracer) writeHeaders(b *strings.Builder, header http.Header) {
+	names := t.Headers
+	if names == nil {
+		names = DefaultTraceHeaders
+	}
+	for _, name := range names {
+		for _, value := range header.Values(name) {
+			fmt.Fprintf(b, "    %s: %s\n", name, redactHeader(name, value))
+		}
+	}
+}
+
+func (t *Tracer) writeBody(b *strings.Builder, contentType string, body []byte) {
+	limit := t.MaxBody
+	if limit == 0 {
+		limit = DefaultTraceBodyLimit
+	}
+	if limit < 0 || len(body) == 0 {
+		return
+	}
+
+	if !isTextContent(contentType) {
+		fmt.Fprintf(b, "    <%d bytes of %s>\n", len(body), contentType)
+		return
+	}
+
+	// Redact before truncating so no secret is cut in half and missed
+	text := Redact(string(body))
+	if len(text) <= limit {
+		fmt.Fprintf(b, "    %s\n", text)
+		return
+	}
+	fmt.Fprintf(b, "    %s\n", text[:limit])
+	fmt.Fprintf(b, "    ... (truncated, %d of %d bytes)\n", limit, len(text))
+}
+
+// isTextContent reports whether a body of contentType is worth printing
+func isTextContent(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// sortedHeaders returns the headers as HAR name/value pairs in a stable
+// order, with secrets redacted
+func sortedHeaders(header http.Header) []HARHeader {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]HARHeader, 0, len(names))
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, HARHeader{Name: name, Value: redactHeader(name, value)})
+		}
+	}
+	return headers
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"key tc_live_abc123XYZ here", "key tc_live_[REDACTED] here"},
+		{`{"key":"tc_test_abcdefgh","name":"ci"}`, `{"key":"tc_test_[REDACTED]","name":"ci"}`},
+		{`{"access_token": "eyJabc", "token_type": "Bearer"}`, `{"access_token": "[REDACTED]", "token_type": "Bearer"}`},
+		{`{"refresh_token":"r\"1","expires_in":3600}`, `{"refresh_token":"[REDACTED]","expires_in":3600}`},
+		{"grant_type=refresh_token&refresh_token=abc&client_id=cli", "grant_type=refresh_token&refresh_token=[REDACTED]&client_id=cli"},
+		{"device_code=xyz", "device_code=[REDACTED]"},
+		{"no secrets here", "no secrets here"},
+	}
+
+	for _, test := range tests {
+		if result := Redact(test.input); result != test.expected {
+			t.Errorf("Redact(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestTracerLogsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-42")
+		w.Write([]byte(`{"text":"` + strings.Repeat("a", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	tracer := &Tracer{Output: &log, MaxBody: 40}
+	client := NewClient("tc_live_secret123", WithBaseURL(server.URL), WithTracer(tracer))
+
+	var result map[string]string
+	if err := client.Post(context.Background(), "/query", map[string]string{"prompt": "hi"}, &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result["text"]) != 100 {
+		t.Errorf("Expected the caller to get the full body, got %d bytes", len(result["text"]))
+	}
+
+	output := log.String()
+	for _, want := range []string{
+		"--> POST " + server.URL + "/query",
+		"Authorization: Bearer [REDACTED]",
+		`{"prompt":"hi"}`,
+		"<-- 200 OK POST " + server.URL + "/query (",
+		"X-Request-Id: req-42",
+		"... (truncated, 40 of 111 bytes)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected trace to contain %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "secret123") {
+		t.Errorf("Trace leaked the API key:\n%s", output)
+	}
+}
+
+func TestTracerLogsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var log bytes.Buffer
+	client := NewClient("tc_live_secret123", WithBaseURL(server.URL), WithTracer(&Tracer{Output: &log}))
+	if err := client.Get(context.Background(), "/user", nil); err == nil {
+		t.Fatal("Expected error from a closed server")
+	}
+	if !strings.Contains(log.String(), "<-- ERROR GET") {
+		t.Errorf("Expected the failure in the trace:\n%s", log.String())
+	}
+}
+
+func TestTracerRecordsHAR(t *testing.T) {
+	shortPollUnit(t)
+	auth := &authServer{}
+	server := httptest.NewServer(auth)
+	defer server.Close()
+
+	tracer := &Tracer{HAR: NewHAR("1.2.3")}
+	deviceAuth := NewDeviceAuth(server.URL, "", WithTracer(tracer))
+	token, err := deviceAuth.PollToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 1})
+	if err != nil {
+		t.Fatalf("Failed to get token: %v", err)
+	}
+
+	client := NewClient("", WithBaseURL(server.URL), WithTracer(tracer),
+		WithTokenSource(RefreshingTokenSource(token.AccessToken, nil)))
+	// The API helpers take no query strings, so request the URL directly
+	resp, err := client.httpClient.Get(server.URL + "/user?fields=email")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	harPath := filepath.Join(t.TempDir(), "trace.har")
+	if err := tracer.HAR.WriteFile(harPath); err != nil {
+		t.Fatalf("Failed to write HAR: %v", err)
+	}
+	data, err := os.ReadFile(harPath)
+	if err != nil {
+		t.Fatalf("Failed to read HAR: %v", err)
+	}
+
+	for _, secret := range []string{token.AccessToken, token.RefreshToken, "device-123"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("HAR leaked %q", secret)
+		}
+	}
+
+	var har struct {
+		Log struct {
+			Version string     `json:"version"`
+			Entries []HAREntry `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("Invalid HAR JSON: %v", err)
+	}
+	if har.Log.Version != "1.2" || len(har.Log.Entries) != 2 {
+		t.Fatalf("Expected a 1.2 log with 2 entries, got %s with %d", har.Log.Version, len(har.Log.Entries))
+	}
+
+	tokenEntry, apiEntry := har.Log.Entries[0], har.Log.Entries[1]
+	if tokenEntry.Request.Method != "POST" || tokenEntry.Request.PostData == nil ||
+		!strings.Contains(tokenEntry.Request.PostData.Text, "device_code=[REDACTED]") {
+		t.Errorf("Unexpected token request: %+v", tokenEntry.Request)
+	}
+	if !strings.Contains(tokenEntry.Response.Content.Text, `"access_token":"[REDACTED]"`) {
+		t.Errorf("Expected redacted token response, got %q", tokenEntry.Response.Content.Text)
+	}
+	if apiEntry.Response.Status != 200 || len(apiEntry.Request.QueryString) != 1 || apiEntry.Request.QueryString[0].Value != "email" {
+		t.Errorf("Unexpected API entry: %+v", apiEntry)
+	}
+	for _, header := range apiEntry.Request.Headers {
+		if header.Name == "Authorization" && header.Value != "Bearer [REDACTED]" {
+			t.Errorf("Expected redacted Authorization, got %q", header.Value)
+		}
+	}
+}
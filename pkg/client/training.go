@@ -30,7 +30,7 @@ func (t *TrainingClient) ListFiles(ctx context.Context) ([]TrainingFile, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "request failed with status %d")
 	}
 
 	// Handle empty response (no files)
@@ -110,7 +110,7 @@ func (t *TrainingClient) UploadFile(ctx context.Context, file io.Reader, filenam
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("upload failed with status %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "upload failed with status %d")
 	}
 
 	// Parse response
@@ -231,13 +231,13 @@ func (t *TrainingClient) UploadFileBatch(ctx context.Context, files []FileUpload
 		var errorResp map[string]interface{}
 		if json.Unmarshal(respBody, &errorResp) == nil {
 			if msg, ok := errorResp["error"].(string); ok {
-				return nil, fmt.Errorf("batch upload failed with status %d: %s", resp.StatusCode, msg)
+				return nil, statusError(resp.StatusCode, "batch upload failed with status %d: %s", msg)
 			}
 			if msg, ok := errorResp["message"].(string); ok {
-				return nil, fmt.Errorf("batch upload failed with status %d: %s", resp.StatusCode, msg)
+				return nil, statusError(resp.StatusCode, "batch upload failed with status %d: %s", msg)
 			}
 		}
-		return nil, fmt.Errorf("batch upload failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, statusError(resp.StatusCode, "batch upload failed with status %d: %s", string(respBody))
 	}
 
 	// Parse response
@@ -259,7 +259,7 @@ func (t *TrainingClient) UploadText(ctx context.Context, request *UploadTextRequ
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode, "request failed with status %d")
 	}
 
 	// Handle empty response (backend issue)
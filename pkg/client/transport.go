@@ -0,0 +1,218 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// tlsVersions maps the names accepted by ParseTLSVersion to their constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// WithRootCAs trusts the certificates in pool when verifying the API server.
+// The transport options only apply to clients whose transport is an
+// *http.Transport.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		if transport := c.transport(); transport != nil {
+			transport.TLSClientConfig.RootCAs = pool
+		}
+	}
+}
+
+// WithClientCertificate presents cert to servers that require mutual TLS
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(c *Client) {
+		if transport := c.transport(); transport != nil {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+}
+
+// WithMinTLSVersion refuses connections below the given TLS version, such as
+// tls.VersionTLS12
+func WithMinTLSVersion(version uint16) ClientOption {
+	return func(c *Client) {
+		if transport := c.transport(); transport != nil {
+			transport.TLSClientConfig.MinVersion = version
+		}
+	}
+}
+
+// environmentProxy picks the proxy from the environment; tests replace it
+var environmentProxy = http.ProxyFromEnvironment
+
+// WithProxy sends requests through an HTTP, HTTPS or SOCKS5 proxy, except to
+// hosts matching noProxy. Entries are host names, domain suffixes such as
+// .example.com, IP addresses, CIDR ranges or "*". A nil proxyURL connects
+// directly.
+func WithProxy(proxyURL *url.URL, noProxy []string) ClientOption {
+	return withProxyFunc(func(*http.Request) (*url.URL, error) {
+		return proxyURL, nil
+	}, noProxy)
+}
+
+// WithEnvironmentProxy sends requests through the proxy named by the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables, except to
+// hosts matching noProxy as well
+func WithEnvironmentProxy(noProxy []string) ClientOption {
+	return withProxyFunc(func(req *http.Request) (*url.URL, error) {
+		return environmentProxy(req)
+	}, noProxy)
+}
+
+// withProxyFunc sets the transport proxy to proxy, bypassed for noProxy
+func withProxyFunc(proxy func(*http.Request) (*url.URL, error), noProxy []string) ClientOption {
+	return func(c *Client) {
+		if transport := c.transport(); transport != nil {
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				if MatchNoProxyURL(req.URL, noProxy) {
+					return nil, nil
+				}
+				return proxy(req)
+			}
+		}
+	}
+}
+
+// transport returns the client's *http.Transport, cloning the default
+// transport on first use so options never modify http.DefaultTransport
+func (c *Client) transport() *http.Transport {
+	if c.httpClient.Transport == nil {
+		c.httpClient.Transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	return transport
+}
+
+// LoadCertPool returns the system roots plus the PEM certificates in caFile
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+	}
+	return pool, nil
+}
+
+// LoadClientCertificate loads a PEM certificate and private key for mutual TLS
+func LoadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// ParseTLSVersion converts a version such as "1.2" to its crypto/tls constant
+func ParseTLSVersion(version string) (uint16, error) {
+	value, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(version), "tls")]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version '%s' (use 1.0, 1.1, 1.2 or 1.3)", version)
+	}
+	return value, nil
+}
+
+// ParseProxyURL parses an http, https or socks5 proxy URL. A URL without a
+// scheme is treated as an HTTP proxy.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%s' (use http, https or socks5)", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL '%s': missing host", raw)
+	}
+	return proxyURL, nil
+}
+
+// MatchNoProxyURL reports whether the host of u matches an entry of
+// noProxy, like MatchNoProxy, filling in the default port of the scheme so
+// that entries such as host:443 match https://host
+func MatchNoProxyURL(u *url.URL, noProxy []string) bool {
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "https":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		case "http":
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	return MatchNoProxy(host, noProxy)
+}
+
+// MatchNoProxy reports whether host, with or without a port, matches an
+// entry of a no-proxy list
+func MatchNoProxy(host string, noProxy []string) bool {
+	hostname, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname, port = h, p
+	}
+	hostname = strings.ToLower(hostname)
+	ip := net.ParseIP(hostname)
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		// An entry with a port only matches that port
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+
+		domain := strings.TrimPrefix(entry, ".")
+		if hostname == domain || strings.HasSuffix(hostname, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
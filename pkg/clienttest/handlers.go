@@ -0,0 +1,870 @@
+package clienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// state is the fake API's data. Lists keep creation order.
+type state struct {
+	mu sync.Mutex
+
+	nextID   map[string]int
+	user     client.User
+	builtin  []client.Persona
+	personas []*client.Persona
+	profiles []*client.Profile
+	files    []*client.TrainingFile
+	jobs     []*client.TrainingJob
+	keys     []*apiKey
+
+	personaFiles    map[string][]string
+	personaProfiles map[string][]string
+}
+
+// apiKey is an API key record with its secret
+type apiKey struct {
+	client.APIKey
+	secret string
+}
+
+func newState(seed bool) *state {
+	st := &state{
+		nextID:          make(map[string]int),
+		personaFiles:    make(map[string][]string),
+		personaProfiles: make(map[string][]string),
+		user: client.User{
+			UserID:    "user-1",
+			Email:     "mock@toneclone.test",
+			Name:      "Mock User",
+			CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Plan:      "pro",
+		},
+	}
+
+	if seed {
+		for _, name := range []string{"Professional", "Casual", "Technical Writer"} {
+			st.builtin = append(st.builtin, client.Persona{
+				PersonaID:      "builtin-" + strings.ToLower(strings.ReplaceAll(name, " ", "-")),
+				Name:           name,
+				Status:         "active",
+				TrainingStatus: "trained",
+				PersonaType:    "builtin",
+				IsBuiltIn:      true,
+			})
+		}
+	}
+	return st
+}
+
+// newID returns the next ID for a kind of object, such as persona-3
+func (st *state) newID(kind string) string {
+	st.nextID[kind]++
+	return fmt.Sprintf("%s-%d", kind, st.nextID[kind])
+}
+
+// AddPersona creates a user persona, as POST /personas would
+func (s *Server) AddPersona(name string) client.Persona {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return *s.state.addPersona(client.Persona{Name: name})
+}
+
+// AddProfile creates a profile, as POST /profiles would
+func (s *Server) AddProfile(name, instructions string) client.Profile {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return *s.state.addProfile(client.Profile{Name: name, Instructions: instructions})
+}
+
+// AddFile creates a training file, as POST /files/text would
+func (s *Server) AddFile(filename, content string) client.TrainingFile {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return *s.state.addFile(filename, int64(len(content)), "text", "mock")
+}
+
+// Personas returns the user's personas
+func (s *Server) Personas() []client.Persona {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return copyAll(s.state.personas)
+}
+
+// Files returns the user's training files
+func (s *Server) Files() []client.TrainingFile {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return copyAll(s.state.files)
+}
+
+// Jobs returns the user's training jobs
+func (s *Server) Jobs() []client.TrainingJob {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return copyAll(s.state.jobs)
+}
+
+func copyAll[T any](items []*T) []T {
+	copies := make([]T, len(items))
+	for i, item := range items {
+		copies[i] = *item
+	}
+	return copies
+}
+
+func find[T any](items []*T, match func(*T) bool) *T {
+	for _, item := range items {
+		if match(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+func remove[T any](items []*T, match func(*T) bool) ([]*T, bool) {
+	for i, item := range items {
+		if match(item) {
+			return append(items[:i], items[i+1:]...), true
+		}
+	}
+	return items, false
+}
+
+// removeIDs returns ids without the ones in drop
+func removeIDs(ids, drop []string) []string {
+	var kept []string
+	for _, id := range ids {
+		if !containsID(drop, id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *state) addPersona(persona client.Persona) *client.Persona {
+	now := time.Now().UTC()
+	persona.PersonaID = st.newID("persona")
+	persona.Status = "active"
+	persona.TrainingStatus = "untrained"
+	persona.PersonaType = "user"
+	persona.LastModifiedAt = now
+	persona.IsBuiltIn = false
+	st.personas = append(st.personas, &persona)
+	return &persona
+}
+
+func (st *state) addProfile(profile client.Profile) *client.Profile {
+	now := time.Now().UTC()
+	profile.ProfileID = st.newID("profile")
+	profile.UserID = st.user.UserID
+	profile.PK = "USER#" + st.user.UserID
+	profile.SK = "PROFILE#" + profile.ProfileID
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+	st.profiles = append(st.profiles, &profile)
+	return &profile
+}
+
+func (st *state) addFile(filename string, size int64, contentType, source string) *client.TrainingFile {
+	now := time.Now().UTC()
+	file := &client.TrainingFile{
+		FileID:      st.newID("file"),
+		UserID:      st.user.UserID,
+		FileName:    filename,
+		FileType:    strings.TrimPrefix(filepath.Ext(filename), "."),
+		FileSize:    size,
+		CreatedAt:   now,
+		ModifiedAt:  now,
+		ContentType: contentType,
+		Source:      source,
+	}
+	file.PK = "USER#" + st.user.UserID
+	file.SK = "FILE#" + file.FileID
+	file.S3Key = "mock/" + file.FileID
+	st.files = append(st.files, file)
+	return file
+}
+
+// persona returns a user or built-in persona
+func (st *state) persona(id string) *client.Persona {
+	if persona := find(st.personas, func(p *client.Persona) bool { return p.PersonaID == id }); persona != nil {
+		return persona
+	}
+	for i := range st.builtin {
+		if st.builtin[i].PersonaID == id {
+			return &st.builtin[i]
+		}
+	}
+	return nil
+}
+
+func (st *state) profile(id string) *client.Profile {
+	return find(st.profiles, func(p *client.Profile) bool { return p.ProfileID == id })
+}
+
+func (st *state) file(id string) *client.TrainingFile {
+	return find(st.files, func(f *client.TrainingFile) bool { return f.FileID == id })
+}
+
+// key returns the record of an API key secret. Keys the server accepts but
+// has not seen yet get a record with every scope.
+func (st *state) key(secret string) *apiKey {
+	if key := find(st.keys, func(k *apiKey) bool { return k.secret == secret }); key != nil {
+		return key
+	}
+
+	key := &apiKey{secret: secret, APIKey: client.APIKey{
+		KeyID:     st.newID("key"),
+		Name:      "default",
+		Prefix:    secret[:min(len(secret), 12)],
+		Scopes:    []client.APIKeyScope{client.ScopeAll},
+		Status:    "active",
+		CreatedAt: time.Now().UTC(),
+	}}
+	st.keys = append(st.keys, key)
+	return key
+}
+
+// routes registers the fake endpoints
+func (s *Server) routes() {
+	handle := func(pattern string, handler func(st *state, w http.ResponseWriter, r *http.Request)) {
+		s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			s.state.mu.Lock()
+			defer s.state.mu.Unlock()
+			handler(s.state, w, r)
+		})
+	}
+
+	s.mux.HandleFunc("GET /ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	handle("GET /user", getUser)
+
+	handle("GET /personas", listPersonas)
+	handle("POST /personas", createPersona)
+	handle("GET /personas/builtin", listBuiltInPersonas)
+	handle("GET /personas/{id}", getPersona)
+	handle("PUT /personas/{id}", updatePersona)
+	handle("DELETE /personas/{id}", deletePersona)
+	handle("GET /personas/{id}/files", listPersonaFiles)
+	handle("POST /personas/{id}/files", associatePersonaFiles)
+	handle("DELETE /personas/{id}/files", disassociatePersonaFiles)
+	handle("GET /personas/{id}/profiles", listPersonaProfiles)
+	handle("POST /personas/{id}/profiles", associatePersonaProfiles)
+	handle("DELETE /personas/{id}/profiles", disassociatePersonaProfiles)
+
+	handle("GET /profiles", listProfiles)
+	handle("POST /profiles", createProfile)
+	handle("GET /profiles/{id}", getProfile)
+	handle("PUT /profiles/{id}", updateProfile)
+	handle("DELETE /profiles/{id}", deleteProfile)
+
+	handle("GET /files", listFiles)
+	handle("POST /files", uploadFile)
+	handle("POST /files/batch", uploadFileBatch)
+	handle("POST /files/text", uploadText)
+	handle("GET /files/{id}", getFile)
+	handle("DELETE /files/{id}", deleteFile)
+
+	handle("POST /query", query)
+
+	handle("GET /training/jobs", listJobs)
+	handle("POST /training/jobs", createJob)
+	handle("GET /training/jobs/{id}", getJob)
+	handle("POST /training/personas/{id}", trainPersona)
+
+	handle("GET /keys", listKeys)
+	handle("POST /keys", createKey)
+	handle("GET /keys/current", currentKey)
+	handle("DELETE /keys/{id}", revokeKey)
+}
+
+// decode reads a JSON request body, answering 400 if it is invalid
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func notFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("%s %s not found", kind, id))
+}
+
+func getUser(st *state, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, st.user)
+}
+
+func listPersonas(st *state, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, copyAll(st.personas))
+}
+
+func listBuiltInPersonas(st *state, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, append([]client.Persona{}, st.builtin...))
+}
+
+func createPersona(st *state, w http.ResponseWriter, r *http.Request) {
+	var persona client.Persona
+	if !decode(w, r, &persona) {
+		return
+	}
+	if strings.TrimSpace(persona.Name) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+		return
+	}
+	writeJSON(w, http.StatusCreated, st.addPersona(persona))
+}
+
+func getPersona(st *state, w http.ResponseWriter, r *http.Request) {
+	persona := st.persona(r.PathValue("id"))
+	if persona == nil {
+		notFound(w, "persona", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, persona)
+}
+
+func updatePersona(st *state, w http.ResponseWriter, r *http.Request) {
+	persona := find(st.personas, func(p *client.Persona) bool { return p.PersonaID == r.PathValue("id") })
+	if persona == nil {
+		notFound(w, "persona", r.PathValue("id"))
+		return
+	}
+
+	var update client.Persona
+	if !decode(w, r, &update) {
+		return
+	}
+	if update.Name != "" {
+		persona.Name = update.Name
+	}
+	if update.PromptDescription != "" {
+		persona.PromptDescription = update.PromptDescription
+	}
+	persona.VoiceEvolution = update.VoiceEvolution
+	persona.LastModifiedAt = time.Now().UTC()
+	writeJSON(w, http.StatusOK, persona)
+}
+
+func deletePersona(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var removed bool
+	st.personas, removed = remove(st.personas, func(p *client.Persona) bool { return p.PersonaID == id })
+	if !removed {
+		notFound(w, "persona", id)
+		return
+	}
+	delete(st.personaFiles, id)
+	delete(st.personaProfiles, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listPersonaFiles(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if st.persona(id) == nil {
+		notFound(w, "persona", id)
+		return
+	}
+
+	files := []client.TrainingFile{}
+	for _, fileID := range st.personaFiles[id] {
+		if file := st.file(fileID); file != nil {
+			files = append(files, *file)
+		}
+	}
+	writeJSON(w, http.StatusOK, client.TrainingFileListResponse{Files: files})
+}
+
+// fileIDsRequest is the body of the persona file association endpoints
+type fileIDsRequest struct {
+	FileIDs []string `json:"fileIds"`
+}
+
+func associatePersonaFiles(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var request fileIDsRequest
+	if st.persona(id) == nil {
+		notFound(w, "persona", id)
+		return
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+	for _, fileID := range request.FileIDs {
+		if st.file(fileID) == nil {
+			notFound(w, "file", fileID)
+			return
+		}
+	}
+
+	for _, fileID := range request.FileIDs {
+		if !containsID(st.personaFiles[id], fileID) {
+			st.personaFiles[id] = append(st.personaFiles[id], fileID)
+			st.file(fileID).PersonaID = id
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"personaId": id, "fileIds": st.personaFiles[id]})
+}
+
+func disassociatePersonaFiles(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var request fileIDsRequest
+	if st.persona(id) == nil {
+		notFound(w, "persona", id)
+		return
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	st.personaFiles[id] = removeIDs(st.personaFiles[id], request.FileIDs)
+	for _, fileID := range request.FileIDs {
+		if file := st.file(fileID); file != nil && file.PersonaID == id {
+			file.PersonaID = ""
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listPersonaProfiles(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if st.persona(id) == nil {
+		notFound(w, "persona", id)
+		return
+	}
+
+	profiles := []client.Profile{}
+	for _, profileID := range st.personaProfiles[id] {
+		if profile := st.profile(profileID); profile != nil {
+			profiles = append(profiles, *profile)
+		}
+	}
+	writeJSON(w, http.StatusOK, profiles)
+}
+
+// profileIDsRequest is the body of the persona profile association endpoints
+type profileIDsRequest struct {
+	ProfileIDs []string `json:"profileIds"`
+}
+
+func associatePersonaProfiles(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var request profileIDsRequest
+	if st.persona(id) == nil {
+		notFound(w, "persona", id)
+		return
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+	for _, profileID := range request.ProfileIDs {
+		if st.profile(profileID) == nil {
+			notFound(w, "profile", profileID)
+			return
+		}
+	}
+
+	for _, profileID := range request.ProfileIDs {
+		if !containsID(st.personaProfiles[id], profileID) {
+			st.personaProfiles[id] = append(st.personaProfiles[id], profileID)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"personaId": id, "profileIds": st.personaProfiles[id]})
+}
+
+func disassociatePersonaProfiles(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var request profileIDsRequest
+	if st.persona(id) == nil {
+		notFound(w, "persona", id)
+		return
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	st.personaProfiles[id] = removeIDs(st.personaProfiles[id], request.ProfileIDs)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listProfiles(st *state, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, copyAll(st.profiles))
+}
+
+func createProfile(st *state, w http.ResponseWriter, r *http.Request) {
+	var profile client.Profile
+	if !decode(w, r, &profile) {
+		return
+	}
+	if strings.TrimSpace(profile.Name) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+		return
+	}
+	writeJSON(w, http.StatusCreated, st.addProfile(profile))
+}
+
+func getProfile(st *state, w http.ResponseWriter, r *http.Request) {
+	profile := st.profile(r.PathValue("id"))
+	if profile == nil {
+		notFound(w, "profile", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func updateProfile(st *state, w http.ResponseWriter, r *http.Request) {
+	profile := st.profile(r.PathValue("id"))
+	if profile == nil {
+		notFound(w, "profile", r.PathValue("id"))
+		return
+	}
+
+	var update client.Profile
+	if !decode(w, r, &update) {
+		return
+	}
+	if update.Name != "" {
+		profile.Name = update.Name
+	}
+	if update.Instructions != "" {
+		profile.Instructions = update.Instructions
+	}
+	profile.UpdatedAt = time.Now().UTC()
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func deleteProfile(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var removed bool
+	st.profiles, removed = remove(st.profiles, func(p *client.Profile) bool { return p.ProfileID == id })
+	if !removed {
+		notFound(w, "profile", id)
+		return
+	}
+	for personaID, profileIDs := range st.personaProfiles {
+		st.personaProfiles[personaID] = removeIDs(profileIDs, []string{id})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listFiles(st *state, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, copyAll(st.files))
+}
+
+func uploadFile(st *state, w http.ResponseWriter, r *http.Request) {
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "multipart field 'file' is required")
+		return
+	}
+	defer upload.Close()
+
+	size, _ := io.Copy(io.Discard, upload)
+	writeJSON(w, http.StatusCreated, st.addFile(header.Filename, size, "file", "upload"))
+}
+
+func uploadFileBatch(st *state, w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid multipart body: "+err.Error())
+		return
+	}
+	headers := r.MultipartForm.File["files"]
+	if len(headers) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "multipart field 'files' is required")
+		return
+	}
+
+	personaID := r.FormValue("persona_id")
+	if personaID != "" && st.persona(personaID) == nil {
+		notFound(w, "persona", personaID)
+		return
+	}
+	source := r.FormValue("source")
+	if source == "" {
+		source = "upload"
+	}
+
+	var response client.BatchUploadResponse
+	response.PersonaID = personaID
+	for _, header := range headers {
+		result := client.BatchFileResult{Filename: header.Filename, Size: header.Size}
+		if header.Size == 0 {
+			result.Status = "failed"
+			result.Error = "file is empty"
+			response.Summary.Failed++
+			response.Files = append(response.Files, result)
+			continue
+		}
+
+		file := st.addFile(header.Filename, header.Size, "file", source)
+		result.FileID = file.FileID
+		result.Status = "success"
+		response.Summary.Uploaded++
+		if personaID != "" {
+			st.personaFiles[personaID] = append(st.personaFiles[personaID], file.FileID)
+			file.PersonaID = personaID
+			result.Associated = true
+			response.Summary.Associated++
+		}
+		response.Files = append(response.Files, result)
+	}
+	response.Summary.Total = len(headers)
+
+	status := http.StatusCreated
+	if response.Summary.Failed > 0 {
+		status = http.StatusPartialContent
+	}
+	writeJSON(w, status, response)
+}
+
+func uploadText(st *state, w http.ResponseWriter, r *http.Request) {
+	var request client.UploadTextRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.Content == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "content is required")
+		return
+	}
+	if request.Filename == "" {
+		request.Filename = "text.txt"
+	}
+	if request.Source == "" {
+		request.Source = "text"
+	}
+	writeJSON(w, http.StatusCreated, st.addFile(request.Filename, int64(len(request.Content)), "text", request.Source))
+}
+
+func getFile(st *state, w http.ResponseWriter, r *http.Request) {
+	file := st.file(r.PathValue("id"))
+	if file == nil {
+		notFound(w, "file", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, file)
+}
+
+func deleteFile(st *state, w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var removed bool
+	st.files, removed = remove(st.files, func(f *client.TrainingFile) bool { return f.FileID == id })
+	if !removed {
+		notFound(w, "file", id)
+		return
+	}
+	for personaID, fileIDs := range st.personaFiles {
+		st.personaFiles[personaID] = removeIDs(fileIDs, []string{id})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// query answers text generation requests with a predictable text naming
+// the persona, profiles and prompt
+func query(st *state, w http.ResponseWriter, r *http.Request) {
+	var request client.GenerateTextRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if strings.TrimSpace(request.Prompt) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "prompt is required")
+		return
+	}
+
+	persona := st.persona(request.PersonaID)
+	if persona == nil {
+		notFound(w, "persona", request.PersonaID)
+		return
+	}
+	persona.LastUsedAt = time.Now().UTC()
+
+	profileIDs := request.ProfileIDs
+	if request.ProfileID != "" && !containsID(profileIDs, request.ProfileID) {
+		profileIDs = append([]string{request.ProfileID}, profileIDs...)
+	}
+	var profileNames []string
+	for _, profileID := range profileIDs {
+		profile := st.profile(profileID)
+		if profile == nil {
+			notFound(w, "profile", profileID)
+			return
+		}
+		profileNames = append(profileNames, profile.Name)
+	}
+
+	text := fmt.Sprintf("Mock response in the voice of %s", persona.Name)
+	if len(profileNames) > 0 {
+		text += fmt.Sprintf(" following %s", strings.Join(profileNames, ", "))
+	}
+	text += ": " + request.Prompt
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"content": text, "done": true})
+}
+
+// advance moves a training job one step on each time it is read: pending,
+// then one file processed per read, then completed
+func (st *state) advance(job *client.TrainingJob) {
+	switch job.Status {
+	case "pending":
+		job.Status = "running"
+	case "running":
+		if job.FilesProcessed < job.TotalFiles {
+			job.FilesProcessed++
+		}
+		if job.FilesProcessed >= job.TotalFiles {
+			job.Status = "completed"
+			if persona := st.persona(job.PersonaID); persona != nil {
+				persona.TrainingStatus = "trained"
+			}
+			for _, fileID := range job.FileIDs {
+				if file := st.file(fileID); file != nil {
+					file.UsedForTraining = true
+				}
+			}
+		}
+	default:
+		return
+	}
+	job.UpdatedAt = time.Now().UTC()
+}
+
+func listJobs(st *state, w http.ResponseWriter, r *http.Request) {
+	for _, job := range st.jobs {
+		st.advance(job)
+	}
+	writeJSON(w, http.StatusOK, copyAll(st.jobs))
+}
+
+func getJob(st *state, w http.ResponseWriter, r *http.Request) {
+	job := find(st.jobs, func(j *client.TrainingJob) bool { return j.JobID == r.PathValue("id") })
+	if job == nil {
+		notFound(w, "training job", r.PathValue("id"))
+		return
+	}
+	st.advance(job)
+	writeJSON(w, http.StatusOK, job)
+}
+
+func createJob(st *state, w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		PersonaID string   `json:"persona_id"`
+		FileIDs   []string `json:"file_ids"`
+	}
+	if !decode(w, r, &request) {
+		return
+	}
+	st.startJob(w, request.PersonaID, request.FileIDs)
+}
+
+func trainPersona(st *state, w http.ResponseWriter, r *http.Request) {
+	st.startJob(w, r.PathValue("id"), nil)
+}
+
+// startJob creates a training job for a persona's associated files, or the
+// given ones
+func (st *state) startJob(w http.ResponseWriter, personaID string, fileIDs []string) {
+	persona := find(st.personas, func(p *client.Persona) bool { return p.PersonaID == personaID })
+	if persona == nil {
+		notFound(w, "persona", personaID)
+		return
+	}
+	if len(fileIDs) == 0 {
+		fileIDs = st.personaFiles[personaID]
+	}
+	if len(fileIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "persona has no training files")
+		return
+	}
+	for _, fileID := range fileIDs {
+		if st.file(fileID) == nil {
+			notFound(w, "file", fileID)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	job := &client.TrainingJob{
+		JobID:      st.newID("job"),
+		PersonaID:  personaID,
+		FileIDs:    append([]string(nil), fileIDs...),
+		TotalFiles: len(fileIDs),
+		Status:     "pending",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		BaseModel:  "mock-model",
+	}
+	st.jobs = append(st.jobs, job)
+	persona.TrainingStatus = "training"
+	writeJSON(w, http.StatusCreated, job)
+}
+
+func listKeys(st *state, w http.ResponseWriter, r *http.Request) {
+	st.key(bearer(r))
+
+	keys := []client.APIKey{}
+	for _, key := range st.keys {
+		keys = append(keys, key.APIKey)
+	}
+	writeJSON(w, http.StatusOK, client.APIKeyListResponse{Keys: keys})
+}
+
+func createKey(st *state, w http.ResponseWriter, r *http.Request) {
+	var request client.CreateAPIKeyRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+		return
+	}
+
+	id := st.newID("key")
+	secret := fmt.Sprintf("tc_test_mock%s", strings.ReplaceAll(id, "-", ""))
+	key := &apiKey{secret: secret, APIKey: client.APIKey{
+		KeyID:     id,
+		Name:      request.Name,
+		Prefix:    secret[:12],
+		Scopes:    request.Scopes,
+		Status:    "active",
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: request.ExpiresAt,
+	}}
+	st.keys = append(st.keys, key)
+	writeJSON(w, http.StatusCreated, client.CreateAPIKeyResponse{APIKey: key.APIKey, Key: secret})
+}
+
+func currentKey(st *state, w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, st.key(bearer(r)).APIKey)
+}
+
+func revokeKey(st *state, w http.ResponseWriter, r *http.Request) {
+	key := find(st.keys, func(k *apiKey) bool { return k.KeyID == r.PathValue("id") })
+	if key == nil {
+		notFound(w, "API key", r.PathValue("id"))
+		return
+	}
+	key.Status = "revoked"
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func bearer(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
@@ -0,0 +1,298 @@
+// Package clienttest provides an in-memory fake of the ToneClone API for
+// testing code that uses the client or the CLI, without an account or
+// network access.
+package clienttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+// DefaultAPIKey is the key accepted by a server with no keys configured
+const DefaultAPIKey = "tc_test_mockserver"
+
+// Server is a fake ToneClone API keeping its state in memory. It implements
+// the endpoints the client uses and can add latency, random errors and
+// rate limits.
+type Server struct {
+	latency   time.Duration
+	errorRate float64
+	rateLimit int
+	window    time.Duration
+	apiKeys   []string
+	empty     bool
+	log       io.Writer
+
+	mux *http.ServeMux
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	// Faults queued with FailNext
+	failures []int
+	// Requests per key in the current rate limit window
+	windowStart time.Time
+	windowCount map[string]int
+
+	requests []Request
+	state    *state
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	APIKey string
+	Status int
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithLatency delays every response by d
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// WithErrorRate fails the given fraction of requests, from 0 to 1, with a
+// 500 error
+func WithErrorRate(rate float64) Option {
+	return func(s *Server) {
+		s.errorRate = rate
+	}
+}
+
+// WithSeed makes random errors repeatable
+func WithSeed(seed int64) Option {
+	return func(s *Server) {
+		s.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithRateLimit allows each API key limit requests per window, a minute by
+// default, and answers the rest with 429 and a Retry-After header
+func WithRateLimit(limit int, window time.Duration) Option {
+	return func(s *Server) {
+		if window <= 0 {
+			window = time.Minute
+		}
+		s.rateLimit = limit
+		s.window = window
+	}
+}
+
+// WithAPIKeys accepts only the given API keys. By default any key starting
+// with tc_ is accepted.
+func WithAPIKeys(keys ...string) Option {
+	return func(s *Server) {
+		s.apiKeys = keys
+	}
+}
+
+// WithRequestLog writes a line per request to w
+func WithRequestLog(w io.Writer) Option {
+	return func(s *Server) {
+		s.log = w
+	}
+}
+
+// WithoutSeedData starts the server with no built-in personas
+func WithoutSeedData() Option {
+	return func(s *Server) {
+		s.empty = true
+	}
+}
+
+// New returns a fake API server. Serve it with http.ListenAndServe or use
+// NewTestServer in tests.
+func New(options ...Option) *Server {
+	s := &Server{
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		windowCount: make(map[string]int),
+		mux:         http.NewServeMux(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	s.state = newState(!s.empty)
+	s.routes()
+	return s
+}
+
+// NewTestServer starts a fake API server for a test and returns it with
+// its base URL. The server is closed when the test ends.
+func NewTestServer(t testing.TB, options ...Option) (*Server, string) {
+	t.Helper()
+	s := New(options...)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server.URL
+}
+
+// NewClient returns an API client for the fake server at baseURL, using
+// DefaultAPIKey
+func NewClient(baseURL string, options ...client.ClientOption) *client.ToneCloneClient {
+	options = append([]client.ClientOption{client.WithBaseURL(baseURL)}, options...)
+	return client.NewToneCloneClient(DefaultAPIKey, options...)
+}
+
+// FailNext answers the next count requests with status, before any other
+// checks. Useful for testing retries and error handling.
+func (s *Server) FailNext(status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < count; i++ {
+		s.failures = append(s.failures, status)
+	}
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ServeHTTP applies latency, faults, authentication and rate limits, then
+// routes the request to the fake endpoints
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	apiKey := bearer(r)
+	start := time.Now()
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, APIKey: apiKey, Status: recorder.status})
+		if s.log != nil {
+			fmt.Fprintf(s.log, "%s %s %s %d (%s)\n", start.Format("15:04:05"), r.Method, r.URL.Path, recorder.status, time.Since(start).Round(time.Millisecond))
+		}
+	}()
+
+	if s.latency > 0 {
+		select {
+		case <-time.After(s.latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status, failed := s.injectFault(); failed {
+		writeError(recorder, status, "injected_error", "fault injected by the mock server")
+		return
+	}
+
+	// Health checks need no key
+	if r.URL.Path != "/ping" {
+		if !s.validKey(apiKey) {
+			writeError(recorder, http.StatusUnauthorized, "unauthorized", "invalid or missing API key")
+			return
+		}
+		if !s.allow(recorder, apiKey) {
+			return
+		}
+	}
+
+	s.mux.ServeHTTP(recorder, r)
+}
+
+// injectFault returns the status of a queued or random failure
+func (s *Server) injectFault() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		return status, true
+	}
+	if s.errorRate > 0 && s.rng.Float64() < s.errorRate {
+		return http.StatusInternalServerError, true
+	}
+	return 0, false
+}
+
+// validKey accepts keys created through the API until they are revoked,
+// and otherwise the configured keys
+func (s *Server) validKey(secret string) bool {
+	s.state.mu.Lock()
+	known := find(s.state.keys, func(k *apiKey) bool { return k.secret == secret })
+	s.state.mu.Unlock()
+	if known != nil {
+		return known.Status == "active"
+	}
+
+	if len(s.apiKeys) == 0 {
+		return strings.HasPrefix(secret, "tc_")
+	}
+	for _, key := range s.apiKeys {
+		if key == secret {
+			return true
+		}
+	}
+	return false
+}
+
+// allow counts a request against the key's rate limit and answers 429 once
+// it is used up
+func (s *Server) allow(w http.ResponseWriter, apiKey string) bool {
+	if s.rateLimit <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.window {
+		s.windowStart = now
+		s.windowCount = make(map[string]int)
+	}
+	s.windowCount[apiKey]++
+	remaining := s.rateLimit - s.windowCount[apiKey]
+	reset := s.windowStart.Add(s.window)
+	s.mu.Unlock()
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(remaining, 0)))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if remaining >= 0 {
+		return true
+	}
+
+	retryAfter := int(time.Until(reset).Seconds() + 0.999)
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	writeError(w, http.StatusTooManyRequests, "rate_limited", fmt.Sprintf("limit of %d requests per %s exceeded", s.rateLimit, s.window))
+	return false
+}
+
+// statusRecorder remembers the status written for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, client.ErrorResponse{ErrorMsg: code, Message: message})
+}
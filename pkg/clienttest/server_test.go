@@ -0,0 +1,227 @@
+package clienttest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toneclone/cli/pkg/client"
+)
+
+func TestPersonaTrainingFlow(t *testing.T) {
+	server, url := NewTestServer(t)
+	api := NewClient(url)
+	ctx := context.Background()
+
+	if err := api.ValidateConnection(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	user, err := api.WhoAmI(ctx)
+	if err != nil || user.Email == "" {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+
+	builtIn, err := api.Personas.ListBuiltIn(ctx)
+	if err != nil || len(builtIn) == 0 {
+		t.Fatalf("Expected built-in personas, got %v (%v)", builtIn, err)
+	}
+
+	persona, err := api.Personas.Create(ctx, &client.Persona{Name: "Blogger"})
+	if err != nil {
+		t.Fatalf("Failed to create persona: %v", err)
+	}
+	if persona.PersonaID == "" || persona.TrainingStatus != "untrained" {
+		t.Errorf("Unexpected persona: %+v", persona)
+	}
+
+	text, err := api.Training.UploadText(ctx, &client.UploadTextRequest{Content: "Hello there", Filename: "hello.txt"})
+	if err != nil {
+		t.Fatalf("Failed to upload text: %v", err)
+	}
+	batch, err := api.Training.UploadFileBatch(ctx, []client.FileUpload{
+		{Filename: "a.md", Reader: strings.NewReader("# A")},
+		{Filename: "b.md", Reader: strings.NewReader("# B")},
+	}, persona.PersonaID, "cli")
+	if err != nil {
+		t.Fatalf("Failed to upload batch: %v", err)
+	}
+	if batch.Summary.Uploaded != 2 || batch.Summary.Associated != 2 {
+		t.Errorf("Unexpected batch summary: %+v", batch.Summary)
+	}
+	for _, result := range batch.Files {
+		if result.Status != "success" {
+			t.Errorf("Expected success for %s, got %q", result.Filename, result.Status)
+		}
+	}
+	if err := api.Personas.AssociateFiles(ctx, persona.PersonaID, []string{text.FileID}); err != nil {
+		t.Fatalf("Failed to associate file: %v", err)
+	}
+
+	files, err := api.Personas.ListFiles(ctx, persona.PersonaID)
+	if err != nil || len(files) != 3 {
+		t.Fatalf("Expected 3 persona files, got %d (%v)", len(files), err)
+	}
+
+	job, err := api.Training.CreatePersonaTrainingJob(ctx, persona.PersonaID)
+	if err != nil {
+		t.Fatalf("Failed to start training: %v", err)
+	}
+
+	// Jobs advance each time they are read
+	for i := 0; i < 10 && job.Status != "completed"; i++ {
+		if job, err = api.Training.GetJob(ctx, job.JobID); err != nil {
+			t.Fatalf("Failed to get job: %v", err)
+		}
+	}
+	if job.Status != "completed" || job.FilesProcessed != 3 {
+		t.Errorf("Expected completed job, got %+v", job)
+	}
+	if trained, _ := api.Personas.Get(ctx, persona.PersonaID); trained.TrainingStatus != "trained" {
+		t.Errorf("Expected trained persona, got %q", trained.TrainingStatus)
+	}
+	if len(server.Jobs()) != 1 {
+		t.Errorf("Expected 1 job on the server, got %d", len(server.Jobs()))
+	}
+}
+
+func TestProfilesAndGeneration(t *testing.T) {
+	server, url := NewTestServer(t)
+	api := NewClient(url)
+	ctx := context.Background()
+
+	persona := server.AddPersona("Support")
+	profile, err := api.Profiles.Create(ctx, &client.Profile{Name: "Email", Instructions: "Be brief"})
+	if err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+	if err := api.Profiles.AssociateWithPersona(ctx, profile.ProfileID, persona.PersonaID); err != nil {
+		t.Fatalf("Failed to associate profile: %v", err)
+	}
+	profiles, err := api.Profiles.GetPersonaProfiles(ctx, persona.PersonaID)
+	if err != nil || len(profiles) != 1 || profiles[0].Name != "Email" {
+		t.Fatalf("Unexpected persona profiles: %v (%v)", profiles, err)
+	}
+
+	response, err := api.Generate.Text(ctx, &client.GenerateTextRequest{
+		Prompt:     "Reply to the customer",
+		PersonaID:  persona.PersonaID,
+		ProfileIDs: []string{profile.ProfileID},
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate text: %v", err)
+	}
+	expected := "Mock response in the voice of Support following Email: Reply to the customer"
+	if response.Text != expected {
+		t.Errorf("Expected %q, got %q", expected, response.Text)
+	}
+
+	_, err = api.Generate.Text(ctx, &client.GenerateTextRequest{Prompt: "Hi", PersonaID: "persona-404"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	if err := api.Profiles.Delete(ctx, profile.ProfileID); err != nil {
+		t.Fatalf("Failed to delete profile: %v", err)
+	}
+	if profiles, _ := api.Profiles.GetPersonaProfiles(ctx, persona.PersonaID); len(profiles) != 0 {
+		t.Errorf("Expected deleted profile to be disassociated, got %v", profiles)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	_, url := NewTestServer(t, WithAPIKeys("tc_test_onlyme"))
+	ctx := context.Background()
+
+	if err := NewClient(url).ValidateAPIKey(ctx); err == nil {
+		t.Error("Expected the default key to be rejected")
+	}
+
+	api := client.NewToneCloneClient("tc_test_onlyme", client.WithBaseURL(url))
+	current, err := api.APIKeys.Current(ctx)
+	if err != nil || current.Status != "active" {
+		t.Fatalf("Unexpected current key: %+v (%v)", current, err)
+	}
+
+	created, err := api.APIKeys.Create(ctx, &client.CreateAPIKeyRequest{Name: "ci", Scopes: []client.APIKeyScope{client.ScopePersonasRead}})
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	ci := client.NewToneCloneClient(created.Key, client.WithBaseURL(url))
+	if err := ci.ValidateAPIKey(ctx); err != nil {
+		t.Errorf("Expected created key to work: %v", err)
+	}
+
+	if err := api.APIKeys.Revoke(ctx, created.KeyID); err != nil {
+		t.Fatalf("Failed to revoke key: %v", err)
+	}
+	if err := ci.ValidateAPIKey(ctx); err == nil {
+		t.Error("Expected revoked key to be rejected")
+	}
+}
+
+func TestFaults(t *testing.T) {
+	server, url := NewTestServer(t)
+	api := NewClient(url)
+	ctx := context.Background()
+
+	server.FailNext(http.StatusBadGateway, 1)
+	if _, err := api.Personas.List(ctx); err == nil {
+		t.Error("Expected injected error")
+	}
+	if _, err := api.Personas.List(ctx); err != nil {
+		t.Errorf("Expected only one failure, got %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || requests[0].Status != http.StatusBadGateway || requests[1].Path != "/personas" {
+		t.Errorf("Unexpected request log: %+v", requests)
+	}
+
+	_, url = NewTestServer(t, WithErrorRate(1), WithSeed(1))
+	if err := NewClient(url).Ping(ctx); err == nil {
+		t.Error("Expected every request to fail")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	_, url := NewTestServer(t, WithRateLimit(2, time.Minute))
+
+	get := func() *http.Response {
+		req, _ := http.NewRequest("GET", url+"/personas", nil)
+		req.Header.Set("Authorization", "Bearer "+DefaultAPIKey)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	for i := 0; i < 2; i++ {
+		if resp := get(); resp.StatusCode != http.StatusOK {
+			t.Fatalf("Request %d: expected 200, got %d", i, resp.StatusCode)
+		}
+	}
+
+	resp := get()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected rate limit headers, got %v", resp.Header)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	_, url := NewTestServer(t, WithLatency(50*time.Millisecond))
+
+	start := time.Now()
+	if err := NewClient(url).Ping(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected at least 50ms latency, took %v", elapsed)
+	}
+}
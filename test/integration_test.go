@@ -2,63 +2,120 @@ package test
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/toneclone/cli/internal/config"
 	"github.com/toneclone/cli/pkg/client"
+	"github.com/toneclone/cli/pkg/clienttest"
 )
 
-// TestCLIIntegration tests the basic CLI integration flow
+// TestCLIIntegration tests the basic CLI integration flow against the fake API
 func TestCLIIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	// Create temporary config for testing
+	server, baseURL := clienttest.NewTestServer(t)
+
+	// Isolate the config from the user's own files
 	tmpDir := t.TempDir()
-	configPath := tmpDir + "/test-config.yaml"
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("TONECLONE_SYSTEM_CONFIG", filepath.Join(tmpDir, "system.yaml"))
+	t.Setenv("TONECLONE_API_KEY", "")
 
 	// Test configuration creation
 	cfg := config.NewConfig()
-	cfg.AddKey("test", "test_key", "https://api.toneclone.ai")
+	cfg.AddKey("test", clienttest.DefaultAPIKey, baseURL)
 
-	err := cfg.SaveConfig(configPath)
+	configPath, err := config.GetConfigPath()
 	if err != nil {
+		t.Fatalf("Failed to get config path: %v", err)
+	}
+	if err := cfg.SaveConfig(configPath); err != nil {
 		t.Fatalf("Failed to save test config: %v", err)
 	}
 
 	// Test configuration loading
 	loadedCfg, err := config.LoadConfig()
 	if err != nil {
-		// This is expected if no config exists, create a minimal one
-		loadedCfg = config.NewConfig()
+		t.Fatalf("Failed to load test config: %v", err)
 	}
 
 	// Test client creation from config
-	if len(loadedCfg.Keys) > 0 {
-		currentKey, err := loadedCfg.GetCurrentKey()
-		if err != nil {
-			t.Fatalf("Failed to get current key: %v", err)
-		}
+	currentKey, err := loadedCfg.GetCurrentKey()
+	if err != nil {
+		t.Fatalf("Failed to get current key: %v", err)
+	}
+	if currentKey.BaseURL != baseURL {
+		t.Fatalf("Expected base URL %s, got %s", baseURL, currentKey.BaseURL)
+	}
+
+	apiClient := client.NewToneCloneClientFromConfig(
+		currentKey.BaseURL,
+		currentKey.Key,
+		time.Duration(currentKey.Timeout)*time.Second,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := apiClient.ValidateConnection(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if err := apiClient.ValidateAPIKey(ctx); err != nil {
+		t.Fatalf("Failed to validate API key: %v", err)
+	}
+
+	// Create a persona, train it on a file and write with it
+	persona, err := apiClient.Personas.Create(ctx, &client.Persona{Name: "Integration"})
+	if err != nil {
+		t.Fatalf("Failed to create persona: %v", err)
+	}
 
-		apiClient := client.NewToneCloneClientFromConfig(
-			currentKey.BaseURL,
-			currentKey.Key,
-			time.Duration(currentKey.Timeout)*time.Second,
-		)
+	file, err := apiClient.Training.UploadText(ctx, &client.UploadTextRequest{
+		Content:  "Thanks for reaching out, happy to help.",
+		Filename: "sample.txt",
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload text: %v", err)
+	}
+	if err := apiClient.Personas.AssociateFiles(ctx, persona.PersonaID, []string{file.FileID}); err != nil {
+		t.Fatalf("Failed to associate file: %v", err)
+	}
 
-		if apiClient == nil {
-			t.Fatal("Failed to create API client")
+	job, err := apiClient.Training.CreatePersonaTrainingJob(ctx, persona.PersonaID)
+	if err != nil {
+		t.Fatalf("Failed to start training: %v", err)
+	}
+	for i := 0; i < 10 && job.Status != "completed"; i++ {
+		if job, err = apiClient.Training.GetJob(ctx, job.JobID); err != nil {
+			t.Fatalf("Failed to get training job: %v", err)
 		}
+	}
+	if job.Status != "completed" {
+		t.Fatalf("Expected training to complete, got %s", job.Status)
+	}
 
-		// Test basic connectivity (will fail with test_key but should not crash)
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	response, err := apiClient.Generate.Text(ctx, &client.GenerateTextRequest{
+		Prompt:    "Reply to the customer",
+		PersonaID: persona.PersonaID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate text: %v", err)
+	}
+	if !strings.Contains(response.Text, "Integration") {
+		t.Errorf("Expected a response in the persona's voice, got %q", response.Text)
+	}
 
-		// This will likely fail with authentication but should not crash
-		_ = apiClient.ValidateConnection(ctx)
+	// Errors from the API surface as errors
+	server.FailNext(http.StatusServiceUnavailable, 1)
+	if _, err := apiClient.Personas.List(ctx); err == nil {
+		t.Error("Expected error for a failed request")
 	}
 }
 